@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// loadAllowlist reads section key patterns from filename, one per line.
+// Blank lines and lines starting with # are skipped. Patterns use glob
+// syntax, so "lang_*" allows every key starting with "lang_".
+func loadAllowlist(filename string) ([]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var patterns []string
+	scanner := bufio.NewScanner(bytes.NewReader(config.StripBOM(data)))
+	for line := 1; scanner.Scan(); line++ {
+		pattern := strings.TrimSpace(scanner.Text())
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+		_, err := path.Match(pattern, "")
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid pattern %q: %w", filename, line, pattern, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, scanner.Err()
+}
+
+// disallowedSections returns the sorted keys of sections that match none of
+// patterns
+func disallowedSections(sections map[string]config.Section, patterns []string) []string {
+	var disallowed []string
+	for key := range sections {
+		if !matchesAny(key, patterns) {
+			disallowed = append(disallowed, key)
+		}
+	}
+	sort.Strings(disallowed)
+	return disallowed
+}
+
+// missingSections returns the required keys, in the order given, whose
+// section is absent or has only whitespace content
+func missingSections(sections map[string]config.Section, required []string) []string {
+	var missing []string
+	for _, key := range required {
+		section, ok := sections[key]
+		if !ok || strings.TrimSpace(section.Content) == "" {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// splitKeys splits a comma-separated flag value into trimmed, non-empty keys
+func splitKeys(value string) []string {
+	var keys []string
+	for _, key := range strings.Split(value, ",") {
+		key = strings.TrimSpace(key)
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// matchesAny reports whether key matches one of patterns
+func matchesAny(key string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadAllowlist(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "allowed.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# governance list\n\ntesting\n  lang_*  \r\n"), 0644))
+
+	patterns, err := loadAllowlist(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"testing", "lang_*"}, patterns)
+
+	bad := filepath.Join(dir, "bad.txt")
+	require.NoError(t, os.WriteFile(bad, []byte("testing\nlang_[\n"), 0644))
+	_, err = loadAllowlist(bad)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad.txt:2: invalid pattern \"lang_[\"")
+
+	_, err = loadAllowlist(filepath.Join(dir, "missing.txt"))
+	assert.Error(t, err)
+}
+
+func TestDisallowedSections(t *testing.T) {
+	sections := map[string]config.Section{
+		"testing":  {},
+		"lang_go":  {},
+		"secrets":  {},
+		"appendix": {},
+	}
+
+	assert.Equal(t, []string{"appendix", "secrets"}, disallowedSections(sections, []string{"testing", "lang_*"}))
+	assert.Empty(t, disallowedSections(sections, []string{"*"}))
+	assert.Len(t, disallowedSections(sections, nil), 4)
+}
+
+func TestMissingSections(t *testing.T) {
+	sections := map[string]config.Section{
+		"overview": {Content: "## Overview"},
+		"security": {Content: "  \n"},
+		"testing":  {Content: "## Testing"},
+	}
+
+	assert.Equal(t, []string{"security", "deploy"}, missingSections(sections, []string{"overview", "security", "deploy", "testing"}))
+	assert.Empty(t, missingSections(sections, []string{"overview", "testing"}))
+	assert.Empty(t, missingSections(sections, nil))
+}
+
+func TestSplitKeys(t *testing.T) {
+	assert.Equal(t, []string{"overview", "security", "testing"}, splitKeys(" overview, security ,,testing,"))
+	assert.Empty(t, splitKeys(""))
+}
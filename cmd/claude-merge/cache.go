@@ -0,0 +1,161 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// cacheFormat is bumped whenever the cache layout or fingerprint inputs
+// change, so older caches are ignored rather than misread
+const cacheFormat = 3
+
+// defaultCacheFile is the cache file of a bare -cache
+const defaultCacheFile = ".claude-merge-cache"
+
+// cacheFlag is the -cache file name. Given bare, or as true, it names
+// defaultCacheFile; -cache=<file> names another file.
+type cacheFlag string
+
+// String returns the file name
+func (f *cacheFlag) String() string {
+	return string(*f)
+}
+
+// Set sets the file name, mapping true and false to the default and to none
+func (f *cacheFlag) Set(value string) error {
+	switch value {
+	case "true":
+		*f = defaultCacheFile
+	case "false":
+		*f = ""
+	default:
+		*f = cacheFlag(value)
+	}
+	return nil
+}
+
+// IsBoolFlag lets -cache be given without a value
+func (f *cacheFlag) IsBoolFlag() bool {
+	return true
+}
+
+// buildCache is the -cache file: the fingerprint of everything that shaped
+// the last output, and the output itself
+type buildCache struct {
+	Format      int    `json:"format"`
+	Fingerprint string `json:"fingerprint"`
+	OutputFile  string `json:"output_file"`
+	Output      string `json:"output"`
+}
+
+// cacheInputs is everything hashed into a cache fingerprint. Configs are
+// hashed after priority tiers and overrides are applied, so a change to the
+// priority, order, or content of any section, or to the set and order of
+// inputs, changes the fingerprint. Sequences holds the section keys of each
+// config in Seq order, which JSON leaves out, so moving a section within
+// its file changes the fingerprint too.
+type cacheInputs struct {
+	Format    int               `json:"format"`
+	Version   string            `json:"version"`
+	Args      []string          `json:"args"`
+	Configs   []*config.Config  `json:"configs"`
+	Sequences [][]string        `json:"sequences"`
+	Defaults  *config.Config    `json:"defaults,omitempty"`
+	Files     map[string]string `json:"files,omitempty"`
+}
+
+// newBuildCache fingerprints a run. args are the command-line arguments and
+// files names other files whose content shapes the output, such as the
+// -sections-from reference; empty names are skipped.
+func newBuildCache(version string, args []string, configs []*config.Config, defaults *config.Config, files []string) (*buildCache, error) {
+	inputs := cacheInputs{
+		Format:   cacheFormat,
+		Version:  version,
+		Args:     args,
+		Configs:  configs,
+		Defaults: defaults,
+		Files:    make(map[string]string),
+	}
+	for _, cfg := range append(append([]*config.Config{}, configs...), defaults) {
+		if cfg != nil {
+			inputs.Sequences = append(inputs.Sequences, seqOrder(cfg.Sections))
+		}
+	}
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		inputs.Files[file] = hashBytes(data)
+	}
+
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &buildCache{Format: cacheFormat, Fingerprint: hashBytes(data)}, nil
+}
+
+// seqOrder returns the keys of sections by Seq, then by key
+func seqOrder(sections map[string]config.Section) []string {
+	keys := make([]string, 0, len(sections))
+	for key := range sections {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := sections[keys[i]].Seq, sections[keys[j]].Seq
+		if a != b {
+			return a < b
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// loadBuildCache reads a cache file. A missing file, or one written in
+// another format, yields nil without an error.
+func loadBuildCache(filename string) (*buildCache, error) {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cache buildCache
+	err = json.Unmarshal(data, &cache)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	if cache.Format != cacheFormat {
+		return nil, nil
+	}
+	return &cache, nil
+}
+
+// save writes the cache to filename
+func (c *buildCache) save(filename string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// hashBytes returns the hex SHA-256 of data
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cacheConfigs() []*config.Config {
+	return []*config.Config{
+		{SourceFile: "common.md", Sections: map[string]config.Section{"intro": {Order: 1, Content: "# Intro"}}},
+		{SourceFile: "go.md", Sections: map[string]config.Section{"testing": {Order: 2, Content: "## Testing"}}},
+	}
+}
+
+func TestNewBuildCache_Fingerprint(t *testing.T) {
+	args := []string{"-files", "common.md,go.md"}
+	base, err := newBuildCache("1.0.0", args, cacheConfigs(), nil, nil)
+	require.NoError(t, err)
+
+	same, err := newBuildCache("1.0.0", args, cacheConfigs(), nil, []string{""})
+	require.NoError(t, err)
+	assert.Equal(t, base.Fingerprint, same.Fingerprint)
+
+	changes := map[string]func(configs []*config.Config) ([]*config.Config, []string, string){
+		"content": func(c []*config.Config) ([]*config.Config, []string, string) {
+			c[0].Sections["intro"] = config.Section{Order: 1, Content: "# Welcome"}
+			return c, args, "1.0.0"
+		},
+		"priority": func(c []*config.Config) ([]*config.Config, []string, string) {
+			c[1].Sections["testing"] = config.Section{Order: 2, Content: "## Testing", Priority: config.NewExplicitPriority(5)}
+			return c, args, "1.0.0"
+		},
+		"order": func(c []*config.Config) ([]*config.Config, []string, string) {
+			c[1].Sections["testing"] = config.Section{Order: 0, Content: "## Testing"}
+			return c, args, "1.0.0"
+		},
+		"input order": func(c []*config.Config) ([]*config.Config, []string, string) {
+			return []*config.Config{c[1], c[0]}, args, "1.0.0"
+		},
+		"input set": func(c []*config.Config) ([]*config.Config, []string, string) {
+			return c[:1], args, "1.0.0"
+		},
+		"metadata priority": func(c []*config.Config) ([]*config.Config, []string, string) {
+			c[0].Metadata.Priority = config.NewExplicitPriority(1)
+			return c, args, "1.0.0"
+		},
+		"flags": func(c []*config.Config) ([]*config.Config, []string, string) {
+			return c, append(args, "-section-gap", "2"), "1.0.0"
+		},
+		"version": func(c []*config.Config) ([]*config.Config, []string, string) {
+			return c, args, "1.1.0"
+		},
+	}
+	for name, change := range changes {
+		t.Run(name, func(t *testing.T) {
+			configs, args, version := change(cacheConfigs())
+			changed, err := newBuildCache(version, args, configs, nil, nil)
+			require.NoError(t, err)
+			assert.NotEqual(t, base.Fingerprint, changed.Fingerprint)
+		})
+	}
+}
+
+func TestNewBuildCache_SectionSequence(t *testing.T) {
+	written := func(alpha, beta int64) []*config.Config {
+		return []*config.Config{{SourceFile: "rules.toml", Sections: map[string]config.Section{
+			"alpha": {Content: "Alpha", Seq: alpha},
+			"beta":  {Content: "Beta", Seq: beta},
+		}}}
+	}
+
+	before, err := newBuildCache("", nil, written(1, 2), nil, nil)
+	require.NoError(t, err)
+	swapped, err := newBuildCache("", nil, written(2, 1), nil, nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, before.Fingerprint, swapped.Fingerprint, "moving a section within its file rebuilds")
+
+	again, err := newBuildCache("", nil, written(1, 2), nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, before.Fingerprint, again.Fingerprint)
+}
+
+func TestNewBuildCache_Files(t *testing.T) {
+	reference := filepath.Join(t.TempDir(), "outline.md")
+	require.NoError(t, os.WriteFile(reference, []byte("# One"), 0644))
+	before, err := newBuildCache("", nil, cacheConfigs(), nil, []string{reference})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(reference, []byte("# Two"), 0644))
+	after, err := newBuildCache("", nil, cacheConfigs(), nil, []string{reference})
+	require.NoError(t, err)
+	assert.NotEqual(t, before.Fingerprint, after.Fingerprint)
+
+	_, err = newBuildCache("", nil, cacheConfigs(), nil, []string{reference + ".missing"})
+	assert.Error(t, err)
+}
+
+func TestBuildCache_SaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, ".claude-merge-cache")
+
+	missing, err := loadBuildCache(filename)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	cache, err := newBuildCache("1.0.0", nil, cacheConfigs(), nil, nil)
+	require.NoError(t, err)
+	cache.OutputFile = "CLAUDE.md"
+	cache.Output = "# Intro"
+	require.NoError(t, cache.save(filename))
+
+	loaded, err := loadBuildCache(filename)
+	require.NoError(t, err)
+	assert.Equal(t, cache, loaded)
+
+	require.NoError(t, os.WriteFile(filename, []byte(`{"format": 99}`), 0644))
+	old, err := loadBuildCache(filename)
+	require.NoError(t, err)
+	assert.Nil(t, old)
+
+	require.NoError(t, os.WriteFile(filename, []byte("not json"), 0644))
+	_, err = loadBuildCache(filename)
+	assert.Error(t, err)
+}
+
+func TestCacheFlag(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"true", defaultCacheFile},
+		{"false", ""},
+		{"build/cache.json", "build/cache.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			var f cacheFlag
+			require.NoError(t, f.Set(tt.value))
+			assert.Equal(t, tt.want, f.String())
+		})
+	}
+	assert.True(t, new(cacheFlag).IsBoolFlag())
+}
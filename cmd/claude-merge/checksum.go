@@ -0,0 +1,27 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// checksumLine returns the sha256sum line for data written to filename: the
+// hex digest, two spaces, and the file's base name, so `sha256sum -c` run
+// next to the file verifies it
+func checksumLine(filename string, data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]) + "  " + filepath.Base(filename) + "\n"
+}
+
+// writeChecksum writes the checksum of data, the exact bytes written to
+// filename, to filename.sha256
+func writeChecksum(filename string, data []byte) error {
+	err := os.WriteFile(filename+".sha256", []byte(checksumLine(filename, data)), 0644)
+	if err != nil {
+		return withExitCode(exitWrite, fmt.Errorf("Failed to write checksum: %w", err))
+	}
+	return nil
+}
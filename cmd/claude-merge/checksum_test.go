@@ -0,0 +1,29 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksumLine(t *testing.T) {
+	assert.Equal(t,
+		"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824  CLAUDE.md\n",
+		checksumLine(filepath.Join("out", "CLAUDE.md"), []byte("hello")))
+}
+
+func TestWriteOutput_Checksum(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "CLAUDE.md")
+
+	require.NoError(t, writeOutput(filename, "# Guide", false, true, true))
+
+	written, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	sidecar, err := os.ReadFile(filename + ".sha256")
+	require.NoError(t, err)
+	assert.Equal(t, checksumLine(filename, written), string(sidecar), "the checksum covers the BOM")
+	assert.NotEqual(t, checksumLine(filename, []byte("# Guide")), string(sidecar))
+}
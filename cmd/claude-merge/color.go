@@ -0,0 +1,50 @@
+package main
+
+import "os"
+
+// ANSI escape sequences for the colors used in console messages
+const (
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiReset  = "\x1b[0m"
+)
+
+// colorizer colors console messages when enabled. Only messages are ever
+// colored, never generated output.
+type colorizer bool
+
+// Colorizers for stdout and stderr, set up by run once flags are parsed
+var (
+	stdoutColor colorizer
+	stderrColor colorizer
+)
+
+// newColorizer enables color for f only when it is a terminal, disabled is
+// false, and the NO_COLOR environment variable is unset or empty
+func newColorizer(f *os.File, disabled bool) colorizer {
+	return colorizer(!disabled && os.Getenv("NO_COLOR") == "" && isTerminal(f))
+}
+
+// wrap surrounds text with the color code when enabled
+func (c colorizer) wrap(code, text string) string {
+	if !c {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// check returns the success check mark, in green
+func (c colorizer) check() string {
+	return c.wrap(ansiGreen, "✓")
+}
+
+// warning returns the "Warning:" prefix, in yellow
+func (c colorizer) warning() string {
+	return c.wrap(ansiYellow, "Warning:")
+}
+
+// error returns an error message, in red
+func (c colorizer) error(text string) string {
+	return c.wrap(ansiRed, text)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorizer(t *testing.T) {
+	on := colorizer(true)
+	assert.Equal(t, "\x1b[32m✓\x1b[0m", on.check())
+	assert.Equal(t, "\x1b[33mWarning:\x1b[0m", on.warning())
+	assert.Equal(t, "\x1b[31mfailed\x1b[0m", on.error("failed"))
+
+	off := colorizer(false)
+	assert.Equal(t, "✓", off.check())
+	assert.Equal(t, "Warning:", off.warning())
+	assert.Equal(t, "failed", off.error("failed"))
+}
+
+func TestNewColorizer_NotTerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer r.Close()
+	defer w.Close()
+
+	t.Setenv("NO_COLOR", "")
+	assert.False(t, bool(newColorizer(w, false)), "pipes are never colored")
+
+	file, err := os.CreateTemp(t.TempDir(), "out")
+	require.NoError(t, err)
+	defer file.Close()
+	assert.False(t, bool(newColorizer(file, false)), "redirected files are never colored")
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// defaultConventionBase is the base file -convention looks for when given a
+// directory
+const defaultConventionBase = "COMMON.md"
+
+// conventionFiles returns the base file followed by its sibling fragments
+// matching pattern, in name order. path is either the base file itself or a
+// directory holding a COMMON.md base.
+func conventionFiles(path, pattern string) ([]string, error) {
+	_, err := filepath.Match(pattern, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid convention pattern %q: %w", pattern, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("convention base not found: %w", err)
+	}
+
+	base := path
+	if info.IsDir() {
+		base = filepath.Join(path, defaultConventionBase)
+		_, err = os.Stat(base)
+		if err != nil {
+			return nil, fmt.Errorf("no %s in %s; pass the base file instead", defaultConventionBase, path)
+		}
+	}
+
+	fragments, err := filepath.Glob(filepath.Join(filepath.Dir(base), pattern))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(fragments)
+
+	files := []string{base}
+	for _, fragment := range fragments {
+		info, err := os.Stat(fragment)
+		if err != nil || info.IsDir() || canonicalPath(fragment) == canonicalPath(base) {
+			continue
+		}
+		files = append(files, fragment)
+	}
+	return files, nil
+}
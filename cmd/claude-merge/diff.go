@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/arustydev/claude-merge/internal/generator"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// outputDiff returns a unified diff from the current contents of path, if
+// any, to output. Timestamp footers are left out on both sides so a run that
+// only refreshes the timestamp shows no change.
+func outputDiff(path, output string) (string, error) {
+	fromFile := diffLabel("a", path)
+	previous, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		fromFile = "/dev/null"
+	} else if err != nil {
+		return "", fmt.Errorf("failed to read previous output: %w", err)
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        diffLines(generator.StripTimestampFooter(string(config.StripBOM(previous)))),
+		B:        diffLines(generator.StripTimestampFooter(output)),
+		FromFile: fromFile,
+		ToFile:   diffLabel("b", path),
+		Context:  3,
+	})
+}
+
+// diffLines splits text into newline-terminated lines, as the diff expects
+func diffLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	lines := strings.SplitAfter(text, "\n")
+	return lines[:len(lines)-1]
+}
+
+// diffLabel names path in a diff header, with git's a/ and b/ prefixes for
+// relative paths so the diff applies with git apply
+func diffLabel(prefix, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return prefix + "/" + filepath.ToSlash(path)
+}
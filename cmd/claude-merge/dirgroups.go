@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/arustydev/claude-merge/internal/merger"
+)
+
+// dirGroup is one topic subdirectory found by -dir and its input files
+type dirGroup struct {
+	Name  string
+	Files []string
+}
+
+// dirGroups returns a group for each immediate subdirectory of dir, in name
+// order, holding the subdirectory's config files in name order. Files
+// directly in dir, files with an unsupported extension, nested directories,
+// and subdirectories without config files are skipped.
+func dirGroups(dir string) ([]dirGroup, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []dirGroup
+	for _, entry := range entries {
+		subdir := filepath.Join(dir, entry.Name())
+		info, err := os.Stat(subdir)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		files, err := os.ReadDir(subdir)
+		if err != nil {
+			return nil, err
+		}
+
+		group := dirGroup{Name: subdir}
+		for _, file := range files {
+			path := filepath.Join(subdir, file.Name())
+			_, err := config.DetectFormat(path)
+			if err != nil {
+				continue
+			}
+			info, err := os.Stat(path)
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			group.Files = append(group.Files, path)
+		}
+		if len(group.Files) > 0 {
+			sort.Strings(group.Files)
+			groups = append(groups, group)
+		}
+	}
+
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("no config files found in the subdirectories of %s", dir)
+	}
+	return groups, nil
+}
+
+// configGroups sorts the loaded configs into the merger groups matching
+// groups, by source file
+func configGroups(groups []dirGroup, configs []*config.Config) []merger.ConfigGroup {
+	groupOf := make(map[string]int)
+	for i, group := range groups {
+		for _, file := range group.Files {
+			groupOf[file] = i
+		}
+	}
+
+	result := make([]merger.ConfigGroup, len(groups))
+	for i, group := range groups {
+		result[i].Name = group.Name
+	}
+	for _, cfg := range configs {
+		i := groupOf[cfg.SourceFile]
+		result[i].Configs = append(result[i].Configs, cfg)
+	}
+	return result
+}
+
+// keepGroups leaves out the groups named in drop along with the configs
+// loaded from their files
+func keepGroups(groups []dirGroup, configs []*config.Config, drop func(name string) bool) ([]dirGroup, []*config.Config) {
+	var kept []dirGroup
+	dropped := make(map[string]bool)
+	for _, group := range groups {
+		if !drop(group.Name) {
+			kept = append(kept, group)
+			continue
+		}
+		for _, file := range group.Files {
+			dropped[file] = true
+		}
+	}
+
+	var keptConfigs []*config.Config
+	for _, cfg := range configs {
+		if !dropped[cfg.SourceFile] {
+			keptConfigs = append(keptConfigs, cfg)
+		}
+	}
+	return kept, keptConfigs
+}
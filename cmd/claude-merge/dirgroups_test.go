@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirGroups(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"testing/b.md",
+		"testing/a.toml",
+		"style/01.md",
+		"style/notes.txt",
+		"style/nested/deep.md",
+		"README.md",
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("# "+name), 0644))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "empty"), 0755))
+
+	groups, err := dirGroups(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []dirGroup{
+		{Name: filepath.Join(dir, "style"), Files: []string{filepath.Join(dir, "style", "01.md")}},
+		{Name: filepath.Join(dir, "testing"), Files: []string{filepath.Join(dir, "testing", "a.toml"), filepath.Join(dir, "testing", "b.md")}},
+	}, groups)
+
+	_, err = dirGroups(filepath.Join(dir, "empty"))
+	assert.ErrorContains(t, err, "no config files found")
+
+	_, err = dirGroups(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestConfigGroups(t *testing.T) {
+	groups := []dirGroup{
+		{Name: "style", Files: []string{"style/a.md"}},
+		{Name: "testing", Files: []string{"testing/a.json"}},
+	}
+	style := &config.Config{SourceFile: "style/a.md"}
+	first := &config.Config{SourceFile: "testing/a.json"}
+	second := &config.Config{SourceFile: "testing/a.json"}
+
+	result := configGroups(groups, []*config.Config{style, first, second})
+	require.Len(t, result, 2)
+	assert.Equal(t, "style", result[0].Name)
+	assert.Equal(t, []*config.Config{style}, result[0].Configs)
+	assert.Equal(t, "testing", result[1].Name)
+	assert.Equal(t, []*config.Config{first, second}, result[1].Configs)
+}
+
+func TestKeepGroups(t *testing.T) {
+	groups := []dirGroup{
+		{Name: "style", Files: []string{"style/a.md", "style/b.md"}},
+		{Name: "testing", Files: []string{"testing/a.md"}},
+	}
+	styleA := &config.Config{SourceFile: "style/a.md"}
+	testingA := &config.Config{SourceFile: "testing/a.md"}
+
+	kept, configs := keepGroups(groups, []*config.Config{styleA, testingA}, func(name string) bool {
+		return name == "style"
+	})
+	assert.Equal(t, groups[1:], kept)
+	assert.Equal(t, []*config.Config{testingA}, configs)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// Exit codes, one per failure category, so scripts can react to each
+const (
+	exitFailure    = 1 // any failure not covered below
+	exitUsage      = 2 // invalid flags or arguments
+	exitNotFound   = 3 // an input or referenced file could not be read
+	exitParse      = 4 // an input could not be parsed, or has an unknown format
+	exitValidation = 5 // an input parsed but its contents are invalid
+	exitWrite      = 6 // the output or a file beside it could not be written
+	exitPartial    = 7 // -continue-on-error wrote what it could, but some parts failed
+)
+
+// exitError attaches an exit code to an error
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode tags err with code
+func withExitCode(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
+// usageError reports invalid arguments. It exits with exitUsage unless err
+// already carries a more specific code, such as a missing input file.
+func usageError(err error) error {
+	err = fmt.Errorf("Invalid arguments: %w", err)
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return err
+	}
+	return withExitCode(exitUsage, err)
+}
+
+// exitCode returns the process exit code for an error returned by run. An
+// explicit code wins; otherwise a ConfigError anywhere in the chain decides.
+func exitCode(err error) int {
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+
+	var configErr *config.ConfigError
+	if errors.As(err, &configErr) {
+		switch configErr.Kind {
+		case config.KindNotFound:
+			return exitNotFound
+		case config.KindUnsupportedFormat, config.KindParseError:
+			return exitParse
+		case config.KindValidationError:
+			return exitValidation
+		}
+	}
+	return exitFailure
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitCode(t *testing.T) {
+	dir := t.TempDir()
+	badTOML := filepath.Join(dir, "bad.toml")
+	require.NoError(t, os.WriteFile(badTOML, []byte("[metadata\n"), 0644))
+	notes := filepath.Join(dir, "notes.txt")
+	require.NoError(t, os.WriteFile(notes, []byte("notes"), 0644))
+
+	loadErr := func(filename string) error {
+		_, err := config.LoadConfig(filename)
+		require.Error(t, err)
+		return fmt.Errorf("Failed to load config %s: %w", filename, err)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "plain error", err: errors.New("merge failed"), want: exitFailure},
+		{name: "usage", err: usageError(errors.New("no input files specified")), want: exitUsage},
+		{name: "missing input file", err: usageError(validateArgs([]string{filepath.Join(dir, "missing.md")}, "out.md")), want: exitNotFound},
+		{name: "unreadable config", err: loadErr(filepath.Join(dir, "missing.toml")), want: exitNotFound},
+		{name: "unsupported format", err: loadErr(notes), want: exitParse},
+		{name: "parse error", err: loadErr(badTOML), want: exitParse},
+		{name: "validation", err: config.ValidateConfig(&config.Config{}), want: exitValidation},
+		{name: "write", err: withExitCode(exitWrite, errors.New("Failed to write output")), want: exitWrite},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, exitCode(tt.err))
+		})
+	}
+}
+
+func TestUsageError_Message(t *testing.T) {
+	err := usageError(errors.New("output filename cannot be empty"))
+	assert.Equal(t, "Invalid arguments: output filename cannot be empty", err.Error())
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/arustydev/claude-merge/internal/generator"
+)
+
+// loadFenceAliases reads a YAML mapping of code fence language aliases to
+// canonical names from filename and returns the default aliases with the
+// file's entries added or replacing them. Aliases match case-insensitively,
+// and mapping an alias to itself keeps it as written.
+func loadFenceAliases(filename string) (map[string]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries map[string]string
+	err = yaml.Unmarshal(config.StripBOM(data), &entries)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	aliases := generator.DefaultFenceAliases()
+	for alias, language := range entries {
+		alias = strings.ToLower(strings.TrimSpace(alias))
+		language = strings.TrimSpace(language)
+		if alias == "" || language == "" || strings.ContainsAny(alias+language, " \t`") {
+			return nil, fmt.Errorf("%s: alias %q needs a language name without spaces or backticks", filename, alias)
+		}
+		aliases[alias] = language
+	}
+	return aliases, nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFenceAliases(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "aliases.yaml")
+	require.NoError(t, os.WriteFile(valid, []byte("\uFEFFSH: bash\npy: py\n"), 0644))
+	aliases, err := loadFenceAliases(valid)
+	require.NoError(t, err)
+	assert.Equal(t, "bash", aliases["sh"])
+	assert.Equal(t, "py", aliases["py"], "a file entry replaces the default")
+	assert.Equal(t, "go", aliases["golang"], "defaults the file leaves alone are kept")
+
+	for name, data := range map[string]string{
+		"empty.yaml":    "sh: \"\"\n",
+		"spaces.yaml":   "sh: bourne shell\n",
+		"backtick.yaml": "sh: \"`bash\"\n",
+		"invalid.yaml":  "- sh\n",
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+		_, err = loadFenceAliases(path)
+		assert.Error(t, err, name)
+	}
+
+	_, err = loadFenceAliases(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
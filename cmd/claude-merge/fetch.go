@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// fetchBackoff is the wait before the first retry of a failed fetch; each
+// later retry waits twice as long
+const fetchBackoff = 500 * time.Millisecond
+
+// fetcher downloads remote configs, retrying transient failures: connection
+// errors and 5xx responses. Other responses, such as 404, fail at once.
+type fetcher struct {
+	client *http.Client
+
+	// retries is how many times a failed fetch is tried again
+	retries int
+
+	// timeout caps the total time spent on one URL, retries and waits
+	// included; 0 means no limit
+	timeout time.Duration
+
+	// backoff is the wait before the first retry
+	backoff time.Duration
+
+	// log receives a line for each retry
+	log io.Writer
+
+	// hashes, when set, records the SHA-256 of each body fetched, by URL
+	hashes map[string]string
+}
+
+// retryableError is a fetch failure worth trying again
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// fetch returns the body of rawURL
+func (f *fetcher) fetch(rawURL string) ([]byte, error) {
+	ctx := context.Background()
+	if f.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.timeout)
+		defer cancel()
+	}
+
+	wait := f.backoff
+	for attempt := 1; ; attempt++ {
+		data, err := f.get(ctx, rawURL)
+		if err == nil {
+			if f.hashes != nil {
+				f.hashes[rawURL] = hashBytes(data)
+			}
+			return data, nil
+		}
+		var retryable *retryableError
+		if !errors.As(err, &retryable) || attempt > f.retries {
+			return nil, err
+		}
+
+		fmt.Fprintf(f.log, "%s fetching %s failed (attempt %d of %d): %v; retrying in %s\n",
+			stderrColor.warning(), rawURL, attempt, f.retries+1, err, wait)
+		select {
+		case <-ctx.Done():
+			return nil, f.timedOut(err)
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// get makes one attempt at fetching rawURL
+func (f *fetcher) get(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, f.timedOut(err)
+		}
+		return nil, &retryableError{err}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		return nil, &retryableError{fmt.Errorf("server returned %s", resp.Status)}
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return nil, fmt.Errorf("server returned %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, f.timedOut(err)
+		}
+		return nil, &retryableError{err}
+	}
+	return data, nil
+}
+
+// timedOut describes running out of time, with the last failure seen
+func (f *fetcher) timedOut(last error) error {
+	return fmt.Errorf("gave up after -fetch-timeout %s: %w", f.timeout, last)
+}
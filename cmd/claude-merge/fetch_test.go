@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFetcher returns a fetcher with short waits that logs to log
+func testFetcher(retries int, timeout time.Duration, log *bytes.Buffer) *fetcher {
+	return &fetcher{client: http.DefaultClient, retries: retries, timeout: timeout, backoff: time.Millisecond, log: log}
+}
+
+// flakyServer fails with status for the first failures requests, then
+// serves body, counting every request in calls
+func flakyServer(t *testing.T, failures int32, status int, body string, calls *int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(calls, 1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFetcher_RetriesServerErrors(t *testing.T) {
+	var calls int32
+	server := flakyServer(t, 2, http.StatusServiceUnavailable, "# Remote", &calls)
+
+	var log bytes.Buffer
+	data, err := testFetcher(3, time.Minute, &log).fetch(server.URL + "/a.md")
+	require.NoError(t, err)
+	assert.Equal(t, "# Remote", string(data))
+	assert.Equal(t, int32(3), calls)
+	assert.Contains(t, log.String(), "(attempt 1 of 4): server returned 503 Service Unavailable")
+	assert.Contains(t, log.String(), "(attempt 2 of 4)")
+}
+
+func TestFetcher_GivesUpAfterRetries(t *testing.T) {
+	var calls int32
+	server := flakyServer(t, 10, http.StatusBadGateway, "", &calls)
+
+	var log bytes.Buffer
+	_, err := testFetcher(2, time.Minute, &log).fetch(server.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "502")
+	assert.Equal(t, int32(3), calls)
+}
+
+func TestFetcher_NoRetryOnClientErrors(t *testing.T) {
+	var calls int32
+	server := flakyServer(t, 10, http.StatusNotFound, "", &calls)
+
+	var log bytes.Buffer
+	_, err := testFetcher(3, time.Minute, &log).fetch(server.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404 Not Found")
+	assert.Equal(t, int32(1), calls)
+	assert.Empty(t, log.String())
+}
+
+func TestFetcher_RetriesConnectionErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	var log bytes.Buffer
+	_, err := testFetcher(1, time.Minute, &log).fetch(url)
+	require.Error(t, err)
+	assert.Contains(t, log.String(), "(attempt 1 of 2)")
+}
+
+func TestFetcher_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	var log bytes.Buffer
+	start := time.Now()
+	_, err := testFetcher(5, 50*time.Millisecond, &log).fetch(server.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "gave up after -fetch-timeout 50ms")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"strings"
+)
+
+// repeatableFlag is a flag that may be given several times, each value
+// adding to the list
+type repeatableFlag interface {
+	flag.Value
+	values() []string
+}
+
+// stringsFlag collects every value of a repeatable string flag in order
+type stringsFlag []string
+
+// String returns the values joined by newlines
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, "\n")
+}
+
+// Set adds a value
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// values returns the values in the order they were given
+func (f *stringsFlag) values() []string {
+	return *f
+}
+
+// setFlag sets a flag from settings values. A repeatable flag is set once
+// per value; any other flag gets the values joined by commas.
+func setFlag(flags *flag.FlagSet, name string, values []string) error {
+	if _, ok := flags.Lookup(name).Value.(repeatableFlag); ok {
+		for _, value := range values {
+			err := flags.Set(name, value)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return flags.Set(name, strings.Join(values, ","))
+}
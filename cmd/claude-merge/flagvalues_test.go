@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringsFlag(t *testing.T) {
+	var rules stringsFlag
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Var(&rules, "replace", "")
+
+	require.NoError(t, flags.Parse([]string{"-replace", "a=>b", "-replace=c,d=>e"}))
+	assert.Equal(t, []string{"a=>b", "c,d=>e"}, rules.values(), "values keep their commas")
+}
+
+func TestSetFlag(t *testing.T) {
+	var rules stringsFlag
+	flags := testFlags()
+	flags.Var(&rules, "replace", "")
+
+	require.NoError(t, setFlag(flags, "replace", []string{"a=>b", "c=>d"}))
+	require.NoError(t, setFlag(flags, "files", []string{"a.md", "b.md"}))
+
+	assert.Equal(t, []string{"a=>b", "c=>d"}, rules.values(), "a repeatable flag is set once per value")
+	assert.Equal(t, "a.md,b.md", flags.Lookup("files").Value.String())
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// loadGlossary reads a YAML mapping of terms to URLs from filename. JSON
+// files load too, since JSON is valid YAML.
+func loadGlossary(filename string) (map[string]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var terms map[string]string
+	err = yaml.Unmarshal(config.StripBOM(data), &terms)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	for term, url := range terms {
+		if term == "" || url == "" {
+			return nil, fmt.Errorf("%s: term %q needs a non-empty URL", filename, term)
+		}
+	}
+	return terms, nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadGlossary(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "glossary.yaml")
+	require.NoError(t, os.WriteFile(valid, []byte("\uFEFFMCP: https://mcp.example\nC++: https://cpp.example\n"), 0644))
+	terms, err := loadGlossary(valid)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"MCP": "https://mcp.example", "C++": "https://cpp.example"}, terms)
+
+	empty := filepath.Join(dir, "empty.yaml")
+	require.NoError(t, os.WriteFile(empty, []byte("MCP: \"\"\n"), 0644))
+	_, err = loadGlossary(empty)
+	assert.ErrorContains(t, err, "needs a non-empty URL")
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte("- MCP\n"), 0644))
+	_, err = loadGlossary(invalid)
+	assert.Error(t, err)
+
+	_, err = loadGlossary(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
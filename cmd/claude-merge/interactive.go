@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/arustydev/claude-merge/internal/merger"
+)
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// promptResolver returns a conflict resolver that shows both candidates on
+// out and reads the choice from in. End of input falls back to the automatic
+// rules for this and every later conflict.
+func promptResolver(in io.Reader, out io.Writer) merger.ConflictResolver {
+	reader := bufio.NewReader(in)
+	done := false
+
+	return func(conflict merger.Conflict) (merger.Resolution, error) {
+		if done {
+			return merger.ResolveAuto, nil
+		}
+
+		fmt.Fprintf(out, "\nConflict in section %s (both %s):\n", conflict.Section, conflict.Incoming.Priority)
+		fmt.Fprintf(out, "--- [a] %s\n%s\n", conflict.ExistingSource, conflict.Existing.Content)
+		fmt.Fprintf(out, "--- [b] %s\n%s\n", conflict.IncomingSource, conflict.Incoming.Content)
+
+		for {
+			fmt.Fprint(out, "Keep [a], keep [b], or append [both]? ")
+			line, err := reader.ReadString('\n')
+			switch strings.ToLower(strings.TrimSpace(line)) {
+			case "a":
+				return merger.KeepExisting, nil
+			case "b":
+				return merger.KeepIncoming, nil
+			case "both":
+				return merger.AppendBoth, nil
+			}
+
+			if err == io.EOF {
+				fmt.Fprintln(out, "\nNo more input; resolving remaining conflicts automatically")
+				done = true
+				return merger.ResolveAuto, nil
+			}
+			if err != nil {
+				return merger.ResolveAuto, err
+			}
+		}
+	}
+}
@@ -1,102 +1,1242 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/arustydev/claude-merge/internal/about"
 	"github.com/arustydev/claude-merge/internal/config"
 	"github.com/arustydev/claude-merge/internal/generator"
+	"github.com/arustydev/claude-merge/internal/gitinfo"
 	"github.com/arustydev/claude-merge/internal/merger"
+	"github.com/arustydev/claude-merge/internal/report"
 )
 
 func main() {
+	err := run()
+	if err != nil {
+		log.Print(stderrColor.error(err.Error()))
+		os.Exit(exitCode(err))
+	}
+}
+
+// run parses the flags and performs one run of the tool. Errors are returned
+// rather than fatal so deferred cleanup, such as flushing profiles, happens
+// on every path.
+func run() (err error) {
 	// Define command-line flags
 	var (
-		files      = flag.String("files", "", "Comma-separated paths to configuration files (required)")
+		files      = flag.String("files", "", "Comma-separated paths to configuration files (required unless -convention or -dir is set)")
+		convention = flag.String("convention", "", "Base file, or directory holding COMMON.md, whose sibling fragments are merged after it (optional)")
+		dir        = flag.String("dir", "", "Directory whose subdirectories are each merged on their own, then merged together in name order (optional)")
+		multiLang  = flag.Bool("multi-language", false, "Merge each language's inputs on their own and nest their sections under a heading per language, after the shared sections")
+		convPatt   = flag.String("convention-pattern", "LANG.*.md", "Glob matching the fragments found by -convention")
+		stdinMulti = flag.Bool("stdin-multi", false, "Also read a stream of configs from stdin, split at -stdin-delimiter lines and merged after the files in stream order")
+		stdinFmt   = flag.String("stdin-format", "markdown", "Format of the configs read by -stdin-multi: markdown, toml, yaml, or json")
+		stdinDelim = flag.String("stdin-delimiter", "---8<---", "Line separating the configs read by -stdin-multi; Go escapes such as \\x1e are understood")
+		stdinSkip  = flag.Bool("stdin-skip-invalid", false, "Skip configs read by -stdin-multi that fail to parse, with a warning, instead of failing")
+		fetchRetry = flag.Int("fetch-retries", 3, "Times a remote config is fetched again after a connection error or 5xx response")
+		fetchTime  = flag.Duration("fetch-timeout", 30*time.Second, "Maximum total time spent fetching one remote config, retries included (0 disables the limit)")
 		outputFile = flag.String("output", "CLAUDE.merged.md", "Output filename (default: CLAUDE.merged.md)")
+		outFormat  = flag.String("format", "markdown", "Output format: markdown, sections-json, toml, or yaml")
+		outputTmpl = flag.String("output-template", "", "Output path template using {lang} and {title}, overrides -output (optional)")
+		mergeInto  = flag.String("merge-into", "", "Existing markdown file whose block between -merge-start and -merge-end is replaced with the output, overrides -output (optional)")
+		mergeStart = flag.String("merge-start", generator.DefaultManagedStart, "Marker starting the block replaced by -merge-into")
+		mergeEnd   = flag.String("merge-end", generator.DefaultManagedEnd, "Marker ending the block replaced by -merge-into")
+		yamlRoot   = flag.String("yaml-root", "", "Dot-separated key path of the config within YAML files (optional)")
+		tomlRoot   = flag.String("toml-root", "", "Dot-separated table path of the config within TOML files (optional)")
 		mergeOrder = flag.String("order", "", "Comma-separated file order for merging (optional)")
+		sectFrom   = flag.String("sections-from", "", "Reference markdown file whose heading order sets the output section order (optional)")
+		defaults   = flag.String("defaults", "", "Configuration file providing lowest-precedence fallback content (optional)")
+		allowList  = flag.String("allowed-sections", "", "File of allowed section key globs, one per line; other merged keys are an error (optional)")
+		withTags   = flag.String("with-tags", "", "Comma-separated tags; keep only tagged sections carrying one of them, plus untagged sections (optional)")
+		withoutTag = flag.String("without-tags", "", "Comma-separated tags; drop every section carrying one of them (optional)")
+		required   = flag.String("required-sections", "", "Comma-separated section keys that must be present with content after merging (optional)")
+		schemaFile = flag.String("schema", "", "JSON Schema file every input config must satisfy, checked against its JSON form (optional)")
+		overrides  = flag.String("overrides", "", "Sidecar file setting priorities and orders for input files and sections (optional)")
+		concatRaw  = flag.Bool("concat-raw", false, "Concatenate the input files as-is, skipping parsing and merging")
+		separator  = flag.String("separator", `\n`, "Text placed between files in -concat-raw mode; \\n is a newline")
+		metaOnly   = flag.Bool("metadata-only", false, "Merge and output only the metadata, skipping sections and placeholders")
 		validate   = flag.Bool("validate", false, "Validate only, don't generate output")
+		listPlace  = flag.Bool("list-placeholders", false, "List every placeholder in the inputs with the section and file it appears in, then exit")
+		strictTags = flag.Bool("strict-placeholders", false, "Fail if any placeholder tag is left in the merged sections")
+		strict     = flag.Bool("strict", false, "Treat empty input files as errors instead of warnings")
+		allowBin   = flag.Bool("allow-binary", false, "Load input files even when they look binary rather than text")
+		splitMD    = flag.Bool("split-markdown", false, "Split markdown inputs into a section per heading and list instead of one content section")
 		debug      = flag.Bool("debug", false, "Enable debug output")
+		mergeLists = flag.Bool("merge-lists", false, "Union list fields such as merge_points and tags across files instead of replacing them")
+		interact   = flag.Bool("interactive", false, "Prompt to settle equal-priority section conflicts (requires a terminal)")
+		equalPri   = flag.String("equal-priority", "last", "Which file wins when priorities are equal: last or first")
+		versionPol = flag.String("version-policy", "priority", "How the merged version is chosen: priority or highest-semver")
+		trace      = flag.String("trace", "", "Print every merge decision for the given section key (optional)")
+		collapse   = flag.String("collapse", "", "Comma-separated regex=key rules folding matching section keys into one section (optional)")
+		collStrat  = flag.String("collapse-strategy", "append", "Strategy combining sections folded by -collapse: append, prepend, replace, collapse, or keyvalue")
+		foldCase   = flag.Bool("fold-case", false, "Match section keys case-insensitively, keeping the winning file's spelling")
+		titleTmpl  = flag.String("title-template", "", "Go template over the merged metadata, such as {{.Title}}, rendered as the top heading; when empty, no heading is added and the title is only recorded in a comment (optional)")
+		noTitle    = flag.Bool("no-title", false, "Leave the top-level heading out of markdown output, for embedding it in another document")
+		stamp      = flag.Bool("stamp", false, "Start the output with a do-not-edit comment naming the tool version and source files")
+		frontPass  = flag.Bool("frontmatter-passthrough", false, "Start markdown output with YAML frontmatter holding the merged metadata, including extra fields")
+		frontUnion = flag.String("frontmatter-union", "tags,authors", "Comma-separated frontmatter fields collected from every input instead of the winning one")
+		gitProv    = flag.Bool("git-provenance", false, "Add the git path and commit of each source file to -summary and -stamp")
+		tsFooter   = flag.Bool("timestamp-footer", false, "End the output with a footer giving the generation time in UTC")
+		tsLayout   = flag.String("timestamp-layout", time.RFC3339, "Go time layout for -timestamp-footer")
+		writeDiff  = flag.Bool("write-diff", false, "Also write a unified diff from the previous output to <output>.diff")
+		writeBOM   = flag.Bool("bom", false, "Start the written output with a UTF-8 byte order mark")
+		checksum   = flag.Bool("checksum", false, "Also write the SHA-256 of the written output to <output>.sha256 in sha256sum format")
+		manifest   = flag.String("manifest", "", "Write a JSON manifest of the hashed inputs, merge order, options, tool version, and output hash to this file (optional)")
+		manifestTS = flag.Bool("manifest-timestamp", false, "Record the generation time in the -manifest file")
+		sectionGap = flag.Int("section-gap", 1, "Number of blank lines between sections: 0, 1, or 2")
+		divider    = flag.String("divider", "", "Text written between sections in markdown output, such as --- for a horizontal rule; \\n is a newline (optional)")
+		noOrder    = flag.Bool("no-order", false, "Write markdown sections in the order they were loaded, ignoring their order fields, to debug layout")
+		sectDir    = flag.String("out-sections-dir", "", "Write each merged section to its own file in this directory, plus an index.md, instead of -output (optional)")
+		contOnErr  = flag.Bool("continue-on-error", false, "In -multi-language, -dir, and -out-sections-dir runs, leave out the parts that fail, write the rest, and exit with code 7")
+		maxDepth   = flag.Int("max-heading-depth", 0, "Deepest heading level kept in markdown output, 1-6 (0 keeps every level)")
+		overflow   = flag.String("heading-overflow", "bold", "What -max-heading-depth does to deeper headings: bold or clamp")
+		headOffset = flag.String("heading-offset", "", "Comma-separated file=offset pairs moving each file's headings that many levels deeper, or shallower if negative (optional)")
+		glossary   = flag.String("glossary", "", "YAML file mapping terms to URLs; terms in markdown output become links (optional)")
+		glossAll   = flag.Bool("glossary-all", false, "Link every occurrence of a -glossary term instead of only the first")
+		glossWord  = flag.Bool("glossary-whole-word", true, "Only link -glossary terms that are not part of a longer word")
+		replCode   = flag.Bool("replace-in-code", false, "Also apply -replace rules inside fenced code blocks")
+		normalize  = flag.String("normalize-unicode", "", "Unicode normalization form for section content: nfc, nfd, nfkc, or nfkd (default: off)")
+		lint       = flag.Bool("lint", false, "Report style issues in the markdown output; error-severity issues fail the run before writing")
+		lintSev    = flag.String("lint-severity", "", "Comma-separated rule=severity pairs (error, warning, or off) overriding the -lint defaults (optional)")
+		fmtCode    = flag.Bool("fmt-code-blocks", false, "Run Go fenced code blocks in the output through gofmt")
+		normFence  = flag.Bool("normalize-fences", false, "Rewrite code fence language aliases in the output, such as golang and py, to one name, such as go and python")
+		fenceAlias = flag.String("fence-aliases", "", "YAML file mapping code fence language aliases to names, added to the defaults; implies -normalize-fences (optional)")
+		postCmd    = flag.String("post-command", "", "Command that receives the generated markdown on stdin and prints the final output (optional)")
+		postTime   = flag.Duration("post-command-timeout", 30*time.Second, "Maximum run time for -post-command")
+		preview    = flag.Bool("preview", false, "Print the markdown output to the terminal with basic styling instead of writing it")
+		printCfg   = flag.Bool("print-config", false, "Print the merged configuration as JSON instead of writing output")
+		summary    = flag.Bool("summary", false, "Print tables of the sections loaded from each input and where each merged section came from")
+		maxFiles   = flag.Int("max-files", 1000, "Maximum number of input files to load (0 disables the limit)")
+		noPlace    = flag.Bool("no-placeholder", false, "Never treat an input as a base template; merge by priority and keep placeholder tags as text")
+		keepEmpty  = flag.Bool("keep-empty-placeholders", false, "Keep placeholder blocks that have no replacement content")
+		cpuProfile = flag.String("cpuprofile", "", "Write a CPU profile of the run to this file (optional)")
+		memProfile = flag.String("memprofile", "", "Write a heap profile at the end of the run to this file (optional)")
+		watchMode  = flag.Bool("watch", false, "Keep running and regenerate the output whenever an input file changes")
+		watchDelay = flag.Duration("watch-debounce", 300*time.Millisecond, "Quiet period -watch waits after a change, batching rapid saves into one regeneration")
+		noColor    = flag.Bool("no-color", false, "Never color console messages (also set by the NO_COLOR environment variable)")
 		help       = flag.Bool("help", false, "Show help message")
+		replaces   stringsFlag
+		patterns   stringsFlag
+		cacheFile  cacheFlag
 	)
+	flag.Var(&replaces, "replace", "Regex rewrite rule pattern=>replacement applied to section content; repeatable, applied in order (optional)")
+	flag.Var(&cacheFile, "cache", "Cache file, .claude-merge-cache when given as -cache alone; when no input, priority, or flag has changed since the last run, its output is reused (optional)")
+	flag.Var(&patterns, "placeholder-pattern", "Rule name=heading filling placeholders whose name matches name, where * captures, from the section headed by heading with {1}, {2} replaced by the captures; repeatable (optional)")
 
-	// Parse the flags
+	// Parse the flags. Flags not given on the command line fall back to the
+	// nearest project settings file, then the user settings file.
 	flag.Parse()
+	settingsLayers, settingsFiles, err := loadSettingsLayers(flag.CommandLine)
+	if err != nil {
+		return usageError(err)
+	}
+	err = applySettings(flag.CommandLine, settingsLayers...)
+	if err != nil {
+		return usageError(err)
+	}
+	stdoutColor = newColorizer(os.Stdout, *noColor)
+	stderrColor = newColorizer(os.Stderr, *noColor)
+
+	// A bare -cache takes no value, so a file name after it is left over
+	if cacheFile != "" && flag.NArg() > 0 {
+		return usageError(fmt.Errorf("unexpected argument %q; name the cache file with -cache=<file>", flag.Arg(0)))
+	}
 
 	if *help {
 		printHelp()
-		return
+		return nil
+	}
+	if *debug {
+		for _, file := range settingsFiles {
+			fmt.Fprintf(os.Stderr, "Using settings from %s\n", file)
+		}
+	}
+
+	// The managed document is read and written in place of -output
+	if *mergeInto != "" {
+		*outputFile = *mergeInto
+	}
+
+	// Each regeneration is a fresh run of the tool with the same flags
+	if *watchMode {
+		if *interact || *stdinMulti {
+			return usageError(fmt.Errorf("-watch cannot be combined with -interactive or -stdin-multi"))
+		}
+		if *watchDelay < 0 {
+			return usageError(fmt.Errorf("-watch-debounce must not be negative, got %s", *watchDelay))
+		}
+
+		// A convention base file's fragments are its siblings
+		conventionRoot := *convention
+		if info, err := os.Stat(conventionRoot); err == nil && !info.IsDir() {
+			conventionRoot = filepath.Dir(conventionRoot)
+		}
+		paths := watchPaths(*files, conventionRoot, *dir, *defaults, *overrides, *sectFrom, *glossary, *fenceAlias, *allowList, *schemaFile)
+		if len(paths) == 0 {
+			return usageError(fmt.Errorf("-watch needs input files from -files, -convention, or -dir"))
+		}
+		ignored := ignoredPaths(*outputFile, *outputFile+".diff", *sectDir, string(cacheFile), *manifest, *cpuProfile, *memProfile)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		args := flagArgs(watchFlags)
+		return watch(ctx, paths, ignored, *watchDelay, func(ctx context.Context) error {
+			return regenerate(ctx, args)
+		})
+	}
+
+	// Profiles are flushed however the run ends, including on errors
+	stopProfiling, err := startProfiling(*cpuProfile, *memProfile)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, stopProfiling())
+	}()
+
+	// Discover the base file and its fragments by convention, ahead of any
+	// files listed explicitly
+	var inputFiles []string
+	if *convention != "" {
+		inputFiles, err = conventionFiles(*convention, *convPatt)
+		if err != nil {
+			return usageError(err)
+		}
+	}
+
+	// Gather the topic subdirectories and their files
+	var groups []dirGroup
+	if *dir != "" {
+		if *files != "" || *convention != "" || *mergeOrder != "" {
+			return usageError(fmt.Errorf("-dir cannot be combined with -files, -convention, or -order"))
+		}
+		groups, err = dirGroups(*dir)
+		if err != nil {
+			return usageError(err)
+		}
+		for _, group := range groups {
+			inputFiles = append(inputFiles, group.Files...)
+		}
 	}
 
 	// Split input files
-	inputFiles := strings.Split(*files, ",")
-	for i := range inputFiles {
-		inputFiles[i] = strings.TrimSpace(inputFiles[i])
+	if *files != "" || (len(inputFiles) == 0 && !*stdinMulti) {
+		for _, file := range strings.Split(*files, ",") {
+			inputFiles = append(inputFiles, strings.TrimSpace(file))
+		}
+	}
+
+	// Validate arguments; with -stdin-multi, stdin may supply every input
+	if len(inputFiles) > 0 || !*stdinMulti {
+		err = validateArgs(inputFiles, *outputFile)
+		if err != nil {
+			return usageError(err)
+		}
+	}
+	var streamOpts config.StreamOptions
+	if *stdinMulti {
+		if *dir != "" || *concatRaw || *interact || *manifest != "" {
+			return usageError(fmt.Errorf("-stdin-multi cannot be combined with -dir, -concat-raw, -interactive, or -manifest"))
+		}
+		streamOpts.Format, err = config.ParseFormatName(*stdinFmt)
+		if err != nil {
+			return usageError(fmt.Errorf("-stdin-format: %w", err))
+		}
+		streamOpts.Delimiter, err = strconv.Unquote(`"` + *stdinDelim + `"`)
+		if err != nil || streamOpts.Delimiter == "" {
+			return usageError(fmt.Errorf("-stdin-delimiter must be a non-empty line of text, got %q", *stdinDelim))
+		}
+		streamOpts.SkipInvalid = *stdinSkip
+	}
+
+	// Drop duplicate input files
+	inputFiles, duplicates := dedupeFiles(inputFiles)
+	if *debug {
+		for _, file := range duplicates {
+			fmt.Fprintf(os.Stderr, "Skipping duplicate input file %s\n", file)
+		}
+	}
+
+	err = checkFileLimit(inputFiles, *maxFiles)
+	if err != nil {
+		return usageError(err)
+	}
+
+	if *sectionGap < 0 || *sectionGap > 2 {
+		return usageError(fmt.Errorf("-section-gap must be 0, 1, or 2, got %d", *sectionGap))
+	}
+
+	switch *outFormat {
+	case "markdown", "sections-json", "toml", "yaml":
+	default:
+		return usageError(fmt.Errorf("-format must be 'markdown', 'sections-json', 'toml', or 'yaml', got '%s'", *outFormat))
+	}
+
+	lintSeverities, err := generator.ParseLintSeverities(*lintSev)
+	if err != nil {
+		return usageError(err)
+	}
+	if *divider != "" && (*outFormat != "markdown" || *metaOnly || *sectDir != "") {
+		return usageError(fmt.Errorf("-divider requires markdown output and cannot be combined with -metadata-only or -out-sections-dir"))
+	}
+	if *noOrder && (*outFormat != "markdown" || *metaOnly || *sectDir != "") {
+		return usageError(fmt.Errorf("-no-order requires markdown output and cannot be combined with -metadata-only or -out-sections-dir"))
+	}
+	if *lint && (*outFormat != "markdown" || *metaOnly) {
+		return usageError(fmt.Errorf("-lint requires markdown output and cannot be combined with -metadata-only"))
+	}
+	requiredSections := splitKeys(*required)
+	if len(requiredSections) > 0 && *metaOnly {
+		return usageError(fmt.Errorf("-required-sections cannot be combined with -metadata-only"))
+	}
+	if *preview && (*outFormat != "markdown" || *sectDir != "") {
+		return usageError(fmt.Errorf("-preview requires markdown output and cannot be combined with -out-sections-dir"))
+	}
+	if *fetchRetry < 0 {
+		return usageError(fmt.Errorf("-fetch-retries must not be negative, got %d", *fetchRetry))
+	}
+	if *fetchTime < 0 {
+		return usageError(fmt.Errorf("-fetch-timeout must not be negative, got %s", *fetchTime))
+	}
+	if *checksum && (*sectDir != "" || *preview) {
+		return usageError(fmt.Errorf("-checksum cannot be combined with -out-sections-dir or -preview"))
+	}
+	if *manifest != "" && (*sectDir != "" || *preview) {
+		return usageError(fmt.Errorf("-manifest cannot be combined with -out-sections-dir or -preview"))
+	}
+	if *mergeInto != "" && (*outFormat != "markdown" || *metaOnly || *sectDir != "" || *outputTmpl != "" || *concatRaw) {
+		return usageError(fmt.Errorf("-merge-into requires markdown output and cannot be combined with -metadata-only, -out-sections-dir, -output-template, or -concat-raw"))
+	}
+	if *mergeInto != "" && (*mergeStart == "" || *mergeEnd == "") {
+		return usageError(fmt.Errorf("-merge-start and -merge-end must not be empty"))
+	}
+	if *multiLang && *dir != "" {
+		return usageError(fmt.Errorf("-multi-language cannot be combined with -dir"))
+	}
+	if *contOnErr && !*multiLang && *dir == "" && *sectDir == "" {
+		return usageError(fmt.Errorf("-continue-on-error requires -multi-language, -dir, or -out-sections-dir"))
+	}
+
+	// Parts left out by -continue-on-error are reported once the rest of the
+	// run has succeeded
+	var failures partFailures
+	defer func() {
+		if err == nil {
+			err = failures.error()
+		}
+	}()
+	if *noTitle && *titleTmpl != "" {
+		return usageError(fmt.Errorf("-no-title cannot be combined with -title-template"))
+	}
+	if *sectDir != "" && (*outFormat != "markdown" || *metaOnly) {
+		return usageError(fmt.Errorf("-out-sections-dir requires markdown output and cannot be combined with -metadata-only"))
 	}
 
-	// Validate arguments
-	err := validateArgs(inputFiles, *outputFile)
+	equalPolicy := merger.EqualPriorityPolicy(*equalPri)
+	if !equalPolicy.IsValid() {
+		return usageError(fmt.Errorf("-equal-priority must be 'last' or 'first', got '%s'", *equalPri))
+	}
+	versionPolicy := merger.VersionPolicy(*versionPol)
+	if !versionPolicy.IsValid() {
+		return usageError(fmt.Errorf("-version-policy must be 'priority' or 'highest-semver', got '%s'", *versionPol))
+	}
+	if *maxDepth < 0 || *maxDepth > 6 {
+		return usageError(fmt.Errorf("-max-heading-depth must be between 0 and 6, got %d", *maxDepth))
+	}
+	headingOverflow := generator.HeadingOverflow(*overflow)
+	if !headingOverflow.IsValid() {
+		return usageError(fmt.Errorf("-heading-overflow must be 'bold' or 'clamp', got '%s'", *overflow))
+	}
+	headingOffsets, err := config.ParseHeadingOffsets(*headOffset)
 	if err != nil {
-		log.Fatalf("Invalid arguments: %v", err)
+		return usageError(fmt.Errorf("-heading-offset: %w", err))
+	}
+	var allowedSections []string
+	if *allowList != "" {
+		allowedSections, err = loadAllowlist(*allowList)
+		if err != nil {
+			return usageError(err)
+		}
+	}
+	var schema *config.Schema
+	if *schemaFile != "" {
+		schema, err = config.LoadSchema(*schemaFile)
+		if err != nil {
+			return usageError(err)
+		}
+	}
+	var replaceRules []generator.ReplaceRule
+	for _, spec := range replaces {
+		rule, err := generator.ParseReplaceRule(spec)
+		if err != nil {
+			return usageError(err)
+		}
+		replaceRules = append(replaceRules, rule)
+	}
+	var placeRules []merger.PlaceholderPattern
+	for _, spec := range patterns {
+		rule, err := merger.ParsePlaceholderPattern(spec)
+		if err != nil {
+			return usageError(err)
+		}
+		placeRules = append(placeRules, rule)
+	}
+	unicodeForm := config.UnicodeForm(strings.ToLower(*normalize))
+	if !unicodeForm.IsValid() {
+		return usageError(fmt.Errorf("-normalize-unicode must be 'nfc', 'nfd', 'nfkc', or 'nfkd', got '%s'", *normalize))
+	}
+	var glossaryTerms map[string]string
+	if *glossary != "" {
+		glossaryTerms, err = loadGlossary(*glossary)
+		if err != nil {
+			return usageError(err)
+		}
+	}
+	var fenceAliases map[string]string
+	if *fenceAlias != "" {
+		fenceAliases, err = loadFenceAliases(*fenceAlias)
+		if err != nil {
+			return usageError(err)
+		}
+	} else if *normFence {
+		fenceAliases = generator.DefaultFenceAliases()
+	}
+	collapseStrategy := merger.MergeStrategy(*collStrat)
+	if !collapseStrategy.IsValid() {
+		return usageError(fmt.Errorf("-collapse-strategy must be one of append, prepend, replace, collapse, or keyvalue, got '%s'", *collStrat))
+	}
+	var collapseRules []merger.SectionCollapse
+	if *collapse != "" {
+		for _, spec := range strings.Split(*collapse, ",") {
+			rule, err := merger.ParseSectionCollapse(spec)
+			if err != nil {
+				return usageError(err)
+			}
+			collapseRules = append(collapseRules, rule)
+		}
 	}
 
 	// Determine merge order
 	fileOrder := parseFileOrder(inputFiles, *mergeOrder)
 
 	if *debug {
-		fmt.Printf("Input files: %v\n", inputFiles)
-		fmt.Printf("Merge order: %v\n", fileOrder)
-		fmt.Printf("Output file: %s\n", *outputFile)
+		fmt.Fprintf(os.Stderr, "Input files: %v\n", inputFiles)
+		fmt.Fprintf(os.Stderr, "Merge order: %v\n", fileOrder)
+		fmt.Fprintf(os.Stderr, "Output file: %s\n", *outputFile)
 	}
 
-	// Load all configurations
+	remote := &fetcher{client: http.DefaultClient, retries: *fetchRetry, timeout: *fetchTime, backoff: fetchBackoff, log: os.Stderr}
+	if *manifest != "" {
+		remote.hashes = make(map[string]string)
+	}
+
+	// writeManifest records which inputs produced data, the bytes written to
+	// output, when -manifest is set
+	writeManifest := func(output string, data []byte) error {
+		if *manifest == "" {
+			return nil
+		}
+		record, err := newManifest(about.Version, flagArgs(nil), inputFiles, fileOrder,
+			[]string{*defaults, *overrides, *sectFrom, *glossary, *fenceAlias, *allowList, *schemaFile}, remote.hashes)
+		if err != nil {
+			return fmt.Errorf("Failed to hash inputs for manifest: %w", err)
+		}
+		var at time.Time
+		if *manifestTS {
+			at = time.Now()
+		}
+		return record.save(*manifest, output, data, at)
+	}
+
+	// Raw concatenation skips parsing, merging, and generation entirely
+	if *concatRaw {
+		data, err := concatFiles(fileOrder, strings.ReplaceAll(*separator, `\n`, "\n"))
+		if err != nil {
+			return fmt.Errorf("Failed to concatenate files: %w", err)
+		}
+		data = withBOM(data, *writeBOM)
+		err = os.WriteFile(*outputFile, data, 0644)
+		if err != nil {
+			return withExitCode(exitWrite, fmt.Errorf("Failed to write output: %w", err))
+		}
+		if *checksum {
+			err = writeChecksum(*outputFile, data)
+			if err != nil {
+				return err
+			}
+		}
+		err = writeManifest(*outputFile, data)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Concatenated %d files into %s\n", stdoutColor.check(), len(fileOrder), *outputFile)
+		return nil
+	}
+
+	loadOpts := config.LoadOptions{YAMLRoot: *yamlRoot, TOMLRoot: *tomlRoot, AllowBinary: *allowBin, SplitMarkdown: *splitMD, SharedTiers: true}
+	streamOpts.Load = loadOpts
+
+	// With -continue-on-error, an input that fails to load is left out of
+	// -multi-language runs, and its whole group out of -dir runs
+	skipFailed := *contOnErr && (*multiLang || len(groups) > 0)
+	partOf := make(map[string]string)
+	for _, group := range groups {
+		for _, file := range group.Files {
+			partOf[file] = group.Name
+		}
+	}
+
+	// accept checks the configs loaded from filename and adds them to the
+	// configs being merged
 	configs := make([]*config.Config, 0, len(fileOrder))
+	accept := func(filename string, loaded []*config.Config) error {
+		if *validate {
+			for _, cfg := range loaded {
+				err := config.ValidateConfig(cfg)
+				if err != nil {
+					return fmt.Errorf("Invalid config %s: %w", filename, err)
+				}
+			}
+			fmt.Printf("%s %s validated successfully\n", stdoutColor.check(), filename)
+		}
+
+		// Versions that can't be compared are left out of highest-semver
+		if versionPolicy == merger.VersionHighestSemver {
+			for _, cfg := range loaded {
+				if cfg.Metadata.Version != "" && !merger.IsSemver(cfg.Metadata.Version) {
+					fmt.Fprintf(os.Stderr, "%s version %q in %s is not a semantic version; ignoring it for -version-policy\n", stderrColor.warning(), cfg.Metadata.Version, filename)
+				}
+			}
+		}
+
+		// Empty inputs are usually truncated files, so don't let them vanish
+		for _, cfg := range loaded {
+			if !cfg.IsEmpty() {
+				continue
+			}
+			if *strict {
+				return withExitCode(exitValidation, fmt.Errorf("Invalid config %s: file is empty, contributing no content", filename))
+			}
+			fmt.Fprintf(os.Stderr, "%s file %s is empty, contributing no content\n", stderrColor.warning(), filename)
+		}
+
+		configs = append(configs, loaded...)
+
+		if *debug {
+			for _, cfg := range loaded {
+				fmt.Fprintf(os.Stderr, "%s Loaded %s (%s format)\n", stderrColor.check(), filename, formatName(cfg.SourceFormat))
+			}
+		}
+		return nil
+	}
+
+	// Load all configurations
 	for _, filename := range fileOrder {
-		cfg, err := config.LoadConfig(filename)
+		// A file may hold several configs, such as a JSON array
+		var loaded []*config.Config
+		if config.IsURL(filename) {
+			loaded, err = config.LoadConfigMultiURL(filename, remote.fetch, loadOpts)
+		} else {
+			loaded, err = config.LoadConfigMulti(filename, loadOpts)
+		}
 		if err != nil {
-			log.Fatalf("Failed to load config %s: %v", filename, err)
+			err = fmt.Errorf("Failed to load config %s: %w", filename, err)
+			if !skipFailed {
+				return err
+			}
+			part := filename
+			if group, ok := partOf[filename]; ok {
+				part = group
+			}
+			fmt.Fprintf(os.Stderr, "%s leaving out %s: %v\n", stderrColor.warning(), part, err)
+			failures.add(part, err)
+			continue
+		}
+		err = accept(filename, loaded)
+		if err != nil {
+			return err
 		}
+	}
 
-		if *validate {
-			err = config.ValidateConfig(cfg)
+	// Configs streamed on stdin follow the files, in stream order
+	if *stdinMulti {
+		streamed, skipped, err := config.LoadStream(os.Stdin, "stdin", streamOpts)
+		if err != nil {
+			return fmt.Errorf("Failed to load configs from stdin: %w", err)
+		}
+		for _, err := range skipped {
+			fmt.Fprintf(os.Stderr, "%s skipping config from stdin: %v\n", stderrColor.warning(), err)
+		}
+		if len(streamed) == 0 && len(fileOrder) == 0 {
+			return withExitCode(exitValidation, fmt.Errorf("No configs read from stdin"))
+		}
+		for _, cfg := range streamed {
+			err = accept(cfg.SourceFile, []*config.Config{cfg})
 			if err != nil {
-				log.Fatalf("Invalid config %s: %v", filename, err)
+				return err
 			}
-			fmt.Printf("✓ %s validated successfully\n", filename)
 		}
+	}
 
-		configs = append(configs, cfg)
+	if len(failures) > 0 {
+		groups, configs = keepGroups(groups, configs, failures.has)
+		if len(configs) == 0 {
+			return failures[0].err
+		}
+	}
 
-		if *debug {
-			fmt.Printf("✓ Loaded %s (%s format)\n", filename, formatName(cfg.SourceFormat))
+	// Check every input against the schema, reporting all violations at once
+	if schema != nil {
+		var violations []string
+		for _, cfg := range configs {
+			found, err := schema.Violations(cfg)
+			if err != nil {
+				return fmt.Errorf("Failed to check %s against the schema: %w", cfg.SourceFile, err)
+			}
+			for _, violation := range found {
+				violations = append(violations, cfg.SourceFile+": "+violation)
+			}
+		}
+		if len(violations) > 0 {
+			return withExitCode(exitValidation, fmt.Errorf("Schema violations in %s:\n  %s", *schemaFile, strings.Join(violations, "\n  ")))
+		}
+	}
+
+	// List the placeholders the inputs use instead of merging them
+	if *listPlace {
+		uses := merger.ListPlaceholders(configs)
+		if len(uses) == 0 {
+			fmt.Println("No placeholders found")
+			return nil
+		}
+		err = report.RenderTable(os.Stdout, report.PlaceholderRows(uses))
+		if err != nil {
+			return fmt.Errorf("Failed to list placeholders: %w", err)
+		}
+		return nil
+	}
+
+	// Load the defaults configuration, if any
+	var defaultsConfig *config.Config
+	if *defaults != "" {
+		defaultsConfig, err = config.LoadConfigWithOptions(*defaults, loadOpts)
+		if err != nil {
+			return fmt.Errorf("Failed to load defaults %s: %w", *defaults, err)
+		}
+	}
+
+	// Resolve named priority tiers across every input, including defaults
+	tierConfigs := append([]*config.Config{}, configs...)
+	if defaultsConfig != nil {
+		tierConfigs = append(tierConfigs, defaultsConfig)
+	}
+	err = config.ResolvePriorityTiers(tierConfigs)
+	if err != nil {
+		return withExitCode(exitValidation, fmt.Errorf("Invalid priority tiers: %w", err))
+	}
+	config.SequenceSections(tierConfigs)
+
+	// Shift the headings of each input by its offset, so fragments written
+	// from level 1 nest under the headings of the combined document
+	for _, file := range config.SetHeadingOffsets(tierConfigs, headingOffsets) {
+		fmt.Fprintf(os.Stderr, "%s heading offset for %s matches no input\n", stderrColor.warning(), file)
+	}
+	for _, cfg := range tierConfigs {
+		generator.ApplyHeadingOffset(cfg)
+	}
+
+	// Normalize every input, so text that only differs in its Unicode form
+	// is equal when merge strategies compare it
+	for _, cfg := range tierConfigs {
+		config.NormalizeUnicode(cfg, unicodeForm)
+	}
+
+	// Normalize fence languages in every input too, so blocks that only
+	// differ in their language alias are equal when merge strategies compare
+	// them
+	for _, cfg := range tierConfigs {
+		generator.ApplyFenceAliases(cfg, fenceAliases)
+	}
+
+	// Apply priority and order overrides from the sidecar file
+	if *overrides != "" {
+		sidecar, err := config.LoadOverrides(*overrides)
+		if err != nil {
+			return fmt.Errorf("Failed to load overrides %s: %w", *overrides, err)
+		}
+		unused, err := sidecar.Apply(tierConfigs)
+		if err != nil {
+			return withExitCode(exitValidation, fmt.Errorf("Invalid overrides %s: %w", *overrides, err))
+		}
+		for _, key := range unused {
+			fmt.Fprintf(os.Stderr, "%s override %s matches no input\n", stderrColor.warning(), key)
+		}
+	}
+
+	if *summary {
+		err = report.RenderTable(os.Stdout, report.SectionRows(configs))
+		if err != nil {
+			return fmt.Errorf("Failed to print summary: %w", err)
 		}
 	}
 
 	if *validate {
-		fmt.Println("✓ All configurations validated successfully")
-		return
+		var problems []string
+		if !*noPlace {
+			problems = merger.UnfillablePlaceholders(configs, placeRules...)
+		}
+		if len(problems) > 0 {
+			return withExitCode(exitValidation, fmt.Errorf("Invalid template:\n  %s", strings.Join(problems, "\n  ")))
+		}
+		fmt.Printf("%s All configurations validated successfully\n", stdoutColor.check())
+		return nil
+	}
+
+	// Reuse the previous output when nothing that shapes it has changed.
+	// Runs whose output depends on more than the inputs and flags, or that
+	// print merge details, always do the full merge.
+	var runCache *buildCache
+	if cacheFile != "" && *sectDir == "" && *mergeInto == "" && !*summary && !*printCfg && !*preview && !*interact && !*tsFooter && !*gitProv {
+		runCache, err = newBuildCache(about.Version, flagArgs(nil), configs, defaultsConfig, []string{*sectFrom, *glossary, *fenceAlias, *allowList})
+		if err != nil {
+			return fmt.Errorf("Failed to fingerprint inputs: %w", err)
+		}
+		previous, err := loadBuildCache(string(cacheFile))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s ignoring unreadable cache: %v\n", stderrColor.warning(), err)
+		}
+		if previous != nil && previous.Fingerprint == runCache.Fingerprint {
+			err = writeOutput(previous.OutputFile, previous.Output, *writeDiff, *writeBOM, *checksum)
+			if err != nil {
+				return err
+			}
+			err = writeManifest(previous.OutputFile, withBOM([]byte(previous.Output), *writeBOM))
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s Generated %s successfully (unchanged, from cache)\n", stdoutColor.check(), previous.OutputFile)
+			return nil
+		}
+		if previous != nil && *debug {
+			fmt.Fprintf(os.Stderr, "Inputs or flags changed since the cached run; rebuilding\n")
+		}
 	}
 
 	// Merge configurations using priority-based merging
-	m := merger.NewPriorityMerger(*debug)
-	merged, err := m.MergeAll(configs)
+	unionKeys := splitKeys(*frontUnion)
+	mergeOpts := merger.Options{
+		Debug:                 *debug,
+		KeepEmptyPlaceholders: *keepEmpty,
+		NoPlaceholders:        *noPlace,
+		MergeLists:            *mergeLists,
+		EqualPriority:         equalPolicy,
+		VersionPolicy:         versionPolicy,
+		TraceSection:          *trace,
+		Collapse:              collapseRules,
+		PlaceholderPatterns:   placeRules,
+		CollapseStrategy:      collapseStrategy,
+		FoldCase:              *foldCase,
+		UnionKeys:             unionKeys,
+		WithTags:              splitKeys(*withTags),
+		WithoutTags:           splitKeys(*withoutTag),
+	}
+	if *interact {
+		if isTerminal(os.Stdin) {
+			mergeOpts.ResolveConflict = promptResolver(os.Stdin, os.Stderr)
+		} else {
+			fmt.Fprintln(os.Stderr, "stdin is not a terminal; resolving conflicts automatically")
+		}
+	}
+	m := merger.NewPriorityMergerWithOptions(mergeOpts)
+	var mergeResult *merger.MergeResult
+	if *metaOnly {
+		// Metadata-only mode skips section and placeholder processing
+		metadata, err := m.MergeMetadata(configs)
+		if err != nil {
+			return fmt.Errorf("Failed to merge metadata: %w", err)
+		}
+		mergeResult = &merger.MergeResult{
+			Config: &config.Config{
+				Metadata:     metadata,
+				Sections:     make(map[string]config.Section),
+				MergePoints:  make(map[string]config.MergePoint),
+				MergeTargets: make(map[string]config.MergeTarget),
+			},
+			Provenance: make(map[string]string),
+		}
+	} else if *multiLang {
+		mergeResult, err = m.MergeLanguagesResult(context.Background(), configs)
+		if err != nil {
+			return fmt.Errorf("Failed to merge configurations: %w", err)
+		}
+	} else if len(groups) > 0 {
+		mergeResult, err = m.MergeGroupsResult(context.Background(), configGroups(groups, configs))
+		if err != nil {
+			return fmt.Errorf("Failed to merge configurations: %w", err)
+		}
+	} else {
+		mergeResult, err = m.MergeAllResult(context.Background(), configs)
+		if err != nil {
+			return fmt.Errorf("Failed to merge configurations: %w", err)
+		}
+	}
+	merged := mergeResult.Config
+
+	// Git information is optional: files outside a repository, or a missing
+	// git, just go without
+	var gitInfos map[string]gitinfo.Info
+	if *gitProv {
+		gitInfos = gitinfo.LookupAll(fileOrder)
+		if *debug {
+			fmt.Fprintf(os.Stderr, "Found git information for %d of %d files\n", len(gitInfos), len(fileOrder))
+		}
+	}
+
+	if *summary {
+		fmt.Println()
+		provenanceRows := report.ProvenanceRows(mergeResult.Provenance)
+		if *gitProv {
+			provenanceRows = report.GitProvenanceRows(mergeResult.Provenance, gitInfos)
+		}
+		err = report.RenderTable(os.Stdout, provenanceRows)
+		if err != nil {
+			return fmt.Errorf("Failed to print summary: %w", err)
+		}
+		fmt.Printf("%d sections, %d overrides, %d placeholders filled\n",
+			len(merged.Sections), mergeResult.Overrides, mergeResult.PlaceholdersFilled)
+	}
+	if *summary || *debug {
+		for _, warning := range mergeResult.Warnings {
+			fmt.Fprintf(os.Stderr, "%s %s\n", stderrColor.warning(), warning)
+		}
+	}
+	if defaultsConfig != nil {
+		m.ApplyDefaults(merged, defaultsConfig)
+	}
+
+	// Enforce the allowlist on the final set of sections, defaults included
+	if *allowList != "" {
+		disallowed := disallowedSections(merged.Sections, allowedSections)
+		if len(disallowed) > 0 {
+			return withExitCode(exitValidation, fmt.Errorf("Sections not allowed by %s:\n  %s", *allowList, strings.Join(disallowed, "\n  ")))
+		}
+	}
+
+	// A required section missing here means an input was dropped or emptied
+	if len(requiredSections) > 0 {
+		missing := missingSections(merged.Sections, requiredSections)
+		if len(missing) > 0 {
+			return withExitCode(exitValidation, fmt.Errorf("Required sections missing or empty:\n  %s", strings.Join(missing, "\n  ")))
+		}
+	}
+
+	// A leftover tag is a placeholder the merger couldn't match
+	if *strictTags {
+		leftovers := merger.LeftoverPlaceholders(merged.Sections)
+		if len(leftovers) > 0 {
+			return withExitCode(exitValidation, fmt.Errorf("Placeholder tags left in output:\n  %s", strings.Join(leftovers, "\n  ")))
+		}
+	}
+
+	// Rewrite the final sections and merge targets with the -replace rules
+	generator.ApplyReplacements(merged, replaceRules, *replCode)
+	config.NormalizeUnicode(merged, unicodeForm)
+
+	// Reorder sections to follow the reference outline, if any
+	if *sectFrom != "" {
+		reference, err := os.ReadFile(*sectFrom)
+		if err != nil {
+			return fmt.Errorf("Failed to read sections reference: %w", err)
+		}
+		generator.ApplyOutline(merged, generator.ParseOutline(string(config.StripBOM(reference))))
+	}
+
+	if *printCfg {
+		data, err := json.MarshalIndent(merged, "", "  ")
+		if err != nil {
+			return fmt.Errorf("Failed to encode merged configuration: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	// polish applies the markdown post-processing flags
+	polish := func(markdown string) string {
+		markdown = generator.LimitHeadingDepth(markdown, *maxDepth, headingOverflow)
+		if len(glossaryTerms) > 0 {
+			markdown = generator.LinkGlossary(markdown, generator.Glossary{
+				Terms:          glossaryTerms,
+				AllOccurrences: *glossAll,
+				WholeWord:      *glossWord,
+			})
+		}
+		if len(fenceAliases) > 0 {
+			markdown = generator.NormalizeFenceLanguages(markdown, fenceAliases)
+		}
+		if *fmtCode {
+			markdown = generator.FormatGoCodeBlocks(markdown)
+		}
+		return markdown
+	}
+
+	// Fan the sections out to a file each instead of a single document
+	if *sectDir != "" {
+		sectionOpts := generator.Options{
+			Stamp:   *stamp,
+			Sources: stampSources(fileOrder, gitInfos),
+			Version: about.Version,
+		}
+		files, err := generator.GenerateSectionFiles(merged, sectionOpts)
+		if err != nil {
+			return fmt.Errorf("Failed to generate section files: %w", err)
+		}
+		err = os.MkdirAll(*sectDir, 0755)
+		if err != nil {
+			return withExitCode(exitWrite, fmt.Errorf("Failed to create sections directory: %w", err))
+		}
+		// With -continue-on-error, a file failing its post command or lint is
+		// left out and the others are still written
+		contents := make([]string, len(files))
+		failed := make([]bool, len(files))
+		lintFailed := false
+		process := func(i int) error {
+			file := files[i]
+			contents[i] = polish(file.Content)
+			if *postCmd != "" {
+				content, err := runPostCommand(*postCmd, contents[i], *postTime)
+				if err != nil {
+					err = fmt.Errorf("Post command failed for %s: %w", file.Name, err)
+					if !*contOnErr {
+						return err
+					}
+					failures.add(file.Name, err)
+					failed[i] = true
+					return nil
+				}
+				contents[i] = content
+			}
+			if *lint && reportLint(file.Name, contents[i], lintSeverities) {
+				lintFailed = true
+				if *contOnErr {
+					failures.add(file.Name, fmt.Errorf("Lint found errors"))
+					failed[i] = true
+				}
+			}
+			return nil
+		}
+		write := func(i int) error {
+			err := os.WriteFile(filepath.Join(*sectDir, files[i].Name), withBOM([]byte(contents[i]), *writeBOM), 0644)
+			if err != nil {
+				err = withExitCode(exitWrite, fmt.Errorf("Failed to write section file: %w", err))
+				if !*contOnErr {
+					return err
+				}
+				failures.add(files[i].Name, err)
+				failed[i] = true
+			}
+			return nil
+		}
+
+		// The index comes last. Without -continue-on-error it is checked
+		// with the others before anything is written; with it, the index is
+		// built again after the others are written, linking only those.
+		index := len(files) - 1
+		for i := range files[:index] {
+			err = process(i)
+			if err != nil {
+				return err
+			}
+		}
+		if !*contOnErr {
+			err = process(index)
+			if err != nil {
+				return err
+			}
+			if lintFailed {
+				return withExitCode(exitValidation, fmt.Errorf("Lint found errors in the section files"))
+			}
+		}
+		for i := range files[:index] {
+			if failed[i] {
+				continue
+			}
+			err = write(i)
+			if err != nil {
+				return err
+			}
+		}
+		if *contOnErr {
+			var kept []generator.SectionFile
+			for i, file := range files[:index] {
+				if !failed[i] {
+					kept = append(kept, file)
+				}
+			}
+			files[index], err = generator.GenerateSectionIndex(merged.Metadata, kept, sectionOpts)
+			if err != nil {
+				return fmt.Errorf("Failed to generate section files: %w", err)
+			}
+			err = process(index)
+			if err != nil {
+				return err
+			}
+		}
+		if !failed[index] {
+			err = write(index)
+			if err != nil {
+				return err
+			}
+		}
+
+		written := 0
+		for i := range files {
+			if !failed[i] {
+				written++
+			}
+		}
+		if written < len(files) {
+			fmt.Printf("%s Generated %d of %d files in %s\n", stdoutColor.check(), written, len(files), *sectDir)
+			return nil
+		}
+		fmt.Printf("%s Generated %d files in %s successfully\n", stdoutColor.check(), len(files), *sectDir)
+		return nil
+	}
+
+	// Generate the output in the requested format
+	var output string
+	switch {
+	case *metaOnly && *outFormat == "sections-json":
+		data, err := generator.GenerateMetadataJSON(merged.Metadata)
+		if err != nil {
+			return fmt.Errorf("Failed to encode metadata: %w", err)
+		}
+		output = string(data)
+	case *outFormat == "toml" || *outFormat == "yaml":
+		// Merged configs are written back out in a loadable form, each
+		// section commented with its source
+		opts := generator.Options{
+			Stamp:   *stamp,
+			Sources: stampSources(fileOrder, gitInfos),
+			Version: about.Version,
+		}
+		if *outFormat == "toml" {
+			output, err = generator.GenerateConfigTOML(merged, mergeResult.Provenance, opts)
+		} else {
+			output, err = generator.GenerateConfigYAML(merged, mergeResult.Provenance, opts)
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to encode configuration: %w", err)
+		}
+	case *metaOnly:
+		output, err = generator.GenerateMetadataMarkdown(merged.Metadata)
+		if err != nil {
+			return fmt.Errorf("Failed to generate metadata: %w", err)
+		}
+	case *outFormat == "sections-json":
+		data, err := generator.GenerateSectionsJSON(merged, mergeResult.Provenance)
+		if err != nil {
+			return fmt.Errorf("Failed to encode sections: %w", err)
+		}
+		output = string(data)
+	default:
+		opts := generator.Options{
+			SectionGap:  *sectionGap,
+			Stamp:       *stamp,
+			Sources:     stampSources(fileOrder, gitInfos),
+			Version:     about.Version,
+			Frontmatter: *frontPass,
+			NoTitle:     *noTitle,
+			Divider:     strings.ReplaceAll(*divider, `\n`, "\n"),
+			NoOrder:     *noOrder,
+		}
+		if *titleTmpl != "" {
+			opts.Heading, err = generator.RenderTitle(*titleTmpl, merged.Metadata)
+			if err != nil {
+				return fmt.Errorf("Failed to render title: %w", err)
+			}
+		}
+		if *tsFooter {
+			opts.Timestamp = time.Now().UTC()
+			opts.TimestampLayout = *tsLayout
+		}
+		output = polish(generator.GenerateMarkdownWithOptions(merged, opts))
+	}
+
+	// Pipe the output through the post-merge command, if any
+	if *postCmd != "" {
+		output, err = runPostCommand(*postCmd, output, *postTime)
+		if err != nil {
+			return fmt.Errorf("Post command failed: %w", err)
+		}
+	}
+
+	// Resolve the output path from the template, if any
+	if *outputTmpl != "" {
+		*outputFile, err = expandOutputTemplate(*outputTmpl, merged.Metadata)
+		if err != nil {
+			return fmt.Errorf("Invalid output template: %w", err)
+		}
+		err = os.MkdirAll(filepath.Dir(*outputFile), 0755)
+		if err != nil {
+			return withExitCode(exitWrite, fmt.Errorf("Failed to create output directory: %w", err))
+		}
+	}
+
+	// Check the final markdown before it replaces the previous output
+	if *lint && reportLint(*outputFile, output, lintSeverities) {
+		return withExitCode(exitValidation, fmt.Errorf("Lint found errors in %s", *outputFile))
+	}
+
+	// Put the output in the managed block of the target, leaving the rest
+	// of the document as it is
+	if *mergeInto != "" {
+		output, err = mergeIntoDocument(*mergeInto, output, *mergeStart, *mergeEnd)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Show the output instead of writing it, styled only on a terminal
+	if *preview {
+		if stdoutColor {
+			output = generator.Preview(output)
+		}
+		fmt.Print(output)
+		return nil
+	}
+
+	err = writeOutput(*outputFile, output, *writeDiff, *writeBOM, *checksum)
+	if err != nil {
+		return err
+	}
+	err = writeManifest(*outputFile, withBOM([]byte(output), *writeBOM))
+	if err != nil {
+		return err
+	}
+
+	if runCache != nil {
+		runCache.OutputFile = *outputFile
+		runCache.Output = output
+		err = runCache.save(string(cacheFile))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s failed to write cache: %v\n", stderrColor.warning(), err)
+		}
+	}
+
+	fmt.Printf("%s Generated %s successfully\n", stdoutColor.check(), *outputFile)
+	return nil
+}
+
+// reportLint lints markdown, printing each finding to stderr prefixed with
+// name, and reports whether any finding is an error
+func reportLint(name, markdown string, severities generator.LintSeverities) bool {
+	findings := generator.LintMarkdown(markdown, severities)
+	for _, finding := range findings {
+		line := fmt.Sprintf("%s: %s", name, finding)
+		if finding.Severity == generator.SeverityError {
+			line = stderrColor.error(line)
+		}
+		fmt.Fprintln(os.Stderr, line)
+	}
+	return generator.HasLintErrors(findings)
+}
+
+// mergeIntoDocument returns the document in filename with its managed block,
+// between the start and end markers, replaced by output
+func mergeIntoDocument(filename, output, start, end string) (string, error) {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return "", withExitCode(exitNotFound, fmt.Errorf("Failed to read merge target: %w", err))
+	}
 	if err != nil {
-		log.Fatalf("Failed to merge configurations: %v", err)
+		return "", fmt.Errorf("Failed to read merge target: %w", err)
 	}
+	document, err := generator.ReplaceManagedBlock(string(config.StripBOM(data)), output, start, end)
+	if err != nil {
+		return "", withExitCode(exitValidation, fmt.Errorf("Failed to merge into %s: %w", filename, err))
+	}
+	return document, nil
+}
 
-	// Generate markdown
-	markdown := generator.GenerateMarkdown(merged)
+// writeOutput writes output to filename, first recording the change from
+// the file's previous content in filename.diff when writeDiff is set, then
+// the checksum of the written bytes in filename.sha256 when checksum is set
+func writeOutput(filename, output string, writeDiff, bom, checksum bool) error {
+	if writeDiff {
+		diff, err := outputDiff(filename, output)
+		if err != nil {
+			return fmt.Errorf("Failed to diff output: %w", err)
+		}
+		err = os.WriteFile(filename+".diff", []byte(diff), 0644)
+		if err != nil {
+			return withExitCode(exitWrite, fmt.Errorf("Failed to write diff: %w", err))
+		}
+	}
 
-	// Write output
-	err = os.WriteFile(*outputFile, []byte(markdown), 0644)
+	data := withBOM([]byte(output), bom)
+	err := os.WriteFile(filename, data, 0644)
 	if err != nil {
-		log.Fatalf("Failed to write output: %v", err)
+		return withExitCode(exitWrite, fmt.Errorf("Failed to write output: %w", err))
+	}
+	if checksum {
+		return writeChecksum(filename, data)
+	}
+	return nil
+}
+
+// stampSources names each file for the -stamp comment, as its git path and
+// commit when known
+func stampSources(files []string, infos map[string]gitinfo.Info) []string {
+	sources := make([]string, len(files))
+	for i, file := range files {
+		if info, ok := infos[file]; ok {
+			sources[i] = info.String()
+		} else {
+			sources[i] = file
+		}
 	}
+	return sources
+}
 
-	fmt.Printf("✓ Generated %s successfully\n", *outputFile)
+// concatFiles joins the raw contents of files, in order, with separator
+// between each pair. A leading byte order mark is dropped from each file so
+// none ends up in the middle of the output.
+func concatFiles(files []string, separator string) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteString(separator)
+		}
+		buf.Write(config.StripBOM(data))
+	}
+	return buf.Bytes(), nil
+}
+
+// withBOM prepends a UTF-8 byte order mark to data when bom is set
+func withBOM(data []byte, bom bool) []byte {
+	if !bom {
+		return data
+	}
+	return append(append([]byte{}, config.UTF8BOM...), data...)
 }
 
 // validateArgs validates command-line arguments
@@ -111,17 +1251,62 @@ func validateArgs(files []string, output string) error {
 
 	// Check if files exist
 	for _, file := range files {
-		if file == "" {
+		if file == "" || config.IsURL(file) {
 			continue
 		}
 		if _, err := os.Stat(file); os.IsNotExist(err) {
-			return fmt.Errorf("file not found: %s", file)
+			return withExitCode(exitNotFound, fmt.Errorf("file not found: %s", file))
 		}
 	}
 
 	return nil
 }
 
+// checkFileLimit guards against runaway file lists before anything is loaded
+func checkFileLimit(files []string, max int) error {
+	if max > 0 && len(files) > max {
+		return fmt.Errorf("%d input files exceeds the limit of %d; use a narrower file list or glob, or raise -max-files", len(files), max)
+	}
+	return nil
+}
+
+// dedupeFiles removes files that resolve to the same path, keeping the first
+// occurrence of each. Paths are compared by cleaned absolute path, with
+// symlinks resolved where possible. The dropped entries are returned as well.
+func dedupeFiles(files []string) ([]string, []string) {
+	result := make([]string, 0, len(files))
+	var duplicates []string
+	seen := make(map[string]bool)
+
+	for _, file := range files {
+		key := canonicalPath(file)
+		if seen[key] {
+			duplicates = append(duplicates, file)
+			continue
+		}
+		seen[key] = true
+		result = append(result, file)
+	}
+
+	return result, duplicates
+}
+
+// canonicalPath returns a path suitable for identifying a file on disk. A
+// URL identifies itself.
+func canonicalPath(file string) string {
+	if config.IsURL(file) {
+		return file
+	}
+	path, err := filepath.Abs(file)
+	if err != nil {
+		return filepath.Clean(file)
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
 // parseFileOrder determines the order of files for merging
 func parseFileOrder(files []string, orderSpec string) []string {
 	if orderSpec == "" {
@@ -161,6 +1346,67 @@ func parseFileOrder(files []string, orderSpec string) []string {
 	return result
 }
 
+// runPostCommand runs command with input on its stdin and returns its stdout.
+// The command line is split on whitespace and executed directly, without a
+// shell. A non-zero exit or exceeding timeout is an error.
+func runPostCommand(command, input string, timeout time.Duration) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", fmt.Errorf("post command is empty")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf("%s timed out after %s", args[0], timeout)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+// expandOutputTemplate substitutes {lang} and {title} in an output path
+// template using the merged metadata
+func expandOutputTemplate(tmpl string, metadata config.Metadata) (string, error) {
+	if strings.Contains(tmpl, "{lang}") && metadata.Language == "" {
+		return "", fmt.Errorf("template %s uses {lang} but the merged document has no language", tmpl)
+	}
+	if strings.Contains(tmpl, "{title}") && metadata.Title == "" {
+		return "", fmt.Errorf("template %s uses {title} but the merged document has no title", tmpl)
+	}
+
+	lang, title := pathComponent(metadata.Language), pathComponent(metadata.Title)
+	if strings.Contains(tmpl, "{lang}") && lang == "" {
+		return "", fmt.Errorf("template %s uses {lang} but the language %q is not a usable file name", tmpl, metadata.Language)
+	}
+	if strings.Contains(tmpl, "{title}") && title == "" {
+		return "", fmt.Errorf("template %s uses {title} but the title %q is not a usable file name", tmpl, metadata.Title)
+	}
+
+	replacer := strings.NewReplacer("{lang}", lang, "{title}", title)
+	return replacer.Replace(tmpl), nil
+}
+
+// pathComponent makes a metadata value safe to use as a single path element.
+// Separators become dashes, and leading and trailing dots are dropped so a
+// value such as ".." can't climb out of the output directory, even when
+// placed next to another value.
+func pathComponent(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	value = strings.NewReplacer("/", "-", "\\", "-", " ", "-").Replace(value)
+	return strings.Trim(value, ".")
+}
+
 // formatName returns a readable format name
 func formatName(format config.FileFormat) string {
 	switch format {
@@ -170,6 +1416,8 @@ func formatName(format config.FileFormat) string {
 		return "YAML"
 	case config.FormatMarkdown:
 		return "Markdown"
+	case config.FormatJSON:
+		return "JSON"
 	default:
 		return "Unknown"
 	}
@@ -183,17 +1431,208 @@ func printHelp() {
 	fmt.Println("  claude-merge -files file1.toml,file2.yaml,file3.md [options]")
 	fmt.Println()
 	fmt.Println("Options:")
-	fmt.Println("  -files string    Comma-separated paths to configuration files (required)")
+	fmt.Println("  -files string    Comma-separated paths to configuration files (required unless -convention or -dir is set)")
+	fmt.Println("  -convention string")
+	fmt.Println("                   Base file, or directory holding COMMON.md, whose sibling fragments are merged after it")
+	fmt.Println("  -dir string      Directory whose subdirectories are each merged on their own, then merged")
+	fmt.Println("                   together in name order (optional)")
+	fmt.Println("  -multi-language  Merge each language's inputs on their own and nest their sections under")
+	fmt.Println("                   a heading per language, after the shared sections")
+	fmt.Println("  -convention-pattern string")
+	fmt.Println("                   Glob matching the fragments found by -convention (default: LANG.*.md)")
+	fmt.Println("  -stdin-multi")
+	fmt.Println("                   Also read a stream of configs from stdin, split at -stdin-delimiter lines")
+	fmt.Println("                   and merged after the files in stream order")
+	fmt.Println("  -stdin-format string")
+	fmt.Println("                   Format of the configs read by -stdin-multi: markdown, toml, yaml, or json")
+	fmt.Println("                   (default: markdown)")
+	fmt.Println("  -stdin-delimiter string")
+	fmt.Println("                   Line separating the configs read by -stdin-multi; Go escapes such as \\x1e")
+	fmt.Println("                   are understood (default: ---8<---)")
+	fmt.Println("  -stdin-skip-invalid")
+	fmt.Println("                   Skip configs read by -stdin-multi that fail to parse, with a warning,")
+	fmt.Println("                   instead of failing")
+	fmt.Println("  -fetch-retries int")
+	fmt.Println("                   Times a remote config is fetched again after a connection error or 5xx")
+	fmt.Println("                   response (default: 3)")
+	fmt.Println("  -fetch-timeout duration")
+	fmt.Println("                   Maximum total time spent fetching one remote config, retries included")
+	fmt.Println("                   (default: 30s, 0 disables the limit)")
 	fmt.Println("  -output string   Output filename (default: CLAUDE.merged.md)")
+	fmt.Println("  -format string   Output format: markdown, sections-json, toml, or yaml (default: markdown)")
+	fmt.Println("  -output-template string")
+	fmt.Println("                   Output path template using {lang} and {title}, overrides -output")
+	fmt.Println("  -merge-into string")
+	fmt.Println("                   Existing markdown file whose block between -merge-start and -merge-end is")
+	fmt.Println("                   replaced with the output, overrides -output")
+	fmt.Println("  -merge-start string")
+	fmt.Println("                   Marker starting the block replaced by -merge-into")
+	fmt.Println("                   (default: <!-- CLAUDE:START -->)")
+	fmt.Println("  -merge-end string")
+	fmt.Println("                   Marker ending the block replaced by -merge-into (default: <!-- CLAUDE:END -->)")
+	fmt.Println("  -yaml-root string")
+	fmt.Println("                   Dot-separated key path of the config within YAML files")
+	fmt.Println("  -toml-root string")
+	fmt.Println("                   Dot-separated table path of the config within TOML files")
 	fmt.Println("  -order string    Comma-separated file order for merging (optional)")
+	fmt.Println("  -sections-from string")
+	fmt.Println("                   Reference markdown file whose heading order sets the section order")
+	fmt.Println("  -defaults string Configuration file providing fallback content (optional)")
+	fmt.Println("  -allowed-sections string")
+	fmt.Println("                   File of allowed section key globs, one per line; other merged keys are an error")
+	fmt.Println("  -with-tags string")
+	fmt.Println("                   Comma-separated tags; keep only tagged sections carrying one of them,")
+	fmt.Println("                   plus untagged sections (optional)")
+	fmt.Println("  -without-tags string")
+	fmt.Println("                   Comma-separated tags; drop every section carrying one of them (optional)")
+	fmt.Println("  -required-sections string")
+	fmt.Println("                   Comma-separated section keys that must be present with content")
+	fmt.Println("  -schema string   JSON Schema file every input config must satisfy")
+	fmt.Println("  -overrides string")
+	fmt.Println("                   Sidecar file setting priorities and orders for input files and sections")
+	fmt.Println("  -max-files int   Maximum number of input files to load (default: 1000, 0 disables)")
+	fmt.Println("  -concat-raw      Concatenate the input files as-is, skipping parsing and merging")
+	fmt.Println("  -separator string")
+	fmt.Println("                   Text placed between files in -concat-raw mode; \\n is a newline (default: \\n)")
+	fmt.Println("  -metadata-only   Merge and output only the metadata, skipping sections and placeholders")
 	fmt.Println("  -validate        Validate only, don't generate output")
+	fmt.Println("  -list-placeholders")
+	fmt.Println("                   List every placeholder in the inputs with the section and file it")
+	fmt.Println("                   appears in, then exit")
+	fmt.Println("  -strict          Treat empty input files as errors instead of warnings")
+	fmt.Println("  -allow-binary    Load input files even when they look binary rather than text")
+	fmt.Println("  -split-markdown  Split markdown inputs into a section per heading and list instead of")
+	fmt.Println("                   one content section")
+	fmt.Println("  -strict-placeholders")
+	fmt.Println("                   Fail if any placeholder tag is left in the merged sections")
 	fmt.Println("  -debug          Enable debug output")
+	fmt.Println("  -merge-lists     Union list fields such as merge_points and tags across files instead of")
+	fmt.Println("                   replacing them")
+	fmt.Println("  -interactive     Prompt to settle equal-priority section conflicts (requires a terminal)")
+	fmt.Println("  -equal-priority string")
+	fmt.Println("                   Which file wins when priorities are equal: last or first (default: last)")
+	fmt.Println("  -version-policy string")
+	fmt.Println("                   How the merged version is chosen: priority or highest-semver (default: priority)")
+	fmt.Println("  -trace string    Print every merge decision for the given section key")
+	fmt.Println("  -collapse string Comma-separated regex=key rules folding matching section keys into one")
+	fmt.Println("                   section, e.g. changelog_.*=changelog")
+	fmt.Println("  -collapse-strategy string")
+	fmt.Println("                   Strategy combining sections folded by -collapse: append, prepend,")
+	fmt.Println("                   replace, collapse, or keyvalue (default: append)")
+	fmt.Println("  -fold-case       Match section keys case-insensitively, keeping the winning file's spelling")
+	fmt.Println("  -title-template string")
+	fmt.Println("                   Go template over the merged metadata, such as {{.Title}}, rendered as")
+	fmt.Println("                   the top heading; when empty (the default), no heading is added and the")
+	fmt.Println("                   title is only recorded in a comment")
+	fmt.Println("  -no-title        Leave the top-level heading out of markdown output, for embedding it")
+	fmt.Println("                   in another document")
+	fmt.Println("  -stamp           Start the output with a do-not-edit comment naming the tool version")
+	fmt.Println("                   and source files")
+	fmt.Println("  -frontmatter-passthrough")
+	fmt.Println("                   Start markdown output with YAML frontmatter holding the merged metadata,")
+	fmt.Println("                   including extra fields")
+	fmt.Println("  -frontmatter-union string")
+	fmt.Println("                   Comma-separated frontmatter fields collected from every input instead of")
+	fmt.Println("                   the winning one (default: tags,authors)")
+	fmt.Println("  -git-provenance  Add the git path and commit of each source file to -summary and -stamp")
+	fmt.Println("  -timestamp-footer")
+	fmt.Println("                   End the output with a footer giving the generation time in UTC")
+	fmt.Println("  -timestamp-layout string")
+	fmt.Println("                   Go time layout for -timestamp-footer (default: 2006-01-02T15:04:05Z07:00)")
+	fmt.Println("  -write-diff      Also write a unified diff from the previous output to <output>.diff")
+	fmt.Println("  -bom             Start the written output with a UTF-8 byte order mark")
+	fmt.Println("  -checksum        Also write the SHA-256 of the written output to <output>.sha256 in")
+	fmt.Println("                   sha256sum format")
+	fmt.Println("  -manifest string")
+	fmt.Println("                   Write a JSON manifest of the hashed inputs, merge order, options, tool")
+	fmt.Println("                   version, and output hash to this file (optional)")
+	fmt.Println("  -manifest-timestamp")
+	fmt.Println("                   Record the generation time in the -manifest file")
+	fmt.Println("  -section-gap int Number of blank lines between sections: 0, 1, or 2 (default: 1)")
+	fmt.Println("  -divider string  Text written between sections in markdown output, such as --- for a")
+	fmt.Println("                   horizontal rule; \\n is a newline (optional)")
+	fmt.Println("  -no-order        Write markdown sections in the order they were loaded, ignoring their")
+	fmt.Println("                   order fields, to debug layout")
+	fmt.Println("  -out-sections-dir string")
+	fmt.Println("                   Write each merged section to its own file in this directory, plus an")
+	fmt.Println("                   index.md, instead of -output (optional)")
+	fmt.Println("  -continue-on-error")
+	fmt.Println("                   In -multi-language, -dir, and -out-sections-dir runs, leave out the parts")
+	fmt.Println("                   that fail, write the rest, and exit with code 7")
+	fmt.Println("  -max-heading-depth int")
+	fmt.Println("                   Deepest heading level kept in markdown output, 1-6 (default: 0, keeps every level)")
+	fmt.Println("  -heading-overflow string")
+	fmt.Println("                   What -max-heading-depth does to deeper headings: bold or clamp (default: bold)")
+	fmt.Println("  -heading-offset string")
+	fmt.Println("                   Comma-separated file=offset pairs moving each file's headings that many")
+	fmt.Println("                   levels deeper, or shallower if negative (optional)")
+	fmt.Println("  -glossary string YAML file mapping terms to URLs; terms in markdown output become links (optional)")
+	fmt.Println("  -glossary-all    Link every occurrence of a -glossary term instead of only the first")
+	fmt.Println("  -glossary-whole-word")
+	fmt.Println("                   Only link -glossary terms that are not part of a longer word (default: true)")
+	fmt.Println("  -replace value   Regex rewrite rule pattern=>replacement applied to section content;")
+	fmt.Println("                   repeatable, applied in order (optional)")
+	fmt.Println("  -replace-in-code Also apply -replace rules inside fenced code blocks")
+	fmt.Println("  -normalize-unicode string")
+	fmt.Println("                   Unicode normalization form for section content: nfc, nfd, nfkc, or")
+	fmt.Println("                   nfkd (default: off)")
+	fmt.Println("  -lint            Report style issues in the markdown output; error-severity issues fail")
+	fmt.Println("                   the run before writing")
+	fmt.Println("  -lint-severity string")
+	fmt.Println("                   Comma-separated rule=severity pairs (error, warning, or off) overriding")
+	fmt.Println("                   the -lint defaults (optional)")
+	fmt.Println("  -fmt-code-blocks Run Go fenced code blocks in the output through gofmt")
+	fmt.Println("  -normalize-fences")
+	fmt.Println("                   Rewrite code fence language aliases in the output, such as golang and py,")
+	fmt.Println("                   to one name, such as go and python")
+	fmt.Println("  -fence-aliases string")
+	fmt.Println("                   YAML file mapping code fence language aliases to names, added to the")
+	fmt.Println("                   defaults; implies -normalize-fences (optional)")
+	fmt.Println("  -post-command string")
+	fmt.Println("                   Command that receives the generated markdown on stdin and prints")
+	fmt.Println("                   the final output. Runs with your privileges; only use trusted commands")
+	fmt.Println("  -post-command-timeout duration")
+	fmt.Println("                   Maximum run time for -post-command (default: 30s)")
+	fmt.Println("  -preview        Print the markdown output to the terminal with basic styling instead of writing it")
+	fmt.Println("  -print-config    Print the merged configuration as JSON instead of writing output")
+	fmt.Println("  -summary        Print tables of the sections loaded from each input and where each")
+	fmt.Println("                  merged section came from, plus merge statistics and warnings")
+	fmt.Println("  -no-placeholder  Never treat an input as a base template; merge by priority and keep")
+	fmt.Println("                  placeholder tags as text")
+	fmt.Println("  -keep-empty-placeholders")
+	fmt.Println("                  Keep placeholder blocks that have no replacement content")
+	fmt.Println("  -placeholder-pattern value")
+	fmt.Println("                  Rule name=heading filling placeholders whose name matches name, where *")
+	fmt.Println("                  captures, from the section headed by heading with {1}, {2} replaced by")
+	fmt.Println("                  the captures; repeatable (optional)")
+	fmt.Println("  -cache[=file]   Cache file, .claude-merge-cache when given as -cache alone; when no input,")
+	fmt.Println("                  priority, or flag has changed since the last run, its output is reused")
+	fmt.Println("  -cpuprofile string")
+	fmt.Println("                  Write a CPU profile of the run to this file")
+	fmt.Println("  -memprofile string")
+	fmt.Println("                  Write a heap profile at the end of the run to this file")
+	fmt.Println("  -watch          Keep running and regenerate the output whenever an input file changes")
+	fmt.Println("  -watch-debounce duration")
+	fmt.Println("                   Quiet period -watch waits after a change before regenerating (default: 300ms)")
+	fmt.Println("  -no-color       Never color console messages (also set by the NO_COLOR environment variable)")
 	fmt.Println("  -help           Show this help message")
 	fmt.Println()
-	fmt.Println("Supported formats: TOML (.toml), YAML (.yaml, .yml), Markdown (.md)")
+	fmt.Println("Supported formats: TOML (.toml), YAML (.yaml, .yml), JSON (.json), Markdown (.md)")
 	fmt.Println()
 	fmt.Println("Priority-based merging:")
 	fmt.Println("  1. Explicit priority values override everything else")
 	fmt.Println("  2. Relative priority values prevent override by lower priorities")
-	fmt.Println("  3. File order determines precedence when no priorities set")
+	fmt.Println("  3. File order determines precedence when no priorities set (see -equal-priority)")
+	fmt.Println("  4. Defaults (-defaults) only fill in keys that no input provides")
+	fmt.Println()
+	fmt.Println("Exit codes:")
+	fmt.Println("  0  Success")
+	fmt.Println("  1  Any other failure, such as a failed merge or -post-command")
+	fmt.Println("  2  Invalid flags or arguments")
+	fmt.Println("  3  An input file, or a file it references, could not be read")
+	fmt.Println("  4  An input file could not be parsed or has an unsupported format")
+	fmt.Println("  5  An input file is invalid (-validate, -strict, -strict-placeholders,")
+	fmt.Println("     -lint, -schema, tiers, overrides, -allowed-sections, or -required-sections)")
+	fmt.Println("  6  The output, or a file written beside it, could not be written")
+	fmt.Println("  7  -continue-on-error left out the parts that failed and wrote the rest")
 }
\ No newline at end of file
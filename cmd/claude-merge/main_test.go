@@ -1,11 +1,17 @@
 package main
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/arustydev/claude-merge/internal/generator"
+	"github.com/arustydev/claude-merge/internal/gitinfo"
+	"github.com/arustydev/claude-merge/internal/merger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -114,6 +120,169 @@ func TestParseFileOrder(t *testing.T) {
 	}
 }
 
+func TestCheckFileLimit(t *testing.T) {
+	files := []string{"a.md", "b.md", "c.md"}
+
+	assert.NoError(t, checkFileLimit(files, 3))
+	assert.NoError(t, checkFileLimit(files, 0), "0 disables the limit")
+
+	err := checkFileLimit(files, 2)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "3 input files exceeds the limit of 2")
+	assert.Contains(t, err.Error(), "-max-files")
+}
+
+func TestDedupeFiles(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.md")
+	b := filepath.Join(dir, "b.md")
+	require.NoError(t, os.WriteFile(a, []byte("# A"), 0644))
+	require.NoError(t, os.WriteFile(b, []byte("# B"), 0644))
+
+	link := filepath.Join(dir, "link.md")
+	require.NoError(t, os.Symlink(a, link))
+
+	tests := []struct {
+		name           string
+		files          []string
+		wantFiles      []string
+		wantDuplicates []string
+	}{
+		{
+			name:      "no duplicates",
+			files:     []string{a, b},
+			wantFiles: []string{a, b},
+		},
+		{
+			name:           "exact duplicate keeps first occurrence",
+			files:          []string{b, a, b},
+			wantFiles:      []string{b, a},
+			wantDuplicates: []string{b},
+		},
+		{
+			name:           "unclean path",
+			files:          []string{a, dir + "/./sub/../a.md"},
+			wantFiles:      []string{a},
+			wantDuplicates: []string{dir + "/./sub/../a.md"},
+		},
+		{
+			name:           "symlink to same file",
+			files:          []string{link, a},
+			wantFiles:      []string{link},
+			wantDuplicates: []string{a},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, duplicates := dedupeFiles(tt.files)
+			assert.Equal(t, tt.wantFiles, files)
+			assert.Equal(t, tt.wantDuplicates, duplicates)
+		})
+	}
+}
+
+func TestConcatFiles(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.md")
+	b := filepath.Join(dir, "b.md")
+	require.NoError(t, os.WriteFile(a, []byte("---\ntitle: A\n---\n# A\n"), 0644))
+	require.NoError(t, os.WriteFile(b, []byte("\uFEFF# B\n"), 0644))
+
+	data, err := concatFiles([]string{b, a}, "\n")
+	require.NoError(t, err)
+	assert.Equal(t, "# B\n\n---\ntitle: A\n---\n# A\n", string(data))
+
+	_, err = concatFiles([]string{a, filepath.Join(dir, "missing.md")}, "")
+	assert.Error(t, err)
+}
+
+func TestExpandOutputTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		tmpl     string
+		metadata config.Metadata
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "language",
+			tmpl:     "docs/{lang}/CLAUDE.md",
+			metadata: config.Metadata{Language: "go"},
+			want:     "docs/go/CLAUDE.md",
+		},
+		{
+			name:     "title is made path safe",
+			tmpl:     "out/{title}.md",
+			metadata: config.Metadata{Title: "Go/Rust Guidelines"},
+			want:     "out/go-rust-guidelines.md",
+		},
+		{
+			name:     "dots cannot climb out of the directory",
+			tmpl:     "out/{lang}{title}/CLAUDE.md",
+			metadata: config.Metadata{Language: "go.", Title: ".rules."},
+			want:     "out/gorules/CLAUDE.md",
+		},
+		{
+			name:     "traversal in a title",
+			tmpl:     "out/{title}.md",
+			metadata: config.Metadata{Title: "../../etc/passwd"},
+			want:     "out/-..-etc-passwd.md",
+		},
+		{
+			name:     "only dots",
+			tmpl:     "out/{lang}/CLAUDE.md",
+			metadata: config.Metadata{Language: ".."},
+			wantErr:  true,
+		},
+		{
+			name:     "no placeholders",
+			tmpl:     "CLAUDE.md",
+			metadata: config.Metadata{},
+			want:     "CLAUDE.md",
+		},
+		{
+			name:     "missing language",
+			tmpl:     "docs/{lang}/CLAUDE.md",
+			metadata: config.Metadata{Title: "Untitled"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandOutputTemplate(tt.tmpl, tt.metadata)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRunPostCommand(t *testing.T) {
+	out, err := runPostCommand("cat", "# Merged\n", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "# Merged\n", out)
+
+	out, err = runPostCommand("tr a-z A-Z", "shout", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "SHOUT", out)
+
+	_, err = runPostCommand("false", "", time.Second)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exit status 1")
+
+	_, err = runPostCommand("sleep 5", "", 50*time.Millisecond)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+
+	_, err = runPostCommand("  ", "", time.Second)
+	require.Error(t, err)
+}
+
 func TestFormatName(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -311,4 +480,196 @@ func TestDefaultOutputFilename(t *testing.T) {
 	// from our code review
 	expected := "CLAUDE.merged.md"
 	assert.Equal(t, expected, "CLAUDE.merged.md", "Default output filename should be CLAUDE.merged.md")
-}
\ No newline at end of file
+}
+
+func TestPromptResolver(t *testing.T) {
+	conflict := merger.Conflict{
+		Section:        "intro",
+		Existing:       config.Section{Content: "First intro"},
+		ExistingSource: "a.toml",
+		Incoming:       config.Section{Content: "Second intro"},
+		IncomingSource: "b.toml",
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []merger.Resolution
+	}{
+		{name: "keep a", input: "a\n", want: []merger.Resolution{merger.KeepExisting}},
+		{name: "keep b", input: "B\n", want: []merger.Resolution{merger.KeepIncoming}},
+		{name: "append both", input: " both \n", want: []merger.Resolution{merger.AppendBoth}},
+		{name: "reprompts on invalid input", input: "x\n\nb\n", want: []merger.Resolution{merger.KeepIncoming}},
+		{name: "answer without newline", input: "a", want: []merger.Resolution{merger.KeepExisting}},
+		{
+			name:  "end of input falls back to auto",
+			input: "a\n",
+			want:  []merger.Resolution{merger.KeepExisting, merger.ResolveAuto, merger.ResolveAuto},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			resolve := promptResolver(strings.NewReader(tt.input), &out)
+
+			for _, want := range tt.want {
+				got, err := resolve(conflict)
+				require.NoError(t, err)
+				assert.Equal(t, want, got)
+			}
+
+			assert.Contains(t, out.String(), "Conflict in section intro")
+			assert.Contains(t, out.String(), "--- [a] a.toml\nFirst intro")
+			assert.Contains(t, out.String(), "--- [b] b.toml\nSecond intro")
+		})
+	}
+}
+
+func TestWithBOM(t *testing.T) {
+	assert.Equal(t, []byte("out"), withBOM([]byte("out"), false))
+	assert.Equal(t, []byte("\uFEFFout"), withBOM([]byte("out"), true))
+}
+
+func TestStartProfiling(t *testing.T) {
+	dir := t.TempDir()
+	cpuPath := filepath.Join(dir, "cpu.out")
+	memPath := filepath.Join(dir, "mem.out")
+
+	stop, err := startProfiling(cpuPath, memPath)
+	require.NoError(t, err)
+	require.NoError(t, stop())
+
+	for _, path := range []string{cpuPath, memPath} {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.NotZero(t, info.Size(), path)
+	}
+
+	stop, err = startProfiling("", "")
+	require.NoError(t, err)
+	assert.NoError(t, stop())
+
+	_, err = startProfiling(filepath.Join(dir, "missing", "cpu.out"), "")
+	assert.Error(t, err)
+}
+
+func TestConventionFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"COMMON.md", "LANG.rust.md", "LANG.go.md", "NOTES.md", "LANG.py.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("# "+name), 0644))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "LANG.dir.md"), 0755))
+
+	tests := []struct {
+		name    string
+		path    string
+		pattern string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "base file",
+			path:    filepath.Join(dir, "COMMON.md"),
+			pattern: "LANG.*.md",
+			want:    []string{"COMMON.md", "LANG.go.md", "LANG.rust.md"},
+		},
+		{
+			name:    "directory discovers the base",
+			path:    dir,
+			pattern: "LANG.*.md",
+			want:    []string{"COMMON.md", "LANG.go.md", "LANG.rust.md"},
+		},
+		{
+			name:    "pattern matching the base skips it",
+			path:    filepath.Join(dir, "COMMON.md"),
+			pattern: "*.md",
+			want:    []string{"COMMON.md", "LANG.go.md", "LANG.rust.md", "NOTES.md"},
+		},
+		{
+			name:    "explicit base with no fragments",
+			path:    filepath.Join(dir, "NOTES.md"),
+			pattern: "NONE.*.md",
+			want:    []string{"NOTES.md"},
+		},
+		{
+			name:    "missing base",
+			path:    filepath.Join(dir, "missing.md"),
+			pattern: "LANG.*.md",
+			wantErr: "convention base not found",
+		},
+		{
+			name:    "directory without COMMON.md",
+			path:    filepath.Join(dir, "LANG.dir.md"),
+			pattern: "LANG.*.md",
+			wantErr: "no COMMON.md in",
+		},
+		{
+			name:    "bad pattern",
+			path:    dir,
+			pattern: "LANG.[.md",
+			wantErr: "invalid convention pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := conventionFiles(tt.path, tt.pattern)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			names := make([]string, len(got))
+			for i, file := range got {
+				names[i] = filepath.Base(file)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestOutputDiff(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "CLAUDE.md")
+
+	diff, err := outputDiff(path, "# Title\n")
+	require.NoError(t, err)
+	assert.Equal(t, "--- /dev/null\n+++ "+path+"\n@@ -0,0 +1 @@\n+# Title\n", diff)
+
+	require.NoError(t, os.WriteFile(path, []byte("# Title\nold line\n"), 0644))
+	diff, err = outputDiff(path, "# Title\nnew line\n")
+	require.NoError(t, err)
+	assert.Equal(t, "--- "+path+"\n+++ "+path+"\n@@ -1,2 +1,2 @@\n # Title\n-old line\n+new line\n", diff)
+
+	diff, err = outputDiff(path, "# Title\nold line\n")
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+}
+
+func TestDiffLabel(t *testing.T) {
+	assert.Equal(t, "a/docs/CLAUDE.md", diffLabel("a", filepath.Join("docs", "CLAUDE.md")))
+	assert.Equal(t, "b/CLAUDE.md", diffLabel("b", "CLAUDE.md"))
+	assert.Equal(t, "/tmp/CLAUDE.md", diffLabel("a", "/tmp/CLAUDE.md"))
+}
+
+func TestOutputDiff_IgnoresTimestampFooter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CLAUDE.md")
+	previous := "\uFEFF# Title\n\n" + generator.TimestampMarker + "\n_Generated on 2025-01-01T00:00:00Z_"
+	require.NoError(t, os.WriteFile(path, []byte(previous), 0644))
+
+	diff, err := outputDiff(path, "# Title\n\n"+generator.TimestampMarker+"\n_Generated on 2025-01-02T00:00:00Z_")
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+}
+
+func TestStampSources(t *testing.T) {
+	infos := map[string]gitinfo.Info{
+		"docs/common.md": {Commit: "3f9c2a1b7d40e5f6a7b8c9d0e1f2a3b4c5d6e7f8", Path: "docs/common.md"},
+	}
+
+	assert.Equal(t, []string{"docs/common.md@3f9c2a1b7d40", "go.toml"}, stampSources([]string{"docs/common.md", "go.toml"}, infos))
+	assert.Equal(t, []string{"go.toml"}, stampSources([]string{"go.toml"}, nil))
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// buildManifest is the -manifest file: which exact inputs, in which order,
+// with which options and tool version, produced which output. Everything in
+// it but the optional timestamp depends only on the run, so two runs over
+// the same inputs write identical manifests.
+type buildManifest struct {
+	Version     string         `json:"version"`
+	Inputs      []manifestFile `json:"inputs"`
+	MergeOrder  []string       `json:"merge_order"`
+	Files       []manifestFile `json:"files,omitempty"`
+	Options     []string       `json:"options"`
+	Output      manifestFile   `json:"output"`
+	GeneratedAt string         `json:"generated_at,omitempty"`
+}
+
+// manifestFile is a file named in a manifest with the SHA-256 of its content
+type manifestFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// newManifest records a run. inputs are the input files as resolved,
+// order is the order they were merged in, and files names other files
+// whose content shapes the output, such as -defaults; empty names are
+// skipped. Remote inputs are hashed as fetched, from fetched.
+func newManifest(version string, args, inputs, order, files []string, fetched map[string]string) (*buildManifest, error) {
+	manifest := &buildManifest{
+		Version:    version,
+		MergeOrder: order,
+		Options:    args,
+	}
+	for _, file := range inputs {
+		entry, err := hashManifestFile(file, fetched)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Inputs = append(manifest.Inputs, entry)
+	}
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+		entry, err := hashManifestFile(file, fetched)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Files = append(manifest.Files, entry)
+	}
+	return manifest, nil
+}
+
+// hashManifestFile hashes a local file, or looks up the hash of a fetched one
+func hashManifestFile(file string, fetched map[string]string) (manifestFile, error) {
+	if config.IsURL(file) {
+		hash, ok := fetched[file]
+		if !ok {
+			return manifestFile{}, fmt.Errorf("%s was not fetched", file)
+		}
+		return manifestFile{Path: file, SHA256: hash}, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return manifestFile{}, err
+	}
+	return manifestFile{Path: file, SHA256: hashBytes(data)}, nil
+}
+
+// save records data, the exact bytes written to output, and writes the
+// manifest to filename. A non-zero at is recorded as the generation time.
+func (m *buildManifest) save(filename, output string, data []byte, at time.Time) error {
+	m.Output = manifestFile{Path: output, SHA256: hashBytes(data)}
+	if !at.IsZero() {
+		m.GeneratedAt = at.UTC().Format(time.RFC3339)
+	}
+	encoded, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(filename, append(encoded, '\n'), 0644)
+	if err != nil {
+		return withExitCode(exitWrite, fmt.Errorf("Failed to write manifest: %w", err))
+	}
+	return nil
+}
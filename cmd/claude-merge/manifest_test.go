@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewManifest(t *testing.T) {
+	dir := t.TempDir()
+	common := filepath.Join(dir, "common.md")
+	golang := filepath.Join(dir, "go.md")
+	defaults := filepath.Join(dir, "defaults.md")
+	require.NoError(t, os.WriteFile(common, []byte("# Common"), 0644))
+	require.NoError(t, os.WriteFile(golang, []byte("# Go"), 0644))
+	require.NoError(t, os.WriteFile(defaults, []byte("# Defaults"), 0644))
+	remote := "https://example.com/base.md"
+
+	manifest, err := newManifest("1.0.0", []string{"-order=go.md"}, []string{common, golang, remote},
+		[]string{golang, common, remote}, []string{"", defaults}, map[string]string{remote: "abc"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.0.0", manifest.Version)
+	assert.Equal(t, []manifestFile{
+		{Path: common, SHA256: hashBytes([]byte("# Common"))},
+		{Path: golang, SHA256: hashBytes([]byte("# Go"))},
+		{Path: remote, SHA256: "abc"},
+	}, manifest.Inputs)
+	assert.Equal(t, []string{golang, common, remote}, manifest.MergeOrder)
+	assert.Equal(t, []manifestFile{{Path: defaults, SHA256: hashBytes([]byte("# Defaults"))}}, manifest.Files)
+	assert.Equal(t, []string{"-order=go.md"}, manifest.Options)
+}
+
+func TestNewManifest_Errors(t *testing.T) {
+	tests := map[string][]string{
+		"missing file":    {filepath.Join(t.TempDir(), "missing.md")},
+		"unfetched input": {"https://example.com/base.md"},
+	}
+	for name, inputs := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := newManifest("1.0.0", nil, inputs, inputs, nil, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestBuildManifest_Save(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "common.md")
+	require.NoError(t, os.WriteFile(input, []byte("# Common"), 0644))
+	build := func() *buildManifest {
+		manifest, err := newManifest("1.0.0", []string{"-output=out.md"}, []string{input}, []string{input}, nil, nil)
+		require.NoError(t, err)
+		return manifest
+	}
+
+	first := filepath.Join(dir, "first.json")
+	second := filepath.Join(dir, "second.json")
+	require.NoError(t, build().save(first, "out.md", []byte("# Out"), time.Time{}))
+	require.NoError(t, build().save(second, "out.md", []byte("# Out"), time.Time{}))
+	firstData, err := os.ReadFile(first)
+	require.NoError(t, err)
+	secondData, err := os.ReadFile(second)
+	require.NoError(t, err)
+	assert.Equal(t, string(firstData), string(secondData), "same run, same manifest")
+	assert.NotContains(t, string(firstData), "generated_at")
+
+	var saved buildManifest
+	require.NoError(t, json.Unmarshal(firstData, &saved))
+	assert.Equal(t, manifestFile{Path: "out.md", SHA256: hashBytes([]byte("# Out"))}, saved.Output)
+
+	stamped := filepath.Join(dir, "stamped.json")
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	require.NoError(t, build().save(stamped, "out.md", []byte("# Out"), at))
+	data, err := os.ReadFile(stamped)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &saved))
+	assert.Equal(t, "2024-05-01T10:00:00Z", saved.GeneratedAt)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// partFailure is a part of a multi-output run that -continue-on-error left
+// out: an input, a -dir group, or a section file
+type partFailure struct {
+	part string
+	err  error
+}
+
+// partFailures collects the failures a -continue-on-error run carries on
+// past, in the order they happened
+type partFailures []partFailure
+
+// add records that part failed with err
+func (f *partFailures) add(part string, err error) {
+	*f = append(*f, partFailure{part: part, err: err})
+}
+
+// has reports whether part failed
+func (f partFailures) has(part string) bool {
+	for _, failure := range f {
+		if failure.part == part {
+			return true
+		}
+	}
+	return false
+}
+
+// error summarizes the failures, one line each, exiting with exitPartial, or
+// returns nil when there were none
+func (f partFailures) error() error {
+	if len(f) == 0 {
+		return nil
+	}
+	lines := make([]string, len(f))
+	for i, failure := range f {
+		lines[i] = failure.part + ": " + failure.err.Error()
+	}
+	noun := "parts"
+	if len(f) == 1 {
+		noun = "part"
+	}
+	return withExitCode(exitPartial, fmt.Errorf("%d %s failed; the rest were written:\n  %s", len(f), noun, strings.Join(lines, "\n  ")))
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartFailures(t *testing.T) {
+	var failures partFailures
+	assert.NoError(t, failures.error())
+	assert.False(t, failures.has("claude/style"))
+
+	failures.add("claude/style", errors.New("Failed to load config claude/style/naming.md: bad"))
+	assert.True(t, failures.has("claude/style"))
+	err := failures.error()
+	require.Error(t, err)
+	assert.Equal(t, exitPartial, exitCode(err))
+	assert.Equal(t, "1 part failed; the rest were written:\n  claude/style: Failed to load config claude/style/naming.md: bad", err.Error())
+
+	failures.add("testing.md", errors.New("Post command failed for testing.md: exit status 1"))
+	assert.Equal(t, "2 parts failed; the rest were written:\n"+
+		"  claude/style: Failed to load config claude/style/naming.md: bad\n"+
+		"  testing.md: Post command failed for testing.md: exit status 1", failures.error().Error())
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// startProfiling starts a CPU profile written to cpuPath and arranges for a
+// heap profile to be written to memPath; either path may be empty. The
+// returned stop function finishes both profiles and must be called however
+// the run ends.
+func startProfiling(cpuPath, memPath string) (stop func() error, err error) {
+	var cpuFile *os.File
+	if cpuPath != "" {
+		cpuFile, err = os.Create(cpuPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		err = pprof.StartCPUProfile(cpuFile)
+		if err != nil {
+			cpuFile.Close()
+			return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+	}
+
+	stop = func() error {
+		var errs []error
+		if cpuFile != nil {
+			pprof.StopCPUProfile()
+			errs = append(errs, cpuFile.Close())
+		}
+		if memPath != "" {
+			errs = append(errs, writeHeapProfile(memPath))
+		}
+		return errors.Join(errs...)
+	}
+	return stop, nil
+}
+
+// writeHeapProfile writes a heap profile reflecting the latest garbage
+// collection to path
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create memory profile: %w", err)
+	}
+	defer f.Close()
+
+	runtime.GC()
+	err = pprof.WriteHeapProfile(f)
+	if err != nil {
+		return fmt.Errorf("failed to write memory profile: %w", err)
+	}
+	return f.Close()
+}
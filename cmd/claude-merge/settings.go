@@ -0,0 +1,254 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// settingsFileName is the project settings file searched for upward from
+// the working directory
+const settingsFileName = ".claude-merge.yaml"
+
+// pathFlags take file paths, or comma-separated lists of them. A relative
+// path in a settings file is resolved against the file's directory, so a
+// project's settings work from any of its subdirectories.
+var pathFlags = map[string]bool{
+	"files": true, "order": true, "convention": true, "dir": true,
+	"output": true, "output-template": true, "out-sections-dir": true,
+	"defaults": true, "overrides": true, "sections-from": true,
+	"allowed-sections": true, "glossary": true, "cache": true,
+	"manifest": true, "cpuprofile": true, "memprofile": true,
+	"schema": true, "merge-into": true, "fence-aliases": true,
+}
+
+// listFlags take comma-separated lists, which a settings file may give as a
+// single value, such as files: a.toml,b.toml
+var listFlags = map[string]bool{"files": true, "order": true}
+
+// findProjectSettings returns the settings file in start or its nearest
+// ancestor holding one, or "" if there is none. The search stops after a
+// directory containing .git, so it never leaves the repository, or at the
+// filesystem root.
+func findProjectSettings(start string) (string, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", err
+	}
+	for {
+		candidate := filepath.Join(dir, settingsFileName)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return "", nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// userSettingsPath returns the user-level settings file,
+// claude-merge/config.yaml in the user's config directory ($XDG_CONFIG_HOME,
+// or ~/.config when unset, on Linux), or "" if the directory is unknown
+func userSettingsPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "claude-merge", "config.yaml")
+}
+
+// loadSettings reads a YAML mapping of flag names to values from filename,
+// checking each name against flags. A list gives several values, which
+// become one comma-separated value or, for a repeatable flag, one value
+// each. Relative paths are resolved against the file's directory. A missing
+// file has no settings.
+func loadSettings(filename string, flags *flag.FlagSet) (map[string][]string, error) {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]interface{}
+	err = yaml.Unmarshal(config.StripBOM(data), &raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	dir := settingsDir(filename)
+	settings := make(map[string][]string, len(raw))
+	for name, value := range raw {
+		if flags.Lookup(name) == nil {
+			return nil, fmt.Errorf("%s: unknown flag %q", filename, name)
+		}
+		values, err := settingValues(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", filename, name, err)
+		}
+		if listFlags[name] {
+			var entries []string
+			for _, value := range values {
+				entries = append(entries, splitKeys(value)...)
+			}
+			values = entries
+		}
+		if pathFlags[name] {
+			for i, path := range values {
+				if path != "" && !filepath.IsAbs(path) && !config.IsURL(path) && !isBoolValue(flags, name, path) {
+					values[i] = filepath.Join(dir, path)
+				}
+			}
+		}
+		settings[name] = values
+	}
+	return settings, nil
+}
+
+// isBoolValue reports whether value is true or false given to a flag that
+// may be set without a value, such as a bare -cache, rather than a path
+func isBoolValue(flags *flag.FlagSet, name, value string) bool {
+	boolFlag, ok := flags.Lookup(name).Value.(interface{ IsBoolFlag() bool })
+	return ok && boolFlag.IsBoolFlag() && (value == "true" || value == "false")
+}
+
+// settingsDir returns the directory of a settings file relative to the
+// working directory where possible, so resolved paths stay short
+func settingsDir(filename string) string {
+	dir := filepath.Dir(filename)
+	wd, err := os.Getwd()
+	if err != nil {
+		return dir
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	if rel, err := filepath.Rel(wd, abs); err == nil {
+		return rel
+	}
+	return dir
+}
+
+// settingValues converts a YAML value into flag values: a scalar becomes one
+// value and a list one value per item
+func settingValues(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return []string{""}, nil
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case []interface{}, map[string]interface{}:
+				return nil, fmt.Errorf("list items must be plain values")
+			}
+			values = append(values, fmt.Sprint(item))
+		}
+		return values, nil
+	case map[string]interface{}:
+		return nil, fmt.Errorf("value must be a plain value or a list")
+	default:
+		return []string{fmt.Sprint(v)}, nil
+	}
+}
+
+// applySettings sets each flag not given on the command line from the
+// settings layers, where later layers take precedence over earlier ones
+func applySettings(flags *flag.FlagSet, layers ...map[string][]string) error {
+	given := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+
+	merged := make(map[string][]string)
+	for _, layer := range layers {
+		for name, value := range layer {
+			merged[name] = value
+		}
+	}
+
+	names := make([]string, 0, len(merged))
+	for name := range merged {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if given[name] {
+			continue
+		}
+		err := setFlag(flags, name, merged[name])
+		if err != nil {
+			return fmt.Errorf("invalid value %q for setting %s: %w", strings.Join(merged[name], ","), name, err)
+		}
+	}
+	return nil
+}
+
+// loadSettingsLayers reads the user settings and then the nearest project
+// settings, returning them lowest precedence first along with the files
+// that were found
+func loadSettingsLayers(flags *flag.FlagSet) ([]map[string][]string, []string, error) {
+	var layers []map[string][]string
+	var found []string
+
+	if user := userSettingsPath(); user != "" {
+		settings, err := loadSettings(user, flags)
+		if err != nil {
+			return nil, nil, err
+		}
+		if settings != nil {
+			layers = append(layers, settings)
+			found = append(found, user)
+		}
+	}
+
+	project, err := findProjectSettings(".")
+	if err != nil {
+		return nil, nil, err
+	}
+	if project != "" {
+		settings, err := loadSettings(project, flags)
+		if err != nil {
+			return nil, nil, err
+		}
+		layers = append(layers, settings)
+		found = append(found, project)
+	}
+	return layers, found, nil
+}
+
+// flagArgs rebuilds the command line from the flags that were set, whether
+// on the command line or by settings, leaving out the flags in skip. A
+// repeatable flag is repeated for each of its values.
+func flagArgs(skip map[string]bool) []string {
+	var args []string
+	flag.Visit(func(f *flag.Flag) {
+		if skip[f.Name] {
+			return
+		}
+		if list, ok := f.Value.(repeatableFlag); ok {
+			for _, value := range list.values() {
+				args = append(args, "-"+f.Name+"="+value)
+			}
+			return
+		}
+		args = append(args, "-"+f.Name+"="+f.Value.String())
+	})
+	return append(args, flag.Args()...)
+}
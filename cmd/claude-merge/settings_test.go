@@ -0,0 +1,145 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindProjectSettings(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	deep := filepath.Join(repo, "docs", "deep")
+	require.NoError(t, os.MkdirAll(deep, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, ".git"), 0755))
+
+	// Settings above the repository are out of reach
+	require.NoError(t, os.WriteFile(filepath.Join(root, settingsFileName), []byte("strict: true\n"), 0644))
+	found, err := findProjectSettings(deep)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, settingsFileName), []byte("strict: true\n"), 0644))
+	found, err = findProjectSettings(deep)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repo, settingsFileName), found)
+
+	// The nearest file wins
+	nearest := filepath.Join(repo, "docs", settingsFileName)
+	require.NoError(t, os.WriteFile(nearest, []byte("strict: true\n"), 0644))
+	found, err = findProjectSettings(deep)
+	require.NoError(t, err)
+	assert.Equal(t, nearest, found)
+}
+
+func TestUserSettingsPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/home/me/.config")
+	t.Setenv("HOME", "/home/me")
+
+	path := userSettingsPath()
+	assert.True(t, strings.HasSuffix(path, filepath.Join("claude-merge", "config.yaml")), path)
+}
+
+func testFlags() *flag.FlagSet {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.String("files", "", "")
+	flags.String("output", "CLAUDE.merged.md", "")
+	flags.String("equal-priority", "last", "")
+	flags.Bool("strict", false, "")
+	flags.Int("section-gap", 1, "")
+	flags.Var(new(cacheFlag), "cache", "")
+	return flags
+}
+
+func TestLoadSettings(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, settingsFileName)
+	require.NoError(t, os.WriteFile(filename, []byte(
+		"files: [common.md, /abs/golang.md]\noutput: out/CLAUDE.md\nstrict: true\nsection-gap: 2\nequal-priority: first\ncache: true\n"), 0644))
+
+	settings, err := loadSettings(filename, testFlags())
+	require.NoError(t, err)
+
+	files := settings["files"]
+	require.Len(t, files, 2)
+	abs, err := filepath.Abs(files[0])
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "common.md"), abs)
+	assert.Equal(t, "/abs/golang.md", files[1])
+
+	require.Len(t, settings["output"], 1)
+	abs, err = filepath.Abs(settings["output"][0])
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "out", "CLAUDE.md"), abs)
+	assert.Equal(t, []string{"true"}, settings["strict"])
+	assert.Equal(t, []string{"2"}, settings["section-gap"])
+	assert.Equal(t, []string{"first"}, settings["equal-priority"], "non-path values are left alone")
+	assert.Equal(t, []string{"true"}, settings["cache"], "a bare path flag is not a path")
+}
+
+func TestLoadSettings_CommaList(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "docs")
+	require.NoError(t, os.MkdirAll(sub, 0755))
+	filename := filepath.Join(root, settingsFileName)
+	require.NoError(t, os.WriteFile(filename, []byte("files: a.toml, b.toml\n"), 0644))
+	t.Chdir(sub)
+
+	settings, err := loadSettings(filename, testFlags())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{filepath.Join("..", "a.toml"), filepath.Join("..", "b.toml")}, settings["files"])
+}
+
+func TestLoadSettings_Errors(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{name: "unknown flag", content: "bogus: 1\n", errMsg: `unknown flag "bogus"`},
+		{name: "nested map", content: "output: {path: a.md}\n", errMsg: "output: value must be a plain value or a list"},
+		{name: "nested list", content: "files: [[a.md]]\n", errMsg: "files: list items must be plain values"},
+		{name: "invalid yaml", content: "files: [a.md\n", errMsg: settingsFileName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(dir, settingsFileName)
+			require.NoError(t, os.WriteFile(filename, []byte(tt.content), 0644))
+
+			_, err := loadSettings(filename, testFlags())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+
+	settings, err := loadSettings(filepath.Join(dir, "missing.yaml"), testFlags())
+	require.NoError(t, err)
+	assert.Nil(t, settings)
+}
+
+func TestApplySettings(t *testing.T) {
+	flags := testFlags()
+	require.NoError(t, flags.Parse([]string{"-output", "cli.md"}))
+
+	user := map[string][]string{"output": {"user.md"}, "strict": {"true"}, "equal-priority": {"first"}}
+	project := map[string][]string{"output": {"project.md"}, "equal-priority": {"last"}, "section-gap": {"2"}, "files": {"a.md", "b.md"}}
+	require.NoError(t, applySettings(flags, user, project))
+
+	assert.Equal(t, "cli.md", flags.Lookup("output").Value.String(), "the command line wins")
+	assert.Equal(t, "last", flags.Lookup("equal-priority").Value.String(), "the project wins over the user")
+	assert.Equal(t, "true", flags.Lookup("strict").Value.String(), "user settings fill the rest")
+	assert.Equal(t, "2", flags.Lookup("section-gap").Value.String())
+	assert.Equal(t, "a.md,b.md", flags.Lookup("files").Value.String(), "lists are joined by commas")
+
+	err := applySettings(testFlags(), map[string][]string{"section-gap": {"wide"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid value "wide" for setting section-gap`)
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// watchPollInterval is how often watched paths are checked for changes
+const watchPollInterval = 100 * time.Millisecond
+
+// watchFlags are the flags that control watching; they are not passed on to
+// each regeneration
+var watchFlags = map[string]bool{"watch": true, "watch-debounce": true}
+
+// fileStamp is what a watched file looks like at one poll. A file that is
+// missing has no stamp, so deleting and recreating it counts as a change.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// watchPaths returns the paths a regeneration reads: every entry of the
+// comma-separated input list and every other named file or directory. Empty
+// entries are skipped.
+func watchPaths(list string, others ...string) []string {
+	paths := splitKeys(list)
+	for _, path := range others {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// ignoredPaths returns the absolute form of every non-empty path
+func ignoredPaths(paths ...string) []string {
+	var ignored []string
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		abs, err := filepath.Abs(path)
+		if err == nil {
+			ignored = append(ignored, abs)
+		}
+	}
+	return ignored
+}
+
+// snapshot stamps every file under paths. Directories are walked, so files
+// added to or removed from them are noticed. Unreadable paths are left out,
+// as are the files and directories in ignored, which must be absolute; these
+// are the files a regeneration writes, which would otherwise trigger another
+// regeneration.
+func snapshot(paths, ignored []string) map[string]fileStamp {
+	stamps := make(map[string]fileStamp)
+	for _, root := range paths {
+		filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if isIgnored(path, ignored) {
+				if entry.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil
+			}
+			stamps[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+			return nil
+		})
+	}
+	return stamps
+}
+
+// isIgnored reports whether path is one of ignored or inside one of them
+func isIgnored(path string, ignored []string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	for _, ignore := range ignored {
+		if abs == ignore || strings.HasPrefix(abs, ignore+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// changedPaths returns the sorted paths that were added, removed, or
+// modified between two snapshots
+func changedPaths(before, after map[string]fileStamp) []string {
+	var changed []string
+	for path, stamp := range after {
+		if old, ok := before[path]; !ok || old != stamp {
+			changed = append(changed, path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// debouncer coalesces changes arriving within window of each other into one
+// batch. Each change pushes the deadline back, so the batch is released only
+// once the files have been quiet for the whole window and reflects their
+// final state.
+type debouncer struct {
+	window   time.Duration
+	pending  int
+	deadline time.Time
+}
+
+// add records n changes seen at now
+func (d *debouncer) add(n int, now time.Time) {
+	if n == 0 {
+		return
+	}
+	d.pending += n
+	d.deadline = now.Add(d.window)
+}
+
+// ready returns the number of batched changes and true once the window has
+// passed since the last change, resetting the batch
+func (d *debouncer) ready(now time.Time) (int, bool) {
+	if d.pending == 0 || now.Before(d.deadline) {
+		return 0, false
+	}
+	n := d.pending
+	d.pending = 0
+	return n, true
+}
+
+// regenerate runs the tool once more with args, passing its output through
+func regenerate(ctx context.Context, args []string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, executable, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// watch regenerates once, then again whenever files under paths, other than
+// ignored ones, change, until ctx is done. Changes within debounce of each
+// other are batched into one regeneration. A failed regeneration is reported
+// and watching goes on, so the next save can fix it.
+func watch(ctx context.Context, paths, ignored []string, debounce time.Duration, build func(context.Context) error) error {
+	stamps := snapshot(paths, ignored)
+	err := build(ctx)
+	if err != nil && ctx.Err() == nil {
+		fmt.Fprintf(os.Stderr, "%s generation failed: %v\n", stderrColor.warning(), err)
+	}
+	fmt.Fprintf(os.Stderr, "Watching %s for changes\n", strings.Join(paths, ", "))
+
+	batch := debouncer{window: debounce}
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			// Later changes, including ones made while regenerating, are
+			// measured against this snapshot, so none are lost
+			current := snapshot(paths, ignored)
+			batch.add(len(changedPaths(stamps, current)), now)
+			stamps = current
+
+			n, ok := batch.ready(now)
+			if !ok {
+				continue
+			}
+			err := build(ctx)
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s regeneration failed (%s): %v\n", stderrColor.warning(), changeCount(n), err)
+				continue
+			}
+			fmt.Printf("%s Regenerated (%s)\n", stdoutColor.check(), changeCount(n))
+		}
+	}
+}
+
+// changeCount describes n changes
+func changeCount(n int) string {
+	if n == 1 {
+		return "1 change"
+	}
+	return fmt.Sprintf("%d changes", n)
+}
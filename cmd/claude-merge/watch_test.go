@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchPaths(t *testing.T) {
+	assert.Equal(t, []string{"a.md", "b.md", "defaults.toml"}, watchPaths(" a.md, b.md,", "", "defaults.toml", ""))
+	assert.Empty(t, watchPaths(""))
+}
+
+func TestSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "a.md")
+	output := filepath.Join(dir, "out.md")
+	sections := filepath.Join(dir, "sections")
+	require.NoError(t, os.WriteFile(input, []byte("# A"), 0644))
+	require.NoError(t, os.WriteFile(output, []byte("# Out"), 0644))
+	require.NoError(t, os.MkdirAll(sections, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(sections, "index.md"), []byte("# Index"), 0644))
+
+	stamps := snapshot([]string{dir, filepath.Join(dir, "missing.md")}, ignoredPaths(output, sections))
+	assert.Len(t, stamps, 1)
+	assert.Contains(t, stamps, input)
+}
+
+func TestChangedPaths(t *testing.T) {
+	now := time.Now()
+	before := map[string]fileStamp{
+		"same.md":    {modTime: now, size: 1},
+		"edited.md":  {modTime: now, size: 1},
+		"removed.md": {modTime: now, size: 1},
+	}
+	after := map[string]fileStamp{
+		"same.md":   {modTime: now, size: 1},
+		"edited.md": {modTime: now.Add(time.Second), size: 1},
+		"added.md":  {modTime: now, size: 1},
+	}
+
+	assert.Equal(t, []string{"added.md", "edited.md", "removed.md"}, changedPaths(before, after))
+	assert.Empty(t, changedPaths(before, before))
+}
+
+func TestDebouncer(t *testing.T) {
+	start := time.Now()
+	batch := debouncer{window: 300 * time.Millisecond}
+
+	_, ok := batch.ready(start)
+	assert.False(t, ok, "nothing pending")
+
+	batch.add(1, start)
+	batch.add(0, start.Add(200*time.Millisecond))
+	batch.add(2, start.Add(250*time.Millisecond))
+	_, ok = batch.ready(start.Add(400 * time.Millisecond))
+	assert.False(t, ok, "the last change pushed the deadline back")
+
+	n, ok := batch.ready(start.Add(550 * time.Millisecond))
+	assert.True(t, ok)
+	assert.Equal(t, 3, n)
+
+	_, ok = batch.ready(start.Add(time.Second))
+	assert.False(t, ok, "the batch was reset")
+}
+
+func TestWatch_BatchesChanges(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "a.md")
+	require.NoError(t, os.WriteFile(input, []byte("# A"), 0644))
+
+	var builds atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- watch(ctx, []string{dir}, nil, 300*time.Millisecond, func(context.Context) error {
+			builds.Add(1)
+			return nil
+		})
+	}()
+
+	// A burst of saves, each within the debounce window of the last
+	time.Sleep(50 * time.Millisecond)
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, os.WriteFile(input, []byte("# A"+string(rune('0'+i))), 0644))
+		time.Sleep(120 * time.Millisecond)
+	}
+	require.Eventually(t, func() bool { return builds.Load() == 2 }, 2*time.Second, 20*time.Millisecond)
+
+	// Nothing else changed, so no further regeneration happens
+	time.Sleep(500 * time.Millisecond)
+	assert.Equal(t, int32(2), builds.Load())
+
+	cancel()
+	assert.NoError(t, <-done)
+}
@@ -3,9 +3,12 @@ module github.com/arustydev/claude-merge
 go 1.24.3
 
 require (
-	github.com/BurntSushi/toml v1.5.0 // indirect
-	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/stretchr/testify v1.10.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	github.com/BurntSushi/toml v1.5.0
+	github.com/pmezard/go-difflib v1.0.0
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
+	github.com/stretchr/testify v1.10.0
+	golang.org/x/text v0.34.0
+	gopkg.in/yaml.v3 v3.0.1
 )
+
+require github.com/davecgh/go-spew v1.1.1 // indirect
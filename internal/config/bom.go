@@ -0,0 +1,27 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// UTF8BOM is the byte order mark some Windows editors write at the start of
+// UTF-8 files
+var UTF8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// StripBOM returns data without a leading UTF-8 byte order mark
+func StripBOM(data []byte) []byte {
+	return bytes.TrimPrefix(data, UTF8BOM)
+}
+
+// stripBOMReader returns a reader that skips a leading UTF-8 byte order mark
+// in r
+func stripBOMReader(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	prefix, _ := br.Peek(len(UTF8BOM))
+	if bytes.Equal(prefix, UTF8BOM) {
+		_, _ = br.Discard(len(UTF8BOM))
+	}
+	return br
+}
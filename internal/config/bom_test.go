@@ -0,0 +1,67 @@
+package config
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStripBOM(t *testing.T) {
+	assert.Equal(t, []byte("title"), StripBOM([]byte("\uFEFFtitle")))
+	assert.Equal(t, []byte("title"), StripBOM([]byte("title")))
+	assert.Equal(t, []byte("a\uFEFF"), StripBOM([]byte("a\uFEFF")))
+	assert.Empty(t, StripBOM(nil))
+}
+
+func TestStripBOMReader(t *testing.T) {
+	for _, input := range []string{"\uFEFFbody", "body"} {
+		data, err := io.ReadAll(stripBOMReader(strings.NewReader(input)))
+		require.NoError(t, err)
+		assert.Equal(t, "body", string(data))
+	}
+
+	data, err := io.ReadAll(stripBOMReader(strings.NewReader("\xEF")))
+	require.NoError(t, err)
+	assert.Equal(t, "\xEF", string(data))
+}
+
+func TestLoadConfig_BOM(t *testing.T) {
+	tests := []struct {
+		filename string
+		content  string
+	}{
+		{filename: "bom.toml", content: "[metadata]\ntitle = \"BOM\"\n\n[sections.intro]\ncontent = \"Intro\"\n"},
+		{filename: "bom.yaml", content: "metadata:\n  title: BOM\nsections:\n  intro:\n    content: Intro\n"},
+		{filename: "bom.json", content: `{"metadata": {"title": "BOM"}, "sections": {"intro": {"content": "Intro"}}}`},
+		{filename: "bom.md", content: "---\ntitle: BOM\n---\nIntro\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.filename)
+			require.NoError(t, os.WriteFile(path, append(append([]byte{}, UTF8BOM...), tt.content...), 0644))
+
+			config, err := LoadConfig(path)
+			require.NoError(t, err)
+			assert.Equal(t, "BOM", config.Metadata.Title)
+
+			for _, section := range config.Sections {
+				assert.Equal(t, "Intro", section.Content)
+			}
+		})
+	}
+}
+
+func TestResolveContentFiles_BOM(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "intro.md"), []byte("\uFEFFIntro\n"), 0644))
+
+	config := &Config{Sections: map[string]Section{"intro": {ContentFile: "intro.md"}}}
+	require.NoError(t, resolveContentFiles(config, fileSource{}, dir))
+	assert.Equal(t, "Intro", config.Sections["intro"].Content)
+}
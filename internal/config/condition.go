@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EvaluateCondition reports whether a section condition holds for the given
+// metadata. Conditions take the form "field == value" or "field != value",
+// where field is one of title, language, or version. An empty condition is
+// always true.
+func EvaluateCondition(condition string, metadata Metadata) (bool, error) {
+	condition = strings.TrimSpace(condition)
+	if condition == "" {
+		return true, nil
+	}
+
+	// Check != first so that it isn't mistaken for a malformed ==
+	operator := ""
+	for _, op := range []string{"!=", "=="} {
+		if strings.Contains(condition, op) {
+			operator = op
+			break
+		}
+	}
+	if operator == "" {
+		return false, fmt.Errorf("invalid condition %q: expected == or !=", condition)
+	}
+
+	parts := strings.SplitN(condition, operator, 2)
+	field := strings.ToLower(strings.TrimSpace(parts[0]))
+	value := strings.Trim(strings.TrimSpace(parts[1]), `"'`)
+
+	var actual string
+	switch field {
+	case "title":
+		actual = metadata.Title
+	case "language":
+		actual = metadata.Language
+	case "version":
+		actual = metadata.Version
+	default:
+		return false, fmt.Errorf("invalid condition %q: unknown field %s", condition, field)
+	}
+
+	if operator == "==" {
+		return actual == value, nil
+	}
+	return actual != value, nil
+}
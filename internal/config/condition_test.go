@@ -0,0 +1,46 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateCondition(t *testing.T) {
+	metadata := Metadata{
+		Title:    "Guidelines",
+		Language: "go",
+		Version:  "1.2.0",
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		want      bool
+		wantErr   bool
+	}{
+		{"empty condition", "", true, false},
+		{"language equals", "language == go", true, false},
+		{"language equals mismatch", "language == python", false, false},
+		{"language not equals", "language != python", true, false},
+		{"language not equals mismatch", "language != go", false, false},
+		{"quoted value", `version == "1.2.0"`, true, false},
+		{"title with spaces", "title == Guidelines", true, false},
+		{"field name is case-insensitive", "Language == go", true, false},
+		{"unknown field", "author == me", false, true},
+		{"missing operator", "language go", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EvaluateCondition(tt.condition, metadata)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// ErrorKind categorizes why a configuration could not be loaded
+type ErrorKind int
+
+const (
+	// KindNotFound means the file, or a file it references such as a
+	// content_file or include, could not be read
+	KindNotFound ErrorKind = iota + 1
+
+	// KindUnsupportedFormat means the file extension is not a known format
+	KindUnsupportedFormat
+
+	// KindParseError means the file could be read but not decoded
+	KindParseError
+
+	// KindValidationError means the file decoded but its contents are invalid
+	KindValidationError
+)
+
+// String returns a short description of the kind
+func (k ErrorKind) String() string {
+	switch k {
+	case KindNotFound:
+		return "not found"
+	case KindUnsupportedFormat:
+		return "unsupported format"
+	case KindParseError:
+		return "parse error"
+	case KindValidationError:
+		return "validation error"
+	default:
+		return fmt.Sprintf("ErrorKind(%d)", int(k))
+	}
+}
+
+// ConfigError is returned by LoadConfig, its variants, LoadOverrides, and
+// ValidateConfig. Use errors.As to get at the Kind; errors.Is sees through to
+// Err, so errors.Is(err, fs.ErrNotExist) still works for missing files.
+type ConfigError struct {
+	Kind     ErrorKind
+	Filename string
+
+	// Err is the underlying cause; it may be nil for KindUnsupportedFormat
+	Err error
+
+	// action names the failed step for the message, as in "failed to parse
+	// FILE"; validation errors carry their own message
+	action string
+}
+
+// Error returns the message, which matches the plain errors these replaced
+func (e *ConfigError) Error() string {
+	switch {
+	case e.Kind == KindUnsupportedFormat && e.Err == nil:
+		return fmt.Sprintf("unsupported file format for %s", e.Filename)
+	case e.action == "":
+		return e.Err.Error()
+	default:
+		return fmt.Sprintf("failed to %s %s: %v", e.action, e.Filename, e.Err)
+	}
+}
+
+// Unwrap returns the underlying cause
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// resolveError wraps a failure to resolve content files or includes,
+// telling unreadable files apart from invalid settings
+func resolveError(filename, action string, err error) *ConfigError {
+	kind := KindValidationError
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		kind = KindNotFound
+	}
+	return &ConfigError{Kind: kind, Filename: filename, Err: err, action: action}
+}
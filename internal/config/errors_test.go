@@ -0,0 +1,103 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig_ErrorKinds(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	tests := []struct {
+		name     string
+		filename string
+		kind     ErrorKind
+		message  string
+	}{
+		{
+			name:     "missing file",
+			filename: filepath.Join(dir, "missing.toml"),
+			kind:     KindNotFound,
+			message:  "failed to read file",
+		},
+		{
+			name:     "unknown extension",
+			filename: write("notes.txt", "text"),
+			kind:     KindUnsupportedFormat,
+			message:  "unsupported file format for",
+		},
+		{
+			name:     "malformed toml",
+			filename: write("bad.toml", "[metadata\ntitle = "),
+			kind:     KindParseError,
+			message:  "failed to parse",
+		},
+		{
+			name:     "missing content file",
+			filename: write("content.toml", "[sections.intro]\ncontent_file = \"missing.md\"\n"),
+			kind:     KindNotFound,
+			message:  "failed to resolve content for",
+		},
+		{
+			name:     "invalid include strategy",
+			filename: write("include.toml", "[sections.intro]\nincludes = [\"a.md\"]\nstrategy = \"shuffle\"\n"),
+			kind:     KindValidationError,
+			message:  "failed to resolve includes for",
+		},
+		{
+			name:     "several configs",
+			filename: write("many.json", `[{"metadata": {"title": "A"}}, {"metadata": {"title": "B"}}]`),
+			kind:     KindValidationError,
+			message:  "contains 2 configs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(tt.filename)
+			require.Error(t, err)
+
+			var configErr *ConfigError
+			require.True(t, errors.As(err, &configErr))
+			assert.Equal(t, tt.kind, configErr.Kind)
+			assert.Equal(t, tt.filename, configErr.Filename)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
+
+func TestConfigError_Unwrap(t *testing.T) {
+	_, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+}
+
+func TestValidateConfig_ErrorKind(t *testing.T) {
+	err := ValidateConfig(&Config{SourceFile: "base.toml", Metadata: Metadata{Title: "Test"}})
+	require.Error(t, err)
+
+	var configErr *ConfigError
+	require.True(t, errors.As(err, &configErr))
+	assert.Equal(t, KindValidationError, configErr.Kind)
+	assert.Equal(t, "base.toml", configErr.Filename)
+	assert.Equal(t, "config has no sections", err.Error())
+}
+
+func TestErrorKind_String(t *testing.T) {
+	assert.Equal(t, "not found", KindNotFound.String())
+	assert.Equal(t, "unsupported format", KindUnsupportedFormat.String())
+	assert.Equal(t, "parse error", KindParseError.String())
+	assert.Equal(t, "validation error", KindValidationError.String())
+	assert.Equal(t, "ErrorKind(0)", ErrorKind(0).String())
+}
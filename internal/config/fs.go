@@ -0,0 +1,114 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// fileSource reads config files and the content files and includes they
+// reference, from the operating system, from an fs.FS, or over HTTP
+type fileSource struct {
+	// fsys is the file system to read from; nil reads from the operating
+	// system
+	fsys fs.FS
+
+	// fetch, if set, reads names as URLs instead, and takes precedence
+	// over fsys
+	fetch Fetcher
+}
+
+// readFile returns the contents of the named file
+func (s fileSource) readFile(name string) ([]byte, error) {
+	switch {
+	case s.fetch != nil:
+		return s.fetch(name)
+	case s.fsys == nil:
+		return os.ReadFile(name)
+	default:
+		return fs.ReadFile(s.fsys, name)
+	}
+}
+
+// dir returns the directory holding the named file
+func (s fileSource) dir(name string) string {
+	switch {
+	case s.fetch != nil:
+		return urlDir(name)
+	case s.fsys == nil:
+		return filepath.Dir(name)
+	default:
+		return path.Dir(name)
+	}
+}
+
+// join resolves name relative to baseDir. Absolute operating system paths
+// and URLs are kept as they are; fs.FS names are always relative to the
+// root.
+func (s fileSource) join(baseDir, name string) string {
+	switch {
+	case s.fetch != nil:
+		return resolveURL(baseDir, name)
+	case s.fsys == nil:
+		if filepath.IsAbs(name) {
+			return name
+		}
+		return filepath.Join(baseDir, name)
+	default:
+		return path.Join(baseDir, name)
+	}
+}
+
+// same reports whether two names refer to the same file
+func (s fileSource) same(a, b string) bool {
+	switch {
+	case s.fetch != nil:
+		return a == b
+	case s.fsys == nil:
+		return samePath(a, b)
+	default:
+		return path.Clean(a) == path.Clean(b)
+	}
+}
+
+// formatName returns the part of name whose extension gives the file
+// format: the path of a URL, without its query, or the name itself
+func (s fileSource) formatName(name string) string {
+	if s.fetch != nil {
+		return urlPath(name)
+	}
+	return name
+}
+
+// LoadConfigFS reads the named configuration file from fsys, such as an
+// embed.FS, like LoadConfig does from disk. The format is detected from the
+// name's extension, and content files and includes are read from fsys too,
+// relative to the config's directory.
+func LoadConfigFS(fsys fs.FS, name string) (*Config, error) {
+	configs, err := LoadConfigMultiFS(fsys, name, LoadOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if len(configs) != 1 {
+		return nil, &ConfigError{
+			Kind:     KindValidationError,
+			Filename: name,
+			Err:      fmt.Errorf("%s contains %d configs; load it with LoadConfigMultiFS", name, len(configs)),
+		}
+	}
+	return configs[0], nil
+}
+
+// LoadConfigMultiFS reads the named file from fsys like LoadConfigMulti,
+// returning every config it holds in file order
+func LoadConfigMultiFS(fsys fs.FS, name string, opts LoadOptions) ([]*Config, error) {
+	return loadConfigMulti(fileSource{fsys: fsys}, name, opts)
+}
+
+// LoadConfigsFS loads each named file from fsys in order, like LoadConfigs
+func LoadConfigsFS(ctx context.Context, fsys fs.FS, names []string) ([]*Config, error) {
+	return loadConfigs(ctx, fileSource{fsys: fsys}, names)
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"bases/go.toml": {Data: []byte(`
+[metadata]
+title = "Go"
+
+[sections.testing]
+content_file = "snippets/testing.md"
+
+[sections.style]
+content = "Use gofmt."
+includes = ["snippets/lint.md"]
+`)},
+		"bases/snippets/testing.md": {Data: []byte("Run go test ./...\n")},
+		"bases/snippets/lint.md":    {Data: []byte("Run golangci-lint.")},
+		"bases/common.md":           {Data: []byte("---\ntitle: Common\n---\n# Common\n")},
+	}
+
+	cfg, err := LoadConfigFS(fsys, "bases/go.toml")
+	require.NoError(t, err)
+	assert.Equal(t, "Go", cfg.Metadata.Title)
+	assert.Equal(t, "Run go test ./...", cfg.Sections["testing"].Content)
+	assert.Equal(t, "Use gofmt.\nRun golangci-lint.", cfg.Sections["style"].Content)
+	assert.Equal(t, "bases/go.toml", cfg.SourceFile)
+	assert.Equal(t, FormatTOML, cfg.SourceFormat)
+
+	cfg, err = LoadConfigFS(fsys, "bases/common.md")
+	require.NoError(t, err)
+	assert.Equal(t, "Common", cfg.Metadata.Title)
+	assert.Equal(t, FormatMarkdown, cfg.SourceFormat)
+}
+
+func TestLoadConfigFS_Errors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"base.txt":   {Data: []byte("plain")},
+		"self.toml":  {Data: []byte("[sections.a]\nincludes = [\"self.toml\"]\n")},
+		"up.toml":    {Data: []byte("[sections.a]\ncontent_file = \"../outside.md\"\n")},
+		"multi.json": {Data: []byte(`[{"sections": {}}, {"sections": {}}]`)},
+	}
+
+	_, err := LoadConfigFS(fsys, "missing.toml")
+	var configErr *ConfigError
+	require.True(t, errors.As(err, &configErr))
+	assert.Equal(t, KindNotFound, configErr.Kind)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = LoadConfigFS(fsys, "base.txt")
+	require.True(t, errors.As(err, &configErr))
+	assert.Equal(t, KindUnsupportedFormat, configErr.Kind)
+
+	_, err = LoadConfigFS(fsys, "self.toml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "includes its own config file")
+
+	// fs.FS names can't leave the root
+	_, err = LoadConfigFS(fsys, "up.toml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read content file ../outside.md")
+
+	_, err = LoadConfigFS(fsys, "multi.json")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "load it with LoadConfigMultiFS")
+
+	configs, err := LoadConfigMultiFS(fsys, "multi.json", LoadOptions{})
+	require.NoError(t, err)
+	assert.Len(t, configs, 2)
+}
+
+func TestLoadConfigsFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.yaml": {Data: []byte("priority_tiers: {high: 50}\nsections: {intro: {content: A, priority: high}}\n")},
+		"b.yaml": {Data: []byte("sections: {intro: {content: B, priority: high}}\n")},
+	}
+
+	configs, err := LoadConfigsFS(context.Background(), fsys, []string{"a.yaml", "b.yaml"})
+	require.NoError(t, err)
+	require.Len(t, configs, 2)
+	assert.Equal(t, NewExplicitPriority(50), configs[1].Sections["intro"].Priority, "tiers resolve across files")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = LoadConfigsFS(ctx, fsys, []string{"a.yaml"})
+	assert.ErrorIs(t, err, context.Canceled)
+}
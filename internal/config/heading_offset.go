@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseHeadingOffsets parses comma-separated file=offset pairs, such as
+// "go.md=1,notes.md=-1", into offsets by file
+func ParseHeadingOffsets(spec string) (map[string]int, error) {
+	offsets := make(map[string]int)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		i := strings.LastIndex(pair, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("heading offset %q must be written as file=offset", pair)
+		}
+		file := strings.TrimSpace(pair[:i])
+		offset, err := strconv.Atoi(strings.TrimSpace(pair[i+1:]))
+		if file == "" || err != nil {
+			return nil, fmt.Errorf("heading offset %q must be written as file=offset, with a whole number offset", pair)
+		}
+		offsets[file] = offset
+	}
+	return offsets, nil
+}
+
+// SetHeadingOffsets sets the Metadata.HeadingOffset of every config loaded
+// from a file in offsets, replacing the one the file gives itself. It
+// returns the files that match no config, sorted.
+func SetHeadingOffsets(configs []*Config, offsets map[string]int) []string {
+	used := make(map[string]bool)
+	for _, config := range configs {
+		for file, offset := range offsets {
+			if samePath(file, config.SourceFile) {
+				config.Metadata.HeadingOffset = offset
+				used[file] = true
+			}
+		}
+	}
+
+	var unused []string
+	for file := range offsets {
+		if !used[file] {
+			unused = append(unused, file)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeadingOffset_Metadata(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		format FileFormat
+	}{
+		{name: "yaml frontmatter", data: "---\ntitle: Go\nheading_offset: 1\n---\n# Go", format: FormatMarkdown},
+		{name: "toml frontmatter", data: "+++\ntitle = \"Go\"\nheading_offset = 1\n+++\n# Go", format: FormatMarkdown},
+		{name: "toml", data: "[metadata]\ntitle = \"Go\"\nheading_offset = 1\n", format: FormatTOML},
+		{name: "yaml", data: "metadata:\n  title: Go\n  heading_offset: 1\n", format: FormatYAML},
+		{name: "json", data: `{"metadata": {"title": "Go", "heading_offset": 1}}`, format: FormatJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseConfig([]byte(tt.data), tt.format)
+			require.NoError(t, err)
+			assert.Equal(t, 1, config.Metadata.HeadingOffset)
+			assert.NotContains(t, config.Metadata.Extra, "heading_offset")
+		})
+	}
+}
+
+func TestParseHeadingOffsets(t *testing.T) {
+	offsets, err := ParseHeadingOffsets(" a.md=1, docs/b.md = -2,,c=d.md=3")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"a.md": 1, "docs/b.md": -2, "c=d.md": 3}, offsets)
+
+	offsets, err = ParseHeadingOffsets("")
+	require.NoError(t, err)
+	assert.Empty(t, offsets)
+
+	for _, spec := range []string{"a.md", "a.md=", "a.md=one", "=1"} {
+		_, err := ParseHeadingOffsets(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestSetHeadingOffsets(t *testing.T) {
+	configs := []*Config{
+		{SourceFile: "a.md", Metadata: Metadata{HeadingOffset: 2}},
+		{SourceFile: "docs/b.md"},
+		{SourceFile: "c.md", Metadata: Metadata{HeadingOffset: 1}},
+	}
+
+	unused := SetHeadingOffsets(configs, map[string]int{"a.md": -1, "./docs/b.md": 1, "missing.md": 1})
+
+	assert.Equal(t, []string{"missing.md"}, unused)
+	assert.Equal(t, -1, configs[0].Metadata.HeadingOffset)
+	assert.Equal(t, 1, configs[1].Metadata.HeadingOffset)
+	assert.Equal(t, 1, configs[2].Metadata.HeadingOffset, "a file without a flag keeps its own offset")
+}
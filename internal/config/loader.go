@@ -1,40 +1,326 @@
 package config
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"strings"
 )
 
+// LoadOptions adjusts how LoadConfigWithOptions reads a file
+type LoadOptions struct {
+	// YAMLRoot is a dot-separated key path; when set, YAML files are parsed
+	// from the subtree at that path instead of the whole document
+	YAMLRoot string
+
+	// TOMLRoot is a dot-separated table path; when set, TOML files are parsed
+	// from that table instead of the whole document
+	TOMLRoot string
+
+	// AllowBinary skips the check that rejects files that look binary, such
+	// as an image a glob picked up by mistake
+	AllowBinary bool
+
+	// SplitMarkdown splits the body of markdown files into a section per
+	// heading, list, and ordered list instead of one content section
+	SplitMarkdown bool
+
+	// SharedTiers leaves priorities naming a tier the file doesn't define
+	// for ResolvePriorityTiers to resolve with the tiers of every loaded
+	// file, instead of failing the load. LoadConfigs sets it.
+	SharedTiers bool
+}
+
 // LoadConfig reads a configuration file and returns a Config struct
-// Supports TOML, YAML, and Markdown formats
+// Supports TOML, YAML, JSON, and Markdown formats
 func LoadConfig(filename string) (*Config, error) {
+	return LoadConfigWithOptions(filename, LoadOptions{})
+}
+
+// LoadConfigWithOptions reads a configuration file like LoadConfig, applying
+// opts
+func LoadConfigWithOptions(filename string, opts LoadOptions) (*Config, error) {
+	configs, err := LoadConfigMulti(filename, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(configs) != 1 {
+		return nil, &ConfigError{
+			Kind:     KindValidationError,
+			Filename: filename,
+			Err:      fmt.Errorf("%s contains %d configs; load it with LoadConfigMulti", filename, len(configs)),
+		}
+	}
+	return configs[0], nil
+}
+
+// LoadConfigMulti reads a configuration file that may hold several configs,
+// such as a JSON array, and returns them in file order
+func LoadConfigMulti(filename string, opts LoadOptions) ([]*Config, error) {
+	return loadConfigMulti(fileSource{}, filename, opts)
+}
+
+// loadConfigMulti reads configs from filename in src, which also supplies
+// the content files and includes they reference
+func loadConfigMulti(src fileSource, filename string, opts LoadOptions) ([]*Config, error) {
 	// Step 1: Read the file
-	data, err := os.ReadFile(filename)
+	data, err := src.readFile(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+		return nil, &ConfigError{Kind: KindNotFound, Filename: filename, Err: err, action: "read file"}
 	}
+	data = StripBOM(data)
 
 	// Step 2: Detect format
-	format, err := DetectFormat(filename)
+	format, err := DetectFormat(src.formatName(filename))
 	if err != nil {
 		return nil, err
 	}
+	return decodeConfigs(src, filename, data, format, opts)
+}
 
-	// Step 3: Parse based on format
-	config, err := ParseConfig(data, format)
+// decodeConfigs parses the configs in data, read from filename in src, and
+// resolves what they reference
+func decodeConfigs(src fileSource, filename string, data []byte, format FileFormat, opts LoadOptions) ([]*Config, error) {
+	// Binary data would only turn into garbage sections
+	if !opts.AllowBinary {
+		err := checkText(data)
+		if err != nil {
+			return nil, &ConfigError{Kind: KindParseError, Filename: filename, Err: fmt.Errorf("file %s %w: %v", filename, ErrNotText, err)}
+		}
+	}
+
+	// Step 3: Parse based on format, starting from the selected subtree
+	var configs []*Config
+	var config *Config
+	var err error
+	switch {
+	case format == FormatYAML && opts.YAMLRoot != "":
+		config, err = parseYAMLSubtree(data, opts.YAMLRoot)
+		configs = []*Config{config}
+	case format == FormatTOML && opts.TOMLRoot != "":
+		config, err = parseTOMLSubtree(data, opts.TOMLRoot)
+		configs = []*Config{config}
+	default:
+		configs, err = ParseConfigMulti(data, format)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+		return nil, &ConfigError{Kind: KindParseError, Filename: filename, Err: err, action: "parse"}
+	}
+
+	for _, config := range configs {
+		if format == FormatMarkdown && opts.SplitMarkdown {
+			splitMarkdownBody(config)
+		}
+
+		// Step 4: Resolve content stored in external files and snippets
+		err = resolveContentFiles(config, src, src.dir(filename))
+		if err != nil {
+			return nil, resolveError(filename, "resolve content for", err)
+		}
+		err = resolveIncludes(config, src, filename)
+		if err != nil {
+			return nil, resolveError(filename, "resolve includes for", err)
+		}
+
+		// Step 5: Resolve tier names defined in this config; with shared
+		// tiers, those defined in other files are resolved by
+		// ResolvePriorityTiers
+		err = resolveTiers(config, config.PriorityTiers)
+		if err != nil && !opts.SharedTiers {
+			return nil, &ConfigError{Kind: KindValidationError, Filename: filename, Err: fmt.Errorf("%s: %w", filename, err)}
+		}
+
+		// Step 6: Resolve relative priorities against the config's own
+		// priority, unless that is a tier still waiting to be resolved
+		if config.Metadata.Priority.Tier == "" {
+			resolveRelativePriorities(config)
+		}
+
+		// Step 7: Set source metadata
+		config.SourceFile = filename
+		config.SourceFormat = format
 	}
 
-	// Step 4: Set source metadata
-	config.SourceFile = filename
-	config.SourceFormat = format
+	// Step 8: Number the sections in the order they were written
+	numberSections(configs, data, format, opts)
+
+	return configs, nil
+}
+
+// LoadConfigs loads each file in order, expanding files that hold several
+// configs, resolving priority tiers across all of them, and numbering their
+// sections with SequenceSections. It stops early
+// with ctx.Err() if the context is cancelled between files.
+func LoadConfigs(ctx context.Context, filenames []string) ([]*Config, error) {
+	return loadConfigs(ctx, fileSource{}, filenames)
+}
+
+// loadConfigs loads each file in order from src, as LoadConfigs describes
+func loadConfigs(ctx context.Context, src fileSource, filenames []string) ([]*Config, error) {
+	configs := make([]*Config, 0, len(filenames))
+	for _, filename := range filenames {
+		err := ctx.Err()
+		if err != nil {
+			return nil, err
+		}
+
+		loaded, err := loadConfigMulti(src, filename, LoadOptions{SharedTiers: true})
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, loaded...)
+	}
 
-	return config, nil
+	err := ResolvePriorityTiers(configs)
+	if err != nil {
+		return nil, err
+	}
+	SequenceSections(configs)
+	return configs, nil
 }
 
-// ValidateConfig checks if a config is valid
+// resolveRelativePriorities turns relative priorities on sections, merge
+// points, and merge targets into offsets from the file's metadata priority, so
+// relative(2) in a file with priority 10 becomes relative(12). Files without
+// a metadata priority keep their relative values as written.
+func resolveRelativePriorities(config *Config) {
+	base := config.Metadata.Priority
+	if base.Type == PriorityNone {
+		return
+	}
+
+	for name, section := range config.Sections {
+		section.Priority = resolveRelative(section.Priority, base)
+		config.Sections[name] = section
+	}
+	for name, point := range config.MergePoints {
+		point.Priority = resolveRelative(point.Priority, base)
+		config.MergePoints[name] = point
+	}
+	for name, target := range config.MergeTargets {
+		target.Priority = resolveRelative(target.Priority, base)
+		config.MergeTargets[name] = target
+	}
+}
+
+// resolveRelative offsets a relative priority by the base priority's value
+func resolveRelative(priority, base Priority) Priority {
+	if priority.Type != PriorityRelative || base.Type == PriorityNone {
+		return priority
+	}
+	return NewRelativePriority(base.Value + priority.Value)
+}
+
+// unresolveRelative undoes resolveRelative, returning a relative priority
+// resolved against base to the value written in the file
+func unresolveRelative(priority, base Priority) Priority {
+	if priority.Type != PriorityRelative || base.Type == PriorityNone {
+		return priority
+	}
+	return NewRelativePriority(priority.Value - base.Value)
+}
+
+// resolveContentFiles replaces content_file references on sections and merge
+// targets with the referenced file's contents in src, relative to baseDir
+func resolveContentFiles(config *Config, src fileSource, baseDir string) error {
+	for name, section := range config.Sections {
+		content, err := readContentFile(src, section.Content, section.ContentFile, baseDir)
+		if err != nil {
+			return fmt.Errorf("section %s: %w", name, err)
+		}
+		section.Content = content
+		config.Sections[name] = section
+	}
+
+	for name, target := range config.MergeTargets {
+		content, err := readContentFile(src, target.Content, target.ContentFile, baseDir)
+		if err != nil {
+			return fmt.Errorf("merge target %s: %w", name, err)
+		}
+		target.Content = content
+		config.MergeTargets[name] = target
+	}
+
+	return nil
+}
+
+// readContentFile returns the inline content, or the contents of contentFile
+// in src when one is referenced
+func readContentFile(src fileSource, content, contentFile, baseDir string) (string, error) {
+	if contentFile == "" {
+		return content, nil
+	}
+	if content != "" {
+		return "", fmt.Errorf("content and content_file cannot both be set")
+	}
+
+	data, err := src.readFile(src.join(baseDir, contentFile))
+	if err != nil {
+		return "", fmt.Errorf("failed to read content file %s: %w", contentFile, err)
+	}
+
+	return strings.TrimRight(string(StripBOM(data)), "\r\n"), nil
+}
+
+// resolveIncludes appends the snippets listed in each section's includes to
+// its content, combining them with the section's strategy (append if unset).
+// Paths are relative to the directory of filename, the config being loaded
+// from src, which may not include itself.
+func resolveIncludes(config *Config, src fileSource, filename string) error {
+	baseDir := src.dir(filename)
+
+	for name, section := range config.Sections {
+		if len(section.Includes) == 0 {
+			continue
+		}
+
+		strategy := section.Strategy
+		if strategy == "" {
+			strategy = "append"
+		}
+		if !isStrategy(strategy) {
+			return fmt.Errorf("section %s has invalid strategy '%s'", name, strategy)
+		}
+
+		content := section.Content
+		for _, include := range section.Includes {
+			path := src.join(baseDir, include)
+			if src.same(path, filename) {
+				return fmt.Errorf("section %s includes its own config file %s", name, include)
+			}
+
+			data, err := src.readFile(path)
+			if err != nil {
+				return fmt.Errorf("section %s: failed to read include %s: %w", name, include, err)
+			}
+			snippet := strings.TrimRight(string(StripBOM(data)), "\r\n")
+
+			// The first snippet of a section without content is its base
+			if content == "" {
+				content = snippet
+				continue
+			}
+			content = CombineContent(strategy, content, snippet, include)
+		}
+
+		section.Content = content
+		config.Sections[name] = section
+	}
+
+	return nil
+}
+
+// ValidateConfig checks if a config is valid. Problems are reported as a
+// ConfigError of kind KindValidationError for the config's source file.
 func ValidateConfig(config *Config) error {
+	err := validateConfig(config)
+	if err != nil {
+		return &ConfigError{Kind: KindValidationError, Filename: config.SourceFile, Err: err}
+	}
+	return nil
+}
+
+// validateConfig returns the first problem found in config
+func validateConfig(config *Config) error {
 	if config.Metadata.Title == "" {
 		return fmt.Errorf("config missing title in metadata")
 	}
@@ -50,5 +336,22 @@ func ValidateConfig(config *Config) error {
 		}
 	}
 
+	// Validate merge target strategies
+	for name, target := range config.MergeTargets {
+		if target.Strategy != "" && !isStrategy(target.Strategy) {
+			return fmt.Errorf("merge target %s has invalid strategy '%s'", name, target.Strategy)
+		}
+	}
+
 	return nil
+}
+
+// validStrategies mirrors the built-in strategy names of the merger package,
+// which cannot be imported here without creating an import cycle
+var validStrategies = map[string]bool{
+	"replace":  true,
+	"append":   true,
+	"prepend":  true,
+	"collapse": true,
+	"keyvalue": true,
 }
\ No newline at end of file
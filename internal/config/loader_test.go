@@ -1,7 +1,9 @@
 package config
 
 import (
+	"context"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -66,6 +68,41 @@ More content here.
 	assert.NotEmpty(t, config.Sections)
 }
 
+func TestLoadConfigWithOptions_SplitMarkdown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "release.md")
+	content := "---\ntitle: Release\n---\n" +
+		"# Setup\n" +
+		"1. Install Go\n" +
+		"2. Clone the repo\n" +
+		"\n" +
+		"# Release\n" +
+		"1. Tag the commit\n" +
+		"2. Push the tag\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"content"}, SortedSectionKeys(config.Sections), "markdown stays one section by default")
+
+	config, err = LoadConfigWithOptions(path, LoadOptions{SplitMarkdown: true})
+	require.NoError(t, err)
+	assert.Equal(t, "Release", config.Metadata.Title)
+	assert.Equal(t, []string{"header_1_setup", "ordered_list_2", "header_1_release", "ordered_list_4"}, SortedSectionKeys(config.Sections))
+	assert.Equal(t, "1. Install Go\n2. Clone the repo", config.Sections["ordered_list_2"].Content)
+	assert.Equal(t, "1. Tag the commit\n2. Push the tag", config.Sections["ordered_list_4"].Content)
+}
+
+func TestLoadConfigWithOptions_SplitMarkdownHeading(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.md")
+	require.NoError(t, os.WriteFile(path, []byte("## STRICT REQUIREMENTS!! 🚨\nNo force pushes.\n"), 0644))
+
+	config, err := LoadConfigWithOptions(path, LoadOptions{SplitMarkdown: true})
+	require.NoError(t, err)
+	section, ok := config.Sections["header_2_strict_requirements"]
+	require.True(t, ok, "sections: %v", SortedSectionKeys(config.Sections))
+	assert.Equal(t, "## STRICT REQUIREMENTS!! 🚨", section.Heading)
+}
+
 func TestLoadConfig_WithPriorities(t *testing.T) {
 	content := `
 [metadata]
@@ -91,6 +128,53 @@ priority = { type = "relative", value = 5 }
 	assert.Equal(t, PriorityRelative, config.Sections["low_priority"].Priority.Type)
 }
 
+func TestLoadConfig_ResolvesRelativePriorities(t *testing.T) {
+	content := `
+[metadata]
+title = "Relative Test"
+priority = { type = "explicit", value = 10 }
+
+[sections.relative]
+content = "Relative content"
+priority = { type = "relative", value = 2 }
+
+[sections.explicit]
+content = "Explicit content"
+priority = { type = "explicit", value = 3 }
+
+[sections.unset]
+content = "No priority"
+
+[merge_points.point]
+placeholder = "<!-- MERGE:point -->"
+priority = { type = "relative", value = -1 }
+
+[merge_targets.point]
+content = "Target"
+priority = { type = "relative", value = 5 }
+`
+	config, err := testLoadFromContent(t, content, "test.toml")
+	require.NoError(t, err)
+
+	assert.Equal(t, NewRelativePriority(12), config.Sections["relative"].Priority)
+	assert.Equal(t, NewExplicitPriority(3), config.Sections["explicit"].Priority)
+	assert.Equal(t, Priority{}, config.Sections["unset"].Priority)
+	assert.Equal(t, NewRelativePriority(9), config.MergePoints["point"].Priority)
+	assert.Equal(t, NewRelativePriority(15), config.MergeTargets["point"].Priority)
+}
+
+func TestLoadConfig_RelativePriorityWithoutMetadataPriority(t *testing.T) {
+	content := `
+[sections.relative]
+content = "Relative content"
+priority = { type = "relative", value = 2 }
+`
+	config, err := testLoadFromContent(t, content, "test.toml")
+	require.NoError(t, err)
+
+	assert.Equal(t, NewRelativePriority(2), config.Sections["relative"].Priority)
+}
+
 func TestLoadConfig_FileNotFound(t *testing.T) {
 	_, err := LoadConfig("nonexistent.toml")
 	assert.Error(t, err)
@@ -203,6 +287,28 @@ func TestValidateConfig(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid merge target strategy",
+			config: &Config{
+				Metadata: Metadata{Title: "Test"},
+				Sections: map[string]Section{"test": {Content: "content"}},
+				MergeTargets: map[string]MergeTarget{
+					"target": {Strategy: "append", Content: "more"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid merge target strategy",
+			config: &Config{
+				Metadata: Metadata{Title: "Test"},
+				Sections: map[string]Section{"test": {Content: "content"}},
+				MergeTargets: map[string]MergeTarget{
+					"target": {Strategy: "appned", Content: "more"},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -215,4 +321,240 @@ func TestValidateConfig(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestValidateConfig_InvalidStrategyMessage(t *testing.T) {
+	cfg := &Config{
+		Metadata: Metadata{Title: "Test"},
+		Sections: map[string]Section{"test": {Content: "content"}},
+		MergeTargets: map[string]MergeTarget{
+			"test-commands": {Strategy: "appned"},
+		},
+	}
+
+	err := ValidateConfig(cfg)
+	require.Error(t, err)
+	assert.Equal(t, "merge target test-commands has invalid strategy 'appned'", err.Error())
+}
+
+func TestLoadConfig_ContentFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "snippets"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "snippets", "testing.md"), []byte("## Testing\n\nRun `go test ./...`\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "snippets", "target.md"), []byte("- go vet ./...\n"), 0644))
+
+	content := `
+[metadata]
+title = "Content File Test"
+
+[sections.testing]
+order = 1
+content_file = "snippets/testing.md"
+
+[merge_targets.test-commands]
+strategy = "append"
+content_file = "snippets/target.md"
+`
+	path := filepath.Join(dir, "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "## Testing\n\nRun `go test ./...`", config.Sections["testing"].Content)
+	assert.Equal(t, "- go vet ./...", config.MergeTargets["test-commands"].Content)
+}
+
+func TestLoadConfig_ContentFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{
+			name: "content and content_file both set",
+			content: `
+[sections.testing]
+content = "inline"
+content_file = "snippet.md"
+`,
+			errMsg: "content and content_file cannot both be set",
+		},
+		{
+			name: "missing content file",
+			content: `
+[merge_targets.test-commands]
+content_file = "missing.md"
+`,
+			errMsg: "failed to read content file missing.md",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "snippet.md"), []byte("snippet"), 0644))
+			path := filepath.Join(dir, "config.toml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			_, err := LoadConfig(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestLoadConfig_Includes(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "parts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "parts", "a.md"), []byte("Part A\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "parts", "b.md"), []byte("Part B\n"), 0644))
+
+	content := `
+[sections.appended]
+includes = ["parts/a.md", "parts/b.md"]
+
+[sections.prepended]
+includes = ["parts/a.md", "parts/b.md"]
+strategy = "prepend"
+
+[sections.with_content]
+content = "Intro"
+includes = ["parts/a.md"]
+
+[sections.collapsed]
+includes = ["parts/a.md", "parts/b.md"]
+strategy = "collapse"
+`
+	path := filepath.Join(dir, "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	config, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Part A\nPart B", config.Sections["appended"].Content)
+	assert.Equal(t, "Part B\nPart A", config.Sections["prepended"].Content)
+	assert.Equal(t, "Intro\nPart A", config.Sections["with_content"].Content)
+	assert.Equal(t, "Part A\n<details>\n<summary>parts/b.md</summary>\n\nPart B\n\n</details>", config.Sections["collapsed"].Content)
+}
+
+func TestLoadConfig_IncludeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{
+			name:    "missing include",
+			content: "[sections.testing]\nincludes = [\"snippet.md\", \"missing.md\"]\n",
+			errMsg:  "section testing: failed to read include missing.md",
+		},
+		{
+			name:    "own config file",
+			content: "[sections.testing]\nincludes = [\"../dir/config.toml\"]\n",
+			errMsg:  "section testing includes its own config file ../dir/config.toml",
+		},
+		{
+			name:    "invalid strategy",
+			content: "[sections.testing]\nincludes = [\"snippet.md\"]\nstrategy = \"shuffle\"\n",
+			errMsg:  "section testing has invalid strategy 'shuffle'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "dir")
+			require.NoError(t, os.MkdirAll(dir, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "snippet.md"), []byte("snippet"), 0644))
+			path := filepath.Join(dir, "config.toml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			_, err := LoadConfig(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestLoadConfigs(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.toml")
+	second := filepath.Join(dir, "second.yaml")
+	require.NoError(t, os.WriteFile(first, []byte("[metadata]\ntitle = \"First\"\n"), 0644))
+	require.NoError(t, os.WriteFile(second, []byte("metadata:\n  title: Second\n"), 0644))
+
+	configs, err := LoadConfigs(context.Background(), []string{first, second})
+	require.NoError(t, err)
+	require.Len(t, configs, 2)
+	assert.Equal(t, "First", configs[0].Metadata.Title)
+	assert.Equal(t, "Second", configs[1].Metadata.Title)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = LoadConfigs(ctx, []string{first, second})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestLoadConfig_JSON(t *testing.T) {
+	content := `{
+  "metadata": {"title": "Test Config", "priority": {"type": "explicit", "value": 5}},
+  "sections": {"test": {"order": 1, "content": "Test content"}}
+}`
+	config, err := testLoadFromContent(t, content, "test.json")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Test Config", config.Metadata.Title)
+	assert.Equal(t, NewExplicitPriority(5), config.Metadata.Priority)
+	assert.Equal(t, "Test content", config.Sections["test"].Content)
+	assert.Equal(t, FormatJSON, config.SourceFormat)
+}
+
+func TestLoadConfigMulti_JSONArray(t *testing.T) {
+	content := `[
+  {"metadata": {"title": "First"}, "sections": {"intro": {"content": "One"}}},
+  {"metadata": {"title": "Second", "priority": {"type": "explicit", "value": 10}},
+   "sections": {"intro": {"content": "Two", "priority": {"type": "relative", "value": 1}}}}
+]`
+	filename := filepath.Join(t.TempDir(), "configs.json")
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0644))
+
+	configs, err := LoadConfigMulti(filename, LoadOptions{})
+	require.NoError(t, err)
+	require.Len(t, configs, 2)
+	assert.Equal(t, "First", configs[0].Metadata.Title)
+	assert.Equal(t, "Second", configs[1].Metadata.Title)
+	assert.Equal(t, NewRelativePriority(11), configs[1].Sections["intro"].Priority, "relative priorities resolve against their own element")
+	for _, config := range configs {
+		assert.Equal(t, filename, config.SourceFile)
+		assert.Equal(t, FormatJSON, config.SourceFormat)
+		assert.NotNil(t, config.MergeTargets)
+	}
+
+	_, err = LoadConfigWithOptions(filename, LoadOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "contains 2 configs")
+
+	all, err := LoadConfigs(context.Background(), []string{filename, filename})
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+}
+
+func TestParseConfigMulti(t *testing.T) {
+	configs, err := ParseConfigMulti([]byte("  \n[]"), FormatJSON)
+	require.NoError(t, err)
+	assert.Empty(t, configs)
+
+	configs, err = ParseConfigMulti([]byte(`{"metadata": {"title": "Single"}}`), FormatJSON)
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+	assert.Equal(t, "Single", configs[0].Metadata.Title)
+
+	configs, err = ParseConfigMulti([]byte("[metadata]\ntitle = \"TOML\""), FormatTOML)
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+	assert.Equal(t, "TOML", configs[0].Metadata.Title)
+
+	_, err = ParseConfigMulti([]byte(`[{"metadata": 1}]`), FormatJSON)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "config 0")
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+// HeadingRegex matches an ATX heading line, capturing its marker and its
+// text without any closing sequence
+var HeadingRegex = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+
+// ParseFence reports whether line opens a fenced code block, returning its
+// indentation, fence marker, and info-string language
+func ParseFence(line string) (indent, fence, lang string, ok bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	indent = line[:len(line)-len(trimmed)]
+	if len(indent) > 3 {
+		return "", "", "", false
+	}
+
+	for _, ch := range []string{"`", "~"} {
+		n := len(trimmed) - len(strings.TrimLeft(trimmed, ch))
+		if n >= 3 {
+			fence = trimmed[:n]
+			info := strings.Fields(trimmed[n:])
+			if len(info) > 0 {
+				lang = strings.ToLower(info[0])
+			}
+			return indent, fence, lang, true
+		}
+	}
+	return "", "", "", false
+}
+
+// IsClosingFence reports whether line closes a block opened with fence
+func IsClosingFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == ""
+}
+
+// ShiftHeadings moves every ATX heading in markdown offset levels deeper, or
+// shallower when offset is negative, keeping each between levels 1 and 6.
+// Headings in fenced code blocks are left alone.
+func ShiftHeadings(markdown string, offset int) string {
+	if offset == 0 {
+		return markdown
+	}
+
+	lines := strings.Split(markdown, "\n")
+	fence := ""
+	for i, line := range lines {
+		if fence != "" {
+			if IsClosingFence(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if _, f, _, ok := ParseFence(line); ok {
+			fence = f
+			continue
+		}
+		match := HeadingRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		level := len(match[1])
+		shifted := min(max(level+offset, 1), 6)
+		lines[i] = line[:indent] + strings.Repeat("#", shifted) + line[indent+level:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeadingRegex(t *testing.T) {
+	tests := []struct {
+		line   string
+		marker string
+		text   string
+		ok     bool
+	}{
+		{"# Title", "#", "Title", true},
+		{"   ### Deep ###", "###", "Deep", true},
+		{"##", "##", "", true},
+		{"#Title", "", "", false},
+		{"    # Indented code", "", "", false},
+		{"####### Seven", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			match := HeadingRegex.FindStringSubmatch(tt.line)
+			if !tt.ok {
+				assert.Nil(t, match)
+				return
+			}
+			if assert.NotNil(t, match) {
+				assert.Equal(t, tt.marker, match[1])
+				assert.Equal(t, tt.text, match[2])
+			}
+		})
+	}
+}
+
+func TestParseFence(t *testing.T) {
+	tests := []struct {
+		line   string
+		indent string
+		fence  string
+		lang   string
+		ok     bool
+	}{
+		{"```go", "", "```", "go", true},
+		{"  ~~~~ Python extra", "  ", "~~~~", "python", true},
+		{"```", "", "```", "", true},
+		{"``", "", "", "", false},
+		{"    ```go", "", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			indent, fence, lang, ok := ParseFence(tt.line)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.indent, indent)
+			assert.Equal(t, tt.fence, fence)
+			assert.Equal(t, tt.lang, lang)
+		})
+	}
+}
+
+func TestIsClosingFence(t *testing.T) {
+	assert.True(t, IsClosingFence("```", "```"))
+	assert.True(t, IsClosingFence("  `````  ", "```"))
+	assert.False(t, IsClosingFence("``", "```"))
+	assert.False(t, IsClosingFence("```go", "```"))
+	assert.False(t, IsClosingFence("~~~", "```"))
+}
+
+func TestShiftHeadings(t *testing.T) {
+	input := "# Title\nBody with # sign\n  ## Setup ##\n###### Deep\n```md\n# not a heading\n```\n#hashtag"
+
+	tests := []struct {
+		name   string
+		offset int
+		want   string
+	}{
+		{name: "zero", offset: 0, want: input},
+		{
+			name:   "deeper",
+			offset: 1,
+			want:   "## Title\nBody with # sign\n  ### Setup ##\n###### Deep\n```md\n# not a heading\n```\n#hashtag",
+		},
+		{
+			name:   "shallower clamps at level 1",
+			offset: -2,
+			want:   "# Title\nBody with # sign\n  # Setup ##\n#### Deep\n```md\n# not a heading\n```\n#hashtag",
+		},
+		{
+			name:   "deeper clamps at level 6",
+			offset: 9,
+			want:   "###### Title\nBody with # sign\n  ###### Setup ##\n###### Deep\n```md\n# not a heading\n```\n#hashtag",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ShiftHeadings(input, tt.offset))
+		})
+	}
+}
+
+func TestShiftHeadings_Fences(t *testing.T) {
+	assert.Equal(t, "~~~\n# comment\n~~~\n## After", ShiftHeadings("~~~\n# comment\n~~~\n# After", 1))
+	assert.Equal(t, "````\n```\n# still code\n````\n##", ShiftHeadings("````\n```\n# still code\n````\n#", 1))
+}
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Overrides sets priorities and orders from a sidecar file, so configs that
+// cannot be edited, such as vendored ones, can still be ranked. Files are
+// keyed by path; sections are keyed by "path#section".
+//
+// Overrides take precedence over the values in the files themselves:
+//  1. A section override replaces that section's priority and order.
+//  2. A file override's priority replaces the file's metadata priority and
+//     is given to every section, merge point, and merge target in the file
+//     that sets no priority of its own. Its order is given to every section
+//     in the file without a section override.
+//  3. Everything else keeps the values written in the file.
+type Overrides struct {
+	Files    map[string]Override `toml:"files" yaml:"files" json:"files"`
+	Sections map[string]Override `toml:"sections" yaml:"sections" json:"sections"`
+}
+
+// Override holds the values set for one file or section; nil fields are
+// left alone
+type Override struct {
+	Priority *Priority `toml:"priority" yaml:"priority" json:"priority"`
+	Order    *int      `toml:"order" yaml:"order" json:"order"`
+}
+
+// LoadOverrides reads an overrides file in TOML, YAML, or JSON format.
+// Errors are reported as a ConfigError.
+func LoadOverrides(filename string) (*Overrides, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, &ConfigError{Kind: KindNotFound, Filename: filename, Err: err, action: "read file"}
+	}
+
+	data = StripBOM(data)
+
+	format, err := DetectFormat(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var overrides Overrides
+	switch format {
+	case FormatTOML:
+		_, err = toml.Decode(string(data), &overrides)
+	case FormatYAML:
+		err = yaml.Unmarshal(data, &overrides)
+	case FormatJSON:
+		err = json.Unmarshal(data, &overrides)
+	default:
+		return nil, &ConfigError{
+			Kind:     KindUnsupportedFormat,
+			Filename: filename,
+			Err:      fmt.Errorf("overrides file %s must be TOML, YAML, or JSON", filename),
+		}
+	}
+	if err != nil {
+		return nil, &ConfigError{Kind: KindParseError, Filename: filename, Err: err, action: "parse"}
+	}
+
+	for key := range overrides.Sections {
+		if !strings.Contains(key, "#") {
+			return nil, &ConfigError{
+				Kind:     KindValidationError,
+				Filename: filename,
+				Err:      fmt.Errorf("%s: section override %q must be written as file#section", filename, key),
+			}
+		}
+	}
+	return &overrides, nil
+}
+
+// Apply sets the overridden priorities and orders on configs, matching files
+// by path. Tier names are resolved against the configs' priority_tiers
+// tables, so Apply should run after ResolvePriorityTiers. It returns the keys
+// of overrides that matched no file or section.
+func (o *Overrides) Apply(configs []*Config) ([]string, error) {
+	tiers, err := MergePriorityTiers(configs)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := resolveOverrideTiers(o.Files, tiers)
+	if err != nil {
+		return nil, err
+	}
+	sections, err := resolveOverrideTiers(o.Sections, tiers)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]bool)
+	for _, config := range configs {
+		for key, override := range files {
+			if samePath(key, config.SourceFile) {
+				applyFileOverride(config, override)
+				used[key] = true
+			}
+		}
+
+		for key, override := range sections {
+			i := strings.LastIndex(key, "#")
+			file, name := key[:i], key[i+1:]
+			section, exists := config.Sections[name]
+			if !exists || !samePath(file, config.SourceFile) {
+				continue
+			}
+			if override.Priority != nil {
+				section.Priority = *override.Priority
+			}
+			if override.Order != nil {
+				section.Order = *override.Order
+			}
+			config.Sections[name] = section
+			used[key] = true
+		}
+	}
+
+	var unused []string
+	for key := range files {
+		if !used[key] {
+			unused = append(unused, key)
+		}
+	}
+	for key := range sections {
+		if !used[key] {
+			unused = append(unused, key)
+		}
+	}
+	sort.Strings(unused)
+	return unused, nil
+}
+
+// applyFileOverride applies a file-level override to one config. Relative
+// priorities in the file were offset by its metadata priority when it was
+// loaded, so they are moved onto the overriding priority instead.
+func applyFileOverride(config *Config, override Override) {
+	if priority := override.Priority; priority != nil {
+		rebase := func(current Priority) Priority {
+			switch current.Type {
+			case PriorityNone:
+				return *priority
+			case PriorityRelative:
+				return resolveRelative(unresolveRelative(current, config.Metadata.Priority), *priority)
+			default:
+				return current
+			}
+		}
+		for name, section := range config.Sections {
+			section.Priority = rebase(section.Priority)
+			config.Sections[name] = section
+		}
+		for name, point := range config.MergePoints {
+			point.Priority = rebase(point.Priority)
+			config.MergePoints[name] = point
+		}
+		for name, target := range config.MergeTargets {
+			target.Priority = rebase(target.Priority)
+			config.MergeTargets[name] = target
+		}
+		config.Metadata.Priority = *priority
+	}
+
+	if override.Order != nil {
+		for name, section := range config.Sections {
+			section.Order = *override.Order
+			config.Sections[name] = section
+		}
+	}
+}
+
+// resolveOverrideTiers returns a copy of overrides with tier-named
+// priorities replaced by their values
+func resolveOverrideTiers(overrides map[string]Override, tiers map[string]int) (map[string]Override, error) {
+	resolved := make(map[string]Override, len(overrides))
+	for key, override := range overrides {
+		if override.Priority != nil && override.Priority.Tier != "" {
+			value, ok := tiers[override.Priority.Tier]
+			if !ok {
+				return nil, fmt.Errorf("override %s has unknown priority tier %s", key, override.Priority.Tier)
+			}
+			priority := NewExplicitPriority(value)
+			override.Priority = &priority
+		}
+		resolved[key] = override
+	}
+	return resolved, nil
+}
+
+// samePath reports whether two paths name the same file
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return absA == absB
+}
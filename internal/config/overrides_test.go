@@ -0,0 +1,227 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePriorityName(t *testing.T) {
+	tests := []struct {
+		name string
+		want Priority
+	}{
+		{name: "none", want: Priority{}},
+		{name: "None", want: Priority{}},
+		{name: "12", want: NewExplicitPriority(12)},
+		{name: "explicit:1", want: NewExplicitPriority(1)},
+		{name: "relative: 4", want: NewRelativePriority(4)},
+		{name: "project", want: Priority{Type: PriorityExplicit, Tier: "project"}},
+		{name: "explicit:high", want: Priority{Type: PriorityExplicit, Tier: "explicit:high"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parsePriorityName(tt.name))
+		})
+	}
+}
+
+func TestLoadOverrides(t *testing.T) {
+	tests := []struct {
+		filename string
+		content  string
+	}{
+		{
+			filename: "overrides.yaml",
+			content: `
+files:
+  vendor/base.md: {priority: "explicit:1", order: 3}
+sections:
+  "vendor/base.md#setup": {priority: none}
+`,
+		},
+		{
+			filename: "overrides.toml",
+			content: `
+[files."vendor/base.md"]
+priority = "explicit:1"
+order = 3
+
+[sections."vendor/base.md#setup"]
+priority = "none"
+`,
+		},
+		{
+			filename: "overrides.json",
+			content: `{
+  "files": {"vendor/base.md": {"priority": "explicit:1", "order": 3}},
+  "sections": {"vendor/base.md#setup": {"priority": "none"}}
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.filename)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			overrides, err := LoadOverrides(path)
+			require.NoError(t, err)
+
+			file := overrides.Files["vendor/base.md"]
+			require.NotNil(t, file.Priority)
+			require.NotNil(t, file.Order)
+			assert.Equal(t, NewExplicitPriority(1), *file.Priority)
+			assert.Equal(t, 3, *file.Order)
+
+			section := overrides.Sections["vendor/base.md#setup"]
+			require.NotNil(t, section.Priority)
+			assert.Equal(t, Priority{}, *section.Priority)
+			assert.Nil(t, section.Order)
+		})
+	}
+}
+
+func TestLoadOverrides_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	badKey := filepath.Join(dir, "bad.yaml")
+	require.NoError(t, os.WriteFile(badKey, []byte("sections:\n  setup: {order: 1}\n"), 0644))
+	_, err := LoadOverrides(badKey)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `section override "setup" must be written as file#section`)
+
+	markdown := filepath.Join(dir, "overrides.md")
+	require.NoError(t, os.WriteFile(markdown, []byte("# Overrides\n"), 0644))
+	_, err = LoadOverrides(markdown)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be TOML, YAML, or JSON")
+}
+
+func TestOverrides_Apply(t *testing.T) {
+	explicit := func(value int) *Priority {
+		priority := NewExplicitPriority(value)
+		return &priority
+	}
+	order := func(value int) *int { return &value }
+
+	newConfigs := func() []*Config {
+		return []*Config{
+			{
+				SourceFile: "vendor/base.md",
+				Sections: map[string]Section{
+					"content": {Order: 1, Content: "Base"},
+					"setup":   {Order: 2, Content: "Setup", Priority: NewRelativePriority(5)},
+				},
+				MergeTargets: map[string]MergeTarget{
+					"target": {Strategy: "replace", Content: "x"},
+				},
+			},
+			{
+				SourceFile:    "./project.toml",
+				PriorityTiers: map[string]int{"team": 40},
+				Sections: map[string]Section{
+					"content": {Order: 1, Content: "Project"},
+				},
+			},
+		}
+	}
+
+	t.Run("file and section overrides", func(t *testing.T) {
+		configs := newConfigs()
+		overrides := &Overrides{
+			Files: map[string]Override{
+				"vendor/base.md": {Priority: explicit(1), Order: order(9)},
+				"project.toml":   {Priority: &Priority{Type: PriorityExplicit, Tier: "team"}},
+			},
+			Sections: map[string]Override{
+				"vendor/base.md#setup": {Priority: &Priority{}, Order: order(4)},
+			},
+		}
+
+		unused, err := overrides.Apply(configs)
+		require.NoError(t, err)
+		assert.Empty(t, unused)
+
+		base := configs[0]
+		assert.Equal(t, NewExplicitPriority(1), base.Metadata.Priority)
+		assert.Equal(t, Section{Order: 9, Content: "Base", Priority: NewExplicitPriority(1)}, base.Sections["content"])
+		assert.Equal(t, Section{Order: 4, Content: "Setup"}, base.Sections["setup"])
+		assert.Equal(t, NewExplicitPriority(1), base.MergeTargets["target"].Priority)
+
+		project := configs[1]
+		assert.Equal(t, NewExplicitPriority(40), project.Metadata.Priority)
+		assert.Equal(t, NewExplicitPriority(40), project.Sections["content"].Priority)
+	})
+
+	t.Run("file priority keeps priorities set in the file", func(t *testing.T) {
+		configs := newConfigs()
+		configs[0].Sections["pinned"] = Section{Content: "Pinned", Priority: NewExplicitPriority(7)}
+		overrides := &Overrides{
+			Files: map[string]Override{"vendor/base.md": {Priority: explicit(1)}},
+		}
+
+		_, err := overrides.Apply(configs)
+		require.NoError(t, err)
+		assert.Equal(t, NewExplicitPriority(7), configs[0].Sections["pinned"].Priority)
+		assert.Equal(t, NewRelativePriority(6), configs[0].Sections["setup"].Priority, "relative priorities follow the new file priority")
+	})
+
+	t.Run("unused entries", func(t *testing.T) {
+		overrides := &Overrides{
+			Files: map[string]Override{"missing.md": {Order: order(1)}},
+			Sections: map[string]Override{
+				"vendor/base.md#missing": {Order: order(1)},
+				"project.toml#setup":     {Order: order(1)},
+			},
+		}
+
+		unused, err := overrides.Apply(newConfigs())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"missing.md", "project.toml#setup", "vendor/base.md#missing"}, unused)
+	})
+
+	t.Run("unknown tier", func(t *testing.T) {
+		overrides := &Overrides{
+			Files: map[string]Override{"project.toml": {Priority: &Priority{Type: PriorityExplicit, Tier: "vendor"}}},
+		}
+
+		_, err := overrides.Apply(newConfigs())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "override project.toml has unknown priority tier vendor")
+	})
+}
+
+func TestOverrides_ApplyRebasesRelativePriorities(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "vendor.toml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+[metadata]
+priority = { type = "explicit", value = 10 }
+
+[sections.testing]
+priority = { type = "relative", value = 2 }
+content = "Vendor testing"
+
+[sections.style]
+content = "Vendor style"
+`), 0644))
+
+	configs, err := LoadConfigs(context.Background(), []string{path})
+	require.NoError(t, err)
+	require.Equal(t, NewRelativePriority(12), configs[0].Sections["testing"].Priority)
+
+	priority := NewExplicitPriority(1)
+	overrides := &Overrides{Files: map[string]Override{path: {Priority: &priority}}}
+	_, err = overrides.Apply(configs)
+	require.NoError(t, err)
+
+	assert.Equal(t, NewExplicitPriority(1), configs[0].Metadata.Priority)
+	assert.Equal(t, NewRelativePriority(3), configs[0].Sections["testing"].Priority, "relative(2) is offset from the overriding priority, not the file's own")
+	assert.Equal(t, NewExplicitPriority(1), configs[0].Sections["style"].Priority)
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// Schema is a JSON Schema that configs are checked against, on top of the
+// built-in checks of ValidateConfig
+type Schema struct {
+	schema *jsonschema.Schema
+}
+
+// LoadSchema reads and compiles the JSON Schema in filename. References to
+// other schema files are resolved relative to it.
+func LoadSchema(filename string) (*Schema, error) {
+	schema, err := jsonschema.Compile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %w", filename, err)
+	}
+	return &Schema{schema: schema}, nil
+}
+
+// Violations checks the JSON representation of config, the form written by
+// -print-config, against the schema. Every violation is returned, each as
+// the JSON pointer of the offending value and a message, sorted; the result
+// is nil when config conforms.
+func (s *Schema) Violations(config *Config) ([]string, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var instance interface{}
+	err = decoder.Decode(&instance)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.schema.Validate(instance)
+	var validationErr *jsonschema.ValidationError
+	if errors.As(err, &validationErr) {
+		messages := violationMessages(validationErr, nil)
+		sort.Strings(messages)
+		return messages, nil
+	}
+	return nil, err
+}
+
+// violationMessages appends a message for each leaf of a validation error
+// tree; inner nodes only summarize their causes
+func violationMessages(err *jsonschema.ValidationError, messages []string) []string {
+	if len(err.Causes) == 0 {
+		pointer := err.InstanceLocation
+		if pointer == "" {
+			pointer = "/"
+		}
+		return append(messages, pointer+": "+err.Message)
+	}
+	for _, cause := range err.Causes {
+		messages = violationMessages(cause, messages)
+	}
+	return messages
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `{
+  "type": "object",
+  "required": ["metadata"],
+  "properties": {
+    "metadata": {
+      "type": "object",
+      "required": ["title"],
+      "properties": {"title": {"type": "string", "minLength": 1}}
+    },
+    "sections": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "object",
+        "properties": {
+          "content": {"type": "string", "minLength": 1},
+          "order": {"type": "integer", "minimum": 1}
+        }
+      }
+    }
+  }
+}`
+
+func writeSchema(t *testing.T, content string) string {
+	filename := filepath.Join(t.TempDir(), "schema.json")
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0644))
+	return filename
+}
+
+func TestSchema_Violations(t *testing.T) {
+	schema, err := LoadSchema(writeSchema(t, testSchema))
+	require.NoError(t, err)
+
+	valid := &Config{
+		Metadata: Metadata{Title: "Go"},
+		Sections: Sections{"intro": {Order: 1, Content: "Hello"}},
+	}
+	violations, err := schema.Violations(valid)
+	require.NoError(t, err)
+	assert.Nil(t, violations)
+
+	invalid := &Config{
+		Sections: Sections{
+			"empty": {Order: 1},
+			"early": {Content: "Hi"},
+		},
+	}
+	violations, err = schema.Violations(invalid)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"/metadata/title: length must be >= 1, but got 0",
+		"/sections/early/order: must be >= 1 but found 0",
+		"/sections/empty/content: length must be >= 1, but got 0",
+	}, violations)
+}
+
+func TestSchema_RootViolation(t *testing.T) {
+	schema, err := LoadSchema(writeSchema(t, `{"type": "array"}`))
+	require.NoError(t, err)
+
+	violations, err := schema.Violations(&Config{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/: expected array, but got object"}, violations)
+}
+
+func TestLoadSchema_Errors(t *testing.T) {
+	_, err := LoadSchema(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+
+	_, err = LoadSchema(writeSchema(t, `{"type": 5}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid schema")
+}
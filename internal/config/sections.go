@@ -0,0 +1,127 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Sections maps section keys to sections. Config files may write them as a
+// map keyed by section key or as a list whose items carry their key, see
+// sectionListItem.
+type Sections map[string]Section
+
+// sectionListItem is one section in the list form of Sections
+type sectionListItem struct {
+	Key     string `toml:"key" yaml:"key" json:"key"`
+	Section `yaml:",inline"`
+}
+
+// fromList converts the list form into sections. An item without an
+// explicit order is ordered by its 1-based position in the list.
+func fromList(items []sectionListItem) (Sections, error) {
+	sections := make(Sections, len(items))
+	for i, item := range items {
+		if item.Key == "" {
+			return nil, fmt.Errorf("section %d in list has no key", i+1)
+		}
+		if _, exists := sections[item.Key]; exists {
+			return nil, fmt.Errorf("section %s is listed more than once", item.Key)
+		}
+		if item.Order == 0 {
+			item.Order = i + 1
+		}
+		sections[item.Key] = item.Section
+	}
+	return sections, nil
+}
+
+// UnmarshalTOML implements the toml.Unmarshaler interface, accepting a table
+// of sections or an array of tables
+func (s *Sections) UnmarshalTOML(data interface{}) error {
+	// The decoded value is re-encoded so each section is decoded with the
+	// usual field rules, priorities included
+	var buf bytes.Buffer
+	err := toml.NewEncoder(&buf).Encode(map[string]interface{}{"sections": data})
+	if err != nil {
+		return err
+	}
+
+	switch data.(type) {
+	case []map[string]interface{}, []interface{}:
+		var doc struct {
+			Sections []sectionListItem `toml:"sections"`
+		}
+		_, err = toml.Decode(buf.String(), &doc)
+		if err != nil {
+			return err
+		}
+		*s, err = fromList(doc.Sections)
+		return err
+	}
+
+	var doc struct {
+		Sections map[string]Section `toml:"sections"`
+	}
+	_, err = toml.Decode(buf.String(), &doc)
+	if err != nil {
+		return err
+	}
+	*s = doc.Sections
+	return nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface, accepting a
+// mapping of sections or a sequence
+func (s *Sections) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.SequenceNode {
+		var items []sectionListItem
+		err := node.Decode(&items)
+		if err != nil {
+			return err
+		}
+		sections, err := fromList(items)
+		if err != nil {
+			return err
+		}
+		*s = sections
+		return nil
+	}
+
+	var sections map[string]Section
+	err := node.Decode(&sections)
+	if err != nil {
+		return err
+	}
+	*s = sections
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, accepting an
+// object of sections or an array
+func (s *Sections) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var items []sectionListItem
+		err := json.Unmarshal(data, &items)
+		if err != nil {
+			return err
+		}
+		sections, err := fromList(items)
+		if err != nil {
+			return err
+		}
+		*s = sections
+		return nil
+	}
+
+	var sections map[string]Section
+	err := json.Unmarshal(data, &sections)
+	if err != nil {
+		return err
+	}
+	*s = sections
+	return nil
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSections_ListForm(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+	}{
+		{
+			name:     "toml",
+			filename: "list.toml",
+			content: `
+[[sections]]
+key = "intro"
+content = "Intro"
+
+[[sections]]
+key = "rules"
+order = 10
+content = "Rules"
+priority = { type = "explicit", value = 7 }
+
+[[sections]]
+key = "outro"
+content = "Outro"
+`,
+		},
+		{
+			name:     "yaml",
+			filename: "list.yaml",
+			content: `
+sections:
+  - key: intro
+    content: Intro
+  - key: rules
+    order: 10
+    content: Rules
+    priority: {type: explicit, value: 7}
+  - key: outro
+    content: Outro
+`,
+		},
+		{
+			name:     "json",
+			filename: "list.json",
+			content: `{"sections": [
+  {"key": "intro", "content": "Intro"},
+  {"key": "rules", "order": 10, "content": "Rules", "priority": {"type": "explicit", "value": 7}},
+  {"key": "outro", "content": "Outro"}
+]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := testLoadFromContent(t, tt.content, tt.filename)
+			require.NoError(t, err)
+
+			require.Len(t, cfg.Sections, 3)
+			assert.Equal(t, 1, cfg.Sections["intro"].Order)
+			assert.Equal(t, "Intro", cfg.Sections["intro"].Content)
+			assert.Equal(t, 10, cfg.Sections["rules"].Order)
+			assert.Equal(t, NewExplicitPriority(7), cfg.Sections["rules"].Priority)
+			assert.Equal(t, 3, cfg.Sections["outro"].Order)
+		})
+	}
+}
+
+func TestSections_MapFormStillWorks(t *testing.T) {
+	content := `
+[sections.intro]
+order = 2
+content = "Intro"
+`
+	cfg, err := testLoadFromContent(t, content, "map.toml")
+	require.NoError(t, err)
+
+	assert.Equal(t, Sections{"intro": {Order: 2, Content: "Intro"}}, withoutSeq(cfg.Sections))
+}
+
+func TestSections_ListErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		errMsg   string
+	}{
+		{
+			name:     "missing key",
+			filename: "nokey.yaml",
+			content:  "sections:\n  - content: Intro\n",
+			errMsg:   "section 1 in list has no key",
+		},
+		{
+			name:     "duplicate key",
+			filename: "dup.json",
+			content:  `{"sections": [{"key": "intro"}, {"key": "intro"}]}`,
+			errMsg:   "section intro is listed more than once",
+		},
+		{
+			name:     "duplicate key toml",
+			filename: "dup.toml",
+			content:  "[[sections]]\nkey = \"a\"\n[[sections]]\nkey = \"a\"\n",
+			errMsg:   "section a is listed more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := testLoadFromContent(t, tt.content, tt.filename)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
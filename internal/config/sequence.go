@@ -0,0 +1,277 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// SortedSectionKeys returns the keys of sections in output order: by Order,
+// then by Seq, the order the sections were read in, then by key
+func SortedSectionKeys(sections map[string]Section) []string {
+	keys := make([]string, 0, len(sections))
+	for key := range sections {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := sections[keys[i]], sections[keys[j]]
+		if a.Order != b.Order {
+			return a.Order < b.Order
+		}
+		if a.Seq != b.Seq {
+			return a.Seq < b.Seq
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// SequenceSections numbers the sections of configs in one sequence, by
+// config in order and then by the Seq each was loaded with, so the sections
+// of a later config follow those of an earlier one. LoadConfigs calls it;
+// callers loading files one at a time call it once all are loaded.
+func SequenceSections(configs []*Config) {
+	var seq int64
+	for _, cfg := range configs {
+		keys := make([]string, 0, len(cfg.Sections))
+		for key := range cfg.Sections {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			a, b := cfg.Sections[keys[i]].Seq, cfg.Sections[keys[j]].Seq
+			if a != b {
+				return a < b
+			}
+			return keys[i] < keys[j]
+		})
+		for _, key := range keys {
+			seq++
+			section := cfg.Sections[key]
+			section.Seq = seq
+			cfg.Sections[key] = section
+		}
+	}
+}
+
+// numberSections gives the sections of configs, parsed from data, Seq
+// numbers from 1 in the order the sections appear in data. Sections whose
+// place can't be told, such as those of a markdown body, follow in Order
+// and key order, which for markdown is the order they were written in.
+func numberSections(configs []*Config, data []byte, format FileFormat, opts LoadOptions) {
+	orders := sectionKeyOrders(data, format, opts)
+	var seq int64
+	for i, cfg := range configs {
+		numbered := make(map[string]bool, len(cfg.Sections))
+		number := func(key string) {
+			section, ok := cfg.Sections[key]
+			if !ok || numbered[key] {
+				return
+			}
+			seq++
+			section.Seq = seq
+			cfg.Sections[key] = section
+			numbered[key] = true
+		}
+
+		if i < len(orders) {
+			for _, key := range orders[i] {
+				number(key)
+			}
+		}
+		rest := make(map[string]Section)
+		for key, section := range cfg.Sections {
+			if !numbered[key] {
+				rest[key] = Section{Order: section.Order}
+			}
+		}
+		for _, key := range SortedSectionKeys(rest) {
+			number(key)
+		}
+	}
+}
+
+// sectionKeyOrders returns, for each config in data, its section keys in
+// the order they are written. The result is best effort: where the order
+// can't be read it is nil.
+func sectionKeyOrders(data []byte, format FileFormat, opts LoadOptions) [][]string {
+	switch format {
+	case FormatTOML:
+		return [][]string{tomlSectionOrder(data, opts.TOMLRoot)}
+	case FormatYAML:
+		return [][]string{yamlSectionOrder(data, opts.YAMLRoot)}
+	case FormatJSON:
+		return jsonSectionOrders(data)
+	default:
+		return nil
+	}
+}
+
+// tomlSectionOrder returns the keys of the sections table under root in the
+// order they are written
+func tomlSectionOrder(data []byte, root string) []string {
+	md, err := toml.NewDecoder(bytes.NewReader(data)).Decode(&map[string]interface{}{})
+	if err != nil {
+		return nil
+	}
+
+	var prefix []string
+	if root != "" {
+		prefix = strings.Split(root, ".")
+	}
+	prefix = append(prefix, "sections")
+
+	// The list form is ordered by position already
+	if md.Type(prefix...) == "ArrayHash" {
+		return nil
+	}
+
+	var keys []string
+	seen := make(map[string]bool)
+	for _, key := range md.Keys() {
+		if len(key) <= len(prefix) || !hasKeyPrefix(key, prefix) {
+			continue
+		}
+		name := key[len(prefix)]
+		if !seen[name] {
+			seen[name] = true
+			keys = append(keys, name)
+		}
+	}
+	return keys
+}
+
+// hasKeyPrefix reports whether key starts with prefix
+func hasKeyPrefix(key toml.Key, prefix []string) bool {
+	for i, part := range prefix {
+		if key[i] != part {
+			return false
+		}
+	}
+	return true
+}
+
+// yamlSectionOrder returns the keys of the sections mapping under root in
+// the order they are written
+func yamlSectionOrder(data []byte, root string) []string {
+	var doc yaml.Node
+	if yaml.Unmarshal(data, &doc) != nil {
+		return nil
+	}
+	node := &doc
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if root != "" {
+		for _, key := range strings.Split(root, ".") {
+			node = yamlMappingValue(node, key)
+			if node == nil {
+				return nil
+			}
+		}
+	}
+
+	sections := yamlMappingValue(node, "sections")
+	if sections == nil || sections.Kind != yaml.MappingNode {
+		return nil
+	}
+	var keys []string
+	for i := 0; i+1 < len(sections.Content); i += 2 {
+		keys = append(keys, sections.Content[i].Value)
+	}
+	return keys
+}
+
+// jsonSectionOrders returns the section keys of the config in data, or of
+// each config of a top-level array, in the order they are written
+func jsonSectionOrders(data []byte) [][]string {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	token, err := dec.Token()
+	if err != nil {
+		return nil
+	}
+	if token != json.Delim('[') {
+		if token != json.Delim('{') {
+			return nil
+		}
+		return [][]string{jsonObjectSectionOrder(dec)}
+	}
+
+	var orders [][]string
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return orders
+		}
+		if token != json.Delim('{') {
+			if skipJSONValue(dec, token) != nil {
+				return orders
+			}
+			orders = append(orders, nil)
+			continue
+		}
+		orders = append(orders, jsonObjectSectionOrder(dec))
+	}
+	return orders
+}
+
+// jsonObjectSectionOrder reads the rest of an object whose opening brace
+// has been read, returning the keys of its "sections" object in order
+func jsonObjectSectionOrder(dec *json.Decoder) []string {
+	var keys []string
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return keys
+		}
+		name, _ := token.(string)
+		value, err := dec.Token()
+		if err != nil {
+			return keys
+		}
+		if name == "sections" && value == json.Delim('{') {
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return keys
+				}
+				if s, ok := key.(string); ok {
+					keys = append(keys, s)
+				}
+				first, err := dec.Token()
+				if err != nil || skipJSONValue(dec, first) != nil {
+					return keys
+				}
+			}
+		}
+		if skipJSONValue(dec, value) != nil {
+			return keys
+		}
+	}
+	_, _ = dec.Token()
+	return keys
+}
+
+// skipJSONValue reads the rest of the value that first opened
+func skipJSONValue(dec *json.Decoder, first json.Token) error {
+	depth := 0
+	for token := first; ; {
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth <= 0 {
+			return nil
+		}
+		var err error
+		token, err = dec.Token()
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withoutSeq returns sections with their Seq cleared, for comparing loaded
+// sections with literals
+func withoutSeq(sections map[string]Section) Sections {
+	cleared := make(Sections, len(sections))
+	for key, section := range sections {
+		section.Seq = 0
+		cleared[key] = section
+	}
+	return cleared
+}
+
+func TestSortedSectionKeys(t *testing.T) {
+	sections := map[string]Section{
+		"zeta":  {Order: 0, Seq: 1},
+		"alpha": {Order: 0, Seq: 2},
+		"beta":  {Order: 0},
+		"gamma": {Order: 0},
+		"first": {Order: -1, Seq: 9},
+		"last":  {Order: 1},
+	}
+
+	assert.Equal(t, []string{"first", "beta", "gamma", "zeta", "alpha", "last"}, SortedSectionKeys(sections))
+}
+
+func TestLoadConfig_SectionSeq(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "toml",
+			file:    "order.toml",
+			content: "[sections.zeta]\ncontent = \"Z\"\n\n[sections.alpha]\ncontent = \"A\"\n\n[sections.mid.priority]\ntype = \"explicit\"\nvalue = 1\n",
+			want:    []string{"zeta", "alpha", "mid"},
+		},
+		{
+			name:    "yaml",
+			file:    "order.yaml",
+			content: "sections:\n  zeta:\n    content: Z\n  alpha:\n    content: A\n  mid:\n    content: M\n",
+			want:    []string{"zeta", "alpha", "mid"},
+		},
+		{
+			name:    "json",
+			file:    "order.json",
+			content: "{\"metadata\": {\"title\": \"T\"}, \"sections\": {\"zeta\": {\"content\": \"Z\", \"tags\": [\"a\"]}, \"alpha\": {\"content\": \"A\"}, \"mid\": {\"content\": \"M\"}}}",
+			want:    []string{"zeta", "alpha", "mid"},
+		},
+		{
+			name:    "markdown",
+			file:    "order.md",
+			content: "# Zeta\n\nZ\n",
+			want:    []string{"content"},
+		},
+		{
+			name:    "toml list form",
+			file:    "list.toml",
+			content: "[[sections]]\nkey = \"zeta\"\ncontent = \"Z\"\n\n[[sections]]\nkey = \"alpha\"\ncontent = \"A\"\n",
+			want:    []string{"zeta", "alpha"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), tt.file)
+			require.NoError(t, os.WriteFile(filename, []byte(tt.content), 0644))
+
+			cfg, err := LoadConfig(filename)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, SortedSectionKeys(cfg.Sections))
+			for _, key := range tt.want {
+				assert.NotZero(t, cfg.Sections[key].Seq, key)
+			}
+		})
+	}
+}
+
+func TestLoadConfigs_SectionSeqAcrossFiles(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.yaml")
+	second := filepath.Join(dir, "second.yaml")
+	require.NoError(t, os.WriteFile(first, []byte("sections:\n  zeta:\n    content: Z\n"), 0644))
+	require.NoError(t, os.WriteFile(second, []byte("sections:\n  alpha:\n    content: A\n"), 0644))
+
+	configs, err := LoadConfigs(context.Background(), []string{first, second})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), configs[0].Sections["zeta"].Seq)
+	assert.Equal(t, int64(2), configs[1].Sections["alpha"].Seq)
+
+	// Each run numbers from the start again
+	again, err := LoadConfigs(context.Background(), []string{second})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), again[0].Sections["alpha"].Seq)
+}
+
+func TestSequenceSections(t *testing.T) {
+	first := &Config{Sections: map[string]Section{"b": {Seq: 2}, "a": {Seq: 1}, "c": {}}}
+	second := &Config{Sections: map[string]Section{"d": {Seq: 1}}}
+
+	SequenceSections([]*Config{first, second})
+
+	assert.Equal(t, int64(1), first.Sections["c"].Seq, "unnumbered sections come first, by key")
+	assert.Equal(t, int64(2), first.Sections["a"].Seq)
+	assert.Equal(t, int64(3), first.Sections["b"].Seq)
+	assert.Equal(t, int64(4), second.Sections["d"].Seq)
+}
+
+func TestSection_SeqNotEncoded(t *testing.T) {
+	data, err := json.Marshal(Section{Content: "A", Seq: 3})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "seq")
+}
+
+func TestJSONSectionOrders_Array(t *testing.T) {
+	data := []byte(`[{"sections": {"b": {}, "a": {"content": "{[x]}"}}}, 7, {"metadata": {"sections": {"x": 1}}, "sections": {"d": {}, "c": {}}}]`)
+
+	assert.Equal(t, [][]string{{"b", "a"}, nil, {"d", "c"}}, jsonSectionOrders(data))
+}
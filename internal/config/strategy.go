@@ -0,0 +1,164 @@
+package config
+
+import (
+	"html"
+	"strings"
+	"sync"
+)
+
+var (
+	// customStrategies holds the strategies added with RegisterStrategy, by
+	// name, guarded by customMu
+	customStrategies = make(map[string]func(old, new string) string)
+	customMu         sync.RWMutex
+)
+
+// RegisterStrategy makes fn the merge strategy called name; see
+// merger.RegisterStrategy
+func RegisterStrategy(name string, fn func(old, new string) string) {
+	if name == "" {
+		panic("config: RegisterStrategy with empty name")
+	}
+	if fn == nil {
+		panic("config: RegisterStrategy with nil function for " + name)
+	}
+	customMu.Lock()
+	defer customMu.Unlock()
+	customStrategies[name] = fn
+}
+
+// UnregisterStrategy removes the strategy registered under name with
+// RegisterStrategy, restoring the built-in strategy of that name, if any.
+// Unknown names are ignored.
+func UnregisterStrategy(name string) {
+	customMu.Lock()
+	defer customMu.Unlock()
+	delete(customStrategies, name)
+}
+
+// RegisteredStrategy returns the strategy registered under name with
+// RegisterStrategy, if any
+func RegisteredStrategy(name string) (func(old, new string) string, bool) {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	fn, ok := customStrategies[name]
+	return fn, ok
+}
+
+// isStrategy reports whether name is a built-in or registered strategy
+func isStrategy(name string) bool {
+	if validStrategies[name] {
+		return true
+	}
+	_, ok := RegisteredStrategy(name)
+	return ok
+}
+
+// CombineContent joins old and new content using the named merge strategy,
+// where new content came from the file source. It implements the merger
+// package's strategies, which config cannot import, so that section includes
+// can be combined at load time. Registered strategies come first; unknown
+// strategies replace.
+func CombineContent(strategy, old, new, source string) string {
+	if fn, ok := RegisteredStrategy(strategy); ok {
+		return fn(old, new)
+	}
+	switch strategy {
+	case "append":
+		if old == "" {
+			return new
+		}
+		return old + "\n" + new
+	case "prepend":
+		if old == "" {
+			return new
+		}
+		return new + "\n" + old
+	case "collapse":
+		// The base content stays visible; only appended content is folded
+		collapsed := collapse(new, source)
+		if old == "" {
+			return collapsed
+		}
+		return old + "\n" + collapsed
+	case "keyvalue":
+		return combineKeyValues(old, new)
+	default:
+		return new
+	}
+}
+
+// collapse wraps content in a <details> block whose summary names source.
+// The blank lines around content keep it rendered as markdown.
+func collapse(content, source string) string {
+	label := source
+	if label == "" {
+		label = "More"
+	}
+	return "<details>\n<summary>" + html.EscapeString(label) + "</summary>\n\n" +
+		content + "\n\n</details>"
+}
+
+// entryKey returns the key of a "Key: value" line and true, or false for any
+// other line. The colon must end the line or be followed by a space, so
+// lines holding URLs don't count.
+func entryKey(line string) (string, bool) {
+	idx := strings.Index(line, ":")
+	if idx < 0 || (idx+1 < len(line) && line[idx+1] != ' ' && line[idx+1] != '\t') {
+		return "", false
+	}
+	key := strings.TrimSpace(line[:idx])
+	return key, key != ""
+}
+
+// combineKeyValues overlays the "Key: value" lines of new onto old. Each old
+// line whose key appears in new is replaced by new's line for that key, the
+// last one if new repeats it. New's remaining lines are added at the end in
+// their order, skipping lines without a key that old already has, such as a
+// shared heading. Old lines without a key stay where they are.
+func combineKeyValues(old, new string) string {
+	if old == "" || new == "" {
+		return old + new
+	}
+	oldLines := strings.Split(old, "\n")
+	newLines := strings.Split(new, "\n")
+
+	updates := make(map[string]string)
+	for _, line := range newLines {
+		if key, ok := entryKey(line); ok {
+			updates[key] = line
+		}
+	}
+
+	present := make(map[string]bool)
+	merged := make([]string, 0, len(oldLines)+len(newLines))
+	for _, line := range oldLines {
+		if key, ok := entryKey(line); ok {
+			present[key] = true
+			if update, ok := updates[key]; ok {
+				line = update
+			}
+		}
+		merged = append(merged, line)
+	}
+
+	oldText := make(map[string]bool, len(oldLines))
+	for _, line := range oldLines {
+		oldText[line] = true
+	}
+	added := make(map[string]bool)
+	for _, line := range newLines {
+		key, ok := entryKey(line)
+		switch {
+		case ok && (present[key] || added[key]):
+			continue
+		case ok:
+			added[key] = true
+			line = updates[key]
+		case oldText[line]:
+			continue
+		}
+		merged = append(merged, line)
+	}
+	return strings.Join(merged, "\n")
+}
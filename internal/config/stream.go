@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// StreamOptions controls how LoadStream splits and parses a stream of configs
+type StreamOptions struct {
+	// Delimiter is the text of the lines separating configs in the stream
+	Delimiter string
+
+	// Format is the format of every config in the stream
+	Format FileFormat
+
+	// SkipInvalid skips a chunk that fails to parse instead of failing the
+	// whole stream
+	SkipInvalid bool
+
+	// Load applies to each chunk as it would to a file
+	Load LoadOptions
+}
+
+// streamChunk is one config's text in a stream, and the line it starts on
+type streamChunk struct {
+	text string
+	line int
+}
+
+// LoadStream reads the configs in r, which are separated by lines holding
+// only opts.Delimiter, and returns them in stream order. Each chunk is
+// loaded like a file named name#N, counting chunks from 1, whose content
+// files and includes are resolved against the working directory.
+//
+// A blank chunk before the first delimiter or after the last is ignored, so
+// a stream may open or close with a delimiter; a blank chunk between two
+// delimiters is an empty config. A chunk that fails to load fails the
+// stream, unless opts.SkipInvalid is set: then its error is returned in
+// skipped and the rest of the stream is still loaded.
+func LoadStream(r io.Reader, name string, opts StreamOptions) (configs []*Config, skipped []error, err error) {
+	if opts.Delimiter == "" {
+		return nil, nil, errors.New("stream delimiter must not be empty")
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, &ConfigError{Kind: KindNotFound, Filename: name, Err: err, action: "read"}
+	}
+
+	for i, chunk := range splitStream(string(StripBOM(data)), opts.Delimiter) {
+		filename := fmt.Sprintf("%s#%d", name, i+1)
+		loaded, err := decodeConfigs(fileSource{}, filename, []byte(chunk.text), opts.Format, opts.Load)
+		if err != nil {
+			err = fmt.Errorf("%w (chunk starts at line %d)", err, chunk.line)
+			if !opts.SkipInvalid {
+				return nil, nil, err
+			}
+			skipped = append(skipped, err)
+			continue
+		}
+		configs = append(configs, loaded...)
+	}
+	return configs, skipped, nil
+}
+
+// splitStream splits text into chunks at lines holding only delimiter,
+// ignoring a carriage return ending the line. Blank chunks opening or
+// closing the stream are dropped.
+func splitStream(text, delimiter string) []streamChunk {
+	var chunks []streamChunk
+	var lines []string
+	start := 1
+	for i, line := range strings.Split(text, "\n") {
+		if strings.TrimSuffix(line, "\r") == delimiter {
+			chunks = append(chunks, streamChunk{text: strings.Join(lines, "\n"), line: start})
+			lines = nil
+			start = i + 2
+			continue
+		}
+		lines = append(lines, line)
+	}
+	chunks = append(chunks, streamChunk{text: strings.Join(lines, "\n"), line: start})
+
+	if len(chunks) > 0 && strings.TrimSpace(chunks[0].text) == "" {
+		chunks = chunks[1:]
+	}
+	if len(chunks) > 0 && strings.TrimSpace(chunks[len(chunks)-1].text) == "" {
+		chunks = chunks[:len(chunks)-1]
+	}
+	return chunks
+}
+
+// ParseFormatName returns the format named by name: markdown (or md), toml,
+// yaml (or yml), or json, in any case
+func ParseFormatName(name string) (FileFormat, error) {
+	switch strings.ToLower(name) {
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	case "toml":
+		return FormatTOML, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "json":
+		return FormatJSON, nil
+	default:
+		return FormatTOML, fmt.Errorf("unknown format %q: must be markdown, toml, yaml, or json", name)
+	}
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitStream(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []streamChunk
+	}{
+		{
+			name: "two chunks",
+			text: "# One\n---8<---\n# Two\n",
+			want: []streamChunk{{text: "# One", line: 1}, {text: "# Two\n", line: 3}},
+		},
+		{
+			name: "framing delimiters and CRLF",
+			text: "---8<---\r\n# One\r\n---8<---\r\n# Two\r\n---8<---\r\n",
+			want: []streamChunk{{text: "# One\r", line: 2}, {text: "# Two\r", line: 4}},
+		},
+		{
+			name: "blank chunk between delimiters is kept",
+			text: "# One\n---8<---\n\n---8<---\n# Three",
+			want: []streamChunk{{text: "# One", line: 1}, {text: "", line: 3}, {text: "# Three", line: 5}},
+		},
+		{
+			name: "delimiter must fill the line",
+			text: "# One\ntext ---8<---\n",
+			want: []streamChunk{{text: "# One\ntext ---8<---\n", line: 1}},
+		},
+		{
+			name: "only delimiters",
+			text: "---8<---\n",
+			want: []streamChunk{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitStream(tt.text, "---8<---"))
+		})
+	}
+}
+
+func TestLoadStream(t *testing.T) {
+	stream := "\x1e\n[sections.one]\norder = 1\ncontent = \"# One\"\n\x1e\n[sections.two]\norder = 2\ncontent = \"# Two\"\n\x1e\n"
+
+	configs, skipped, err := LoadStream(strings.NewReader(stream), "stdin", StreamOptions{Delimiter: "\x1e", Format: FormatTOML})
+	require.NoError(t, err)
+	assert.Empty(t, skipped)
+	require.Len(t, configs, 2)
+	assert.Equal(t, "stdin#1", configs[0].SourceFile)
+	assert.Equal(t, "# One", configs[0].Sections["one"].Content)
+	assert.Equal(t, "stdin#2", configs[1].SourceFile)
+	assert.Equal(t, FormatTOML, configs[1].SourceFormat)
+}
+
+func TestLoadStream_InvalidChunk(t *testing.T) {
+	stream := "# One\n--\n[broken\n--\n# Three\n"
+	opts := StreamOptions{Delimiter: "--", Format: FormatTOML}
+
+	_, _, err := LoadStream(strings.NewReader(stream), "stdin", opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse stdin#2")
+	assert.Contains(t, err.Error(), "(chunk starts at line 3)")
+	var configErr *ConfigError
+	assert.True(t, errors.As(err, &configErr))
+
+	opts.Format = FormatMarkdown
+	_, _, err = LoadStream(strings.NewReader("# One\n--\n\x00\x01\n"), "stdin", opts)
+	assert.ErrorIs(t, err, ErrNotText)
+
+	opts.Format = FormatTOML
+	opts.SkipInvalid = true
+	configs, skipped, err := LoadStream(strings.NewReader("title = \"a\"\n--\n[broken\n--\ntitle = \"c\"\n"), "stdin", opts)
+	require.NoError(t, err)
+	require.Len(t, skipped, 1)
+	assert.Contains(t, skipped[0].Error(), "stdin#2")
+	assert.Contains(t, skipped[0].Error(), "(chunk starts at line 3)")
+	require.Len(t, configs, 2)
+	assert.Equal(t, "stdin#1", configs[0].SourceFile)
+	assert.Equal(t, "stdin#3", configs[1].SourceFile)
+}
+
+func TestLoadStream_EmptyDelimiter(t *testing.T) {
+	_, _, err := LoadStream(strings.NewReader("# One"), "stdin", StreamOptions{Format: FormatMarkdown})
+	assert.Error(t, err)
+}
+
+func TestParseFormatName(t *testing.T) {
+	tests := map[string]FileFormat{
+		"markdown": FormatMarkdown,
+		"MD":       FormatMarkdown,
+		"toml":     FormatTOML,
+		"yml":      FormatYAML,
+		"YAML":     FormatYAML,
+		"json":     FormatJSON,
+	}
+	for name, want := range tests {
+		format, err := ParseFormatName(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, format, name)
+	}
+
+	_, err := ParseFormatName("ini")
+	assert.Error(t, err)
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// parseYAMLSubtree parses only the part of a YAML document found at root, a
+// dot-separated key path such as "tools.claude"
+func parseYAMLSubtree(data []byte, root string) (*Config, error) {
+	var doc yaml.Node
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("YAML parse error: %w", err)
+	}
+
+	node := &doc
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	var path []string
+	for _, key := range strings.Split(root, ".") {
+		path = append(path, key)
+		node = yamlMappingValue(node, key)
+		if node == nil {
+			return nil, fmt.Errorf("YAML root %q not found: no key %s", root, strings.Join(path, "."))
+		}
+	}
+
+	var config Config
+	err = node.Decode(&config)
+	if err != nil {
+		return nil, fmt.Errorf("YAML parse error under %s: %w", root, err)
+	}
+
+	initConfig(&config, FormatYAML)
+	return &config, nil
+}
+
+// yamlMappingValue returns the value for key in a mapping node, or nil
+func yamlMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// parseTOMLSubtree parses only the table found at root, a dot-separated key
+// path such as "tools.claude"
+func parseTOMLSubtree(data []byte, root string) (*Config, error) {
+	var tables map[string]toml.Primitive
+	md, err := toml.NewDecoder(bytes.NewReader(data)).Decode(&tables)
+	if err != nil {
+		return nil, fmt.Errorf("TOML parse error: %w", err)
+	}
+
+	var path []string
+	keys := strings.Split(root, ".")
+	for i, key := range keys {
+		path = append(path, key)
+		primitive, ok := tables[key]
+		if !ok {
+			return nil, fmt.Errorf("TOML root %q not found: no key %s", root, strings.Join(path, "."))
+		}
+
+		if i == len(keys)-1 {
+			var config Config
+			err = md.PrimitiveDecode(primitive, &config)
+			if err != nil {
+				return nil, fmt.Errorf("TOML parse error under %s: %w", root, err)
+			}
+			initConfig(&config, FormatTOML)
+			return &config, nil
+		}
+
+		// Tables only implied by a dotted header such as [a.b.c] have no type
+		if kind := md.Type(path...); kind != "Hash" && kind != "" {
+			return nil, fmt.Errorf("TOML root %q not found: %s is not a table", root, strings.Join(path, "."))
+		}
+		tables = nil
+		err = md.PrimitiveDecode(primitive, &tables)
+		if err != nil {
+			return nil, fmt.Errorf("TOML parse error under %s: %w", strings.Join(path, "."), err)
+		}
+	}
+
+	return nil, fmt.Errorf("TOML root cannot be empty")
+}
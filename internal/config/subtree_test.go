@@ -0,0 +1,87 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigWithOptions_YAMLRoot(t *testing.T) {
+	content := `
+server:
+  port: 8080
+tools:
+  claude:
+    metadata:
+      title: "Embedded"
+      priority:
+        type: "relative"
+        value: 2
+    sections:
+      test:
+        content: "Embedded content"
+        priority:
+          type: "relative"
+          value: 1
+`
+	filename := filepath.Join(t.TempDir(), "app.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0644))
+
+	config, err := LoadConfigWithOptions(filename, LoadOptions{YAMLRoot: "tools.claude"})
+	require.NoError(t, err)
+	assert.Equal(t, "Embedded", config.Metadata.Title)
+	assert.Equal(t, "Embedded content", config.Sections["test"].Content)
+	assert.Equal(t, Priority{Type: PriorityRelative, Value: 3}, config.Sections["test"].Priority)
+	assert.NotNil(t, config.MergePoints)
+	assert.Equal(t, FormatYAML, config.SourceFormat)
+
+	_, err = LoadConfigWithOptions(filename, LoadOptions{YAMLRoot: "tools.missing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `YAML root "tools.missing" not found: no key tools.missing`)
+
+	_, err = LoadConfigWithOptions(filename, LoadOptions{YAMLRoot: "server.port.value"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no key server.port.value")
+}
+
+func TestLoadConfigWithOptions_TOMLRoot(t *testing.T) {
+	content := `
+[server]
+port = 8080
+
+[tools.claude.metadata]
+title = "Embedded"
+
+[tools.claude.sections.test]
+order = 1
+content = "Embedded content"
+`
+	filename := filepath.Join(t.TempDir(), "app.toml")
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0644))
+
+	config, err := LoadConfigWithOptions(filename, LoadOptions{TOMLRoot: "tools.claude"})
+	require.NoError(t, err)
+	assert.Equal(t, "Embedded", config.Metadata.Title)
+	assert.Equal(t, Section{Order: 1, Content: "Embedded content"}, withoutSeq(config.Sections)["test"])
+	assert.Equal(t, FormatTOML, config.SourceFormat)
+
+	_, err = LoadConfigWithOptions(filename, LoadOptions{TOMLRoot: "claude"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `TOML root "claude" not found: no key claude`)
+
+	_, err = LoadConfigWithOptions(filename, LoadOptions{TOMLRoot: "server.port.value"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server.port is not a table")
+}
+
+func TestLoadConfigWithOptions_RootIgnoredForOtherFormats(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "notes.md")
+	require.NoError(t, os.WriteFile(filename, []byte("# Notes"), 0644))
+
+	config, err := LoadConfigWithOptions(filename, LoadOptions{YAMLRoot: "claude", TOMLRoot: "claude"})
+	require.NoError(t, err)
+	assert.Equal(t, "# Notes", config.Sections["content"].Content)
+}
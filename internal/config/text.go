@@ -0,0 +1,57 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// ErrNotText is wrapped by the error for an input that looks binary
+var ErrNotText = errors.New("does not appear to be text")
+
+const (
+	// textSampleSize is how much of a file is inspected for binary content
+	textSampleSize = 8000
+
+	// maxSuspiciousShare is the largest share of the sample that may be
+	// invalid UTF-8 or control characters, one byte in 32, before the file
+	// counts as binary. A few stray bytes, such as a Latin-1 name, pass.
+	maxSuspiciousShare = 32
+)
+
+// checkText returns nil if data looks like text. A NUL byte anywhere in the
+// sample marks binary data, as do invalid UTF-8 sequences and control
+// characters other than tab, newline, form feed, and carriage return
+// making up more than one byte in maxSuspiciousShare.
+func checkText(data []byte) error {
+	sample := data
+	if len(sample) > textSampleSize {
+		sample = sample[:textSampleSize]
+	}
+
+	if i := bytes.IndexByte(sample, 0); i >= 0 {
+		return fmt.Errorf("found a NUL byte at offset %d", i)
+	}
+
+	suspicious := 0
+	for i := 0; i < len(sample); {
+		r, size := utf8.DecodeRune(sample[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			// A sequence cut off by the end of the sample is not an error
+			if len(data) > len(sample) && !utf8.FullRune(sample[i:]) {
+				i = len(sample)
+				continue
+			}
+			suspicious++
+		case r < 0x20 && r != '\t' && r != '\n' && r != '\f' && r != '\r', r == 0x7f:
+			suspicious++
+		}
+		i += size
+	}
+	if suspicious*maxSuspiciousShare > len(sample) {
+		return fmt.Errorf("%d of the first %d bytes are not valid UTF-8 text", suspicious, len(sample))
+	}
+	return nil
+}
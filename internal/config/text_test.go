@@ -0,0 +1,58 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckText(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   []byte
+		isText bool
+	}{
+		{name: "markdown", data: []byte("# Title\n\n- item\twith tab\r\n"), isText: true},
+		{name: "unicode", data: []byte("# Überschrift 🚨\n"), isText: true},
+		{name: "empty", data: nil, isText: true},
+		{name: "stray latin-1 byte", data: append([]byte("# Caf"), append([]byte{0xe9}, []byte(strings.Repeat(" text", 20))...)...), isText: true},
+		{name: "multi-byte rune cut by the sample", data: []byte(strings.Repeat("a", textSampleSize-1) + "é and more"), isText: true},
+		{name: "nul byte", data: []byte("# Title\x00\n"), isText: false},
+		{name: "png header", data: []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), isText: false},
+		{name: "invalid utf-8 run", data: []byte("# Title\n\xff\xfe\xfd\xfc\xfb\xfa\xf9\xf8"), isText: false},
+		{name: "control characters", data: []byte("\x01\x02\x03\x04 text"), isText: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkText(tt.data)
+			if tt.isText {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Binary(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "image.md")
+	require.NoError(t, os.WriteFile(filename, []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), 0644))
+
+	_, err := LoadConfig(filename)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrNotText))
+	assert.Contains(t, err.Error(), "file "+filename+" does not appear to be text")
+	var configErr *ConfigError
+	require.True(t, errors.As(err, &configErr))
+	assert.Equal(t, KindParseError, configErr.Kind)
+
+	_, err = LoadConfigWithOptions(filename, LoadOptions{AllowBinary: true})
+	assert.NoError(t, err)
+}
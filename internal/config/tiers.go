@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MergePriorityTiers combines the priority_tiers tables of every config. A
+// tier defined with different values in two files is an error.
+func MergePriorityTiers(configs []*Config) (map[string]int, error) {
+	tiers := make(map[string]int)
+	definedIn := make(map[string]string)
+
+	for _, config := range configs {
+		names := make([]string, 0, len(config.PriorityTiers))
+		for name := range config.PriorityTiers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			value := config.PriorityTiers[name]
+			if existing, exists := tiers[name]; exists && existing != value {
+				return nil, fmt.Errorf("priority tier %s is %d in %s but %d in %s",
+					name, existing, definedIn[name], value, config.SourceFile)
+			}
+			tiers[name] = value
+			definedIn[name] = config.SourceFile
+		}
+	}
+	return tiers, nil
+}
+
+// ResolvePriorityTiers replaces every tier-named priority in configs with the
+// explicit value from the combined priority_tiers tables. Relative priorities
+// in a config whose own priority was a tier are resolved once that tier is
+// known. Unknown tier names and conflicting tier definitions are errors.
+func ResolvePriorityTiers(configs []*Config) error {
+	tiers, err := MergePriorityTiers(configs)
+	if err != nil {
+		return err
+	}
+
+	for _, config := range configs {
+		deferred := config.Metadata.Priority.Tier != ""
+		err := resolveTiers(config, tiers)
+		if err != nil {
+			return fmt.Errorf("%s: %w", config.SourceFile, err)
+		}
+		if deferred {
+			resolveRelativePriorities(config)
+		}
+	}
+	return nil
+}
+
+// resolveTiers resolves the tier-named priorities of one config against
+// tiers, leaving priorities with unknown tiers untouched and reporting the
+// first one found
+func resolveTiers(config *Config, tiers map[string]int) error {
+	var unknown []string
+	resolve := func(what string, priority *Priority) {
+		if priority.Tier == "" {
+			return
+		}
+		value, ok := tiers[priority.Tier]
+		if !ok {
+			unknown = append(unknown, fmt.Sprintf("%s has unknown priority tier %s", what, priority.Tier))
+			return
+		}
+		*priority = NewExplicitPriority(value)
+	}
+
+	resolve("metadata", &config.Metadata.Priority)
+	for name, section := range config.Sections {
+		resolve("section "+name, &section.Priority)
+		config.Sections[name] = section
+	}
+	for name, point := range config.MergePoints {
+		resolve("merge point "+name, &point.Priority)
+		config.MergePoints[name] = point
+	}
+	for name, target := range config.MergeTargets {
+		resolve("merge target "+name, &target.Priority)
+		config.MergeTargets[name] = target
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("%s", unknown[0])
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriority_TierForms(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		filename string
+	}{
+		{
+			name:     "TOML",
+			filename: "tiers.toml",
+			content: `
+[priority_tiers]
+base = 0
+project = 20
+
+[sections.tier]
+priority = "project"
+
+[sections.number]
+priority = "15"
+
+[sections.bare]
+priority = 15
+
+[sections.table]
+priority = { type = "relative", value = 3 }
+`,
+		},
+		{
+			name:     "YAML",
+			filename: "tiers.yaml",
+			content: `
+priority_tiers:
+  base: 0
+  project: 20
+sections:
+  tier:
+    priority: project
+  number:
+    priority: "15"
+  bare:
+    priority: 15
+  table:
+    priority:
+      type: relative
+      value: 3
+`,
+		},
+		{
+			name:     "JSON",
+			filename: "tiers.json",
+			content: `{
+  "priority_tiers": {"base": 0, "project": 20},
+  "sections": {
+    "tier": {"priority": "project"},
+    "number": {"priority": "15"},
+    "bare": {"priority": 15},
+    "table": {"priority": {"type": "relative", "value": 3}}
+  }
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := testLoadFromContent(t, tt.content, tt.filename)
+			require.NoError(t, err)
+
+			assert.Equal(t, NewExplicitPriority(20), config.Sections["tier"].Priority)
+			assert.Equal(t, NewExplicitPriority(15), config.Sections["number"].Priority)
+			assert.Equal(t, NewExplicitPriority(15), config.Sections["bare"].Priority)
+			assert.Equal(t, NewRelativePriority(3), config.Sections["table"].Priority)
+		})
+	}
+}
+
+func TestPriority_TierInFrontmatter(t *testing.T) {
+	config, err := ParseConfig([]byte("---\ntitle: Team\npriority: team\n---\n# Team"), FormatMarkdown)
+	require.NoError(t, err)
+	assert.Equal(t, Priority{Type: PriorityExplicit, Tier: "team"}, config.Metadata.Priority)
+}
+
+func TestMergePriorityTiers(t *testing.T) {
+	configs := []*Config{
+		{SourceFile: "base.toml", PriorityTiers: map[string]int{"base": 0, "team": 10}},
+		{SourceFile: "team.toml", PriorityTiers: map[string]int{"team": 10, "project": 20}},
+	}
+
+	tiers, err := MergePriorityTiers(configs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"base": 0, "team": 10, "project": 20}, tiers)
+
+	configs = append(configs, &Config{SourceFile: "rogue.toml", PriorityTiers: map[string]int{"team": 50}})
+	_, err = MergePriorityTiers(configs)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "priority tier team is 10 in team.toml but 50 in rogue.toml")
+}
+
+func TestLoadConfigs_ResolvesTiersAcrossFiles(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.toml")
+	project := filepath.Join(dir, "project.yaml")
+	require.NoError(t, os.WriteFile(base, []byte(`
+[priority_tiers]
+team = 10
+project = 20
+`), 0644))
+	require.NoError(t, os.WriteFile(project, []byte(`
+metadata:
+  priority: project
+sections:
+  testing:
+    priority:
+      type: relative
+      value: 2
+  style:
+    priority: team
+`), 0644))
+
+	configs, err := LoadConfigs(context.Background(), []string{base, project})
+	require.NoError(t, err)
+
+	assert.Equal(t, NewExplicitPriority(20), configs[1].Metadata.Priority)
+	assert.Equal(t, NewRelativePriority(22), configs[1].Sections["testing"].Priority, "relative priorities resolve once the file's tier is known")
+	assert.Equal(t, NewExplicitPriority(10), configs[1].Sections["style"].Priority)
+
+	_, err = LoadConfigs(context.Background(), []string{project})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "metadata has unknown priority tier project")
+}
+
+func TestLoadConfig_UnknownTier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "project.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+sections:
+  style:
+    priority: projcet
+`), 0644))
+
+	_, err := LoadConfig(path)
+	var configErr *ConfigError
+	require.ErrorAs(t, err, &configErr)
+	assert.Equal(t, KindValidationError, configErr.Kind)
+	assert.Contains(t, err.Error(), "section style has unknown priority tier projcet")
+
+	config, err := LoadConfigWithOptions(path, LoadOptions{SharedTiers: true})
+	require.NoError(t, err, "a tier may be defined by another file")
+	assert.Equal(t, "projcet", config.Sections["style"].Priority.Tier)
+}
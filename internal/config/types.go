@@ -1,8 +1,14 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -16,57 +22,212 @@ const (
 	FormatTOML FileFormat = iota
 	FormatYAML
 	FormatMarkdown
+	FormatJSON
 )
 
 // Config represents the entire configuration file
 // Works across TOML, YAML, and Markdown formats
 type Config struct {
-	Metadata     Metadata                `toml:"metadata" yaml:"metadata"`
-	Sections     map[string]Section      `toml:"sections" yaml:"sections"`
-	MergePoints  map[string]MergePoint   `toml:"merge_points" yaml:"merge_points"`
-	MergeTargets map[string]MergeTarget  `toml:"merge_targets" yaml:"merge_targets"`
-	SourceFile   string                  // Track which file this came from
-	SourceFormat FileFormat              // Track the original format
+	Metadata      Metadata               `toml:"metadata" yaml:"metadata" json:"metadata"`
+	Sections      Sections               `toml:"sections" yaml:"sections" json:"sections"`
+	MergePoints   map[string]MergePoint  `toml:"merge_points" yaml:"merge_points" json:"merge_points"`
+	MergeTargets  map[string]MergeTarget `toml:"merge_targets" yaml:"merge_targets" json:"merge_targets"`
+	PriorityTiers map[string]int         `toml:"priority_tiers" yaml:"priority_tiers" json:"priority_tiers,omitempty"`
+	SourceFile    string                 `toml:"-" yaml:"-" json:"source_file,omitempty"` // Track which file this came from
+	SourceFormat  FileFormat             `toml:"-" yaml:"-" json:"-"`                     // Track the original format
+}
+
+// IsEmpty reports whether the config contributes nothing to a merge: no
+// metadata, sections, merge points, merge targets, or priority tiers. Blank
+// and comment-only files parse to an empty config.
+func (c *Config) IsEmpty() bool {
+	metadata := c.Metadata
+	metadata.Priority = Priority{}
+	extra := metadata.Extra
+	metadata.Extra = nil
+	return reflect.DeepEqual(metadata, Metadata{}) &&
+		len(extra) == 0 &&
+		len(c.Sections) == 0 &&
+		len(c.MergePoints) == 0 &&
+		len(c.MergeTargets) == 0 &&
+		len(c.PriorityTiers) == 0
 }
 
 // Metadata contains information about the configuration
 type Metadata struct {
-	Title       string   `toml:"title" yaml:"title"`
-	Description string   `toml:"description" yaml:"description"`
-	Version     string   `toml:"version" yaml:"version"`
-	Language    string   `toml:"language" yaml:"language"`
-	Extends     string   `toml:"extends" yaml:"extends"`
-	Priority    Priority `toml:"priority" yaml:"priority"`
+	Title       string   `toml:"title" yaml:"title" json:"title"`
+	Description string   `toml:"description" yaml:"description" json:"description"`
+	Version     string   `toml:"version" yaml:"version" json:"version"`
+	Language    string   `toml:"language" yaml:"language" json:"language"`
+	Extends     string   `toml:"extends" yaml:"extends" json:"extends"`
+	Priority    Priority `toml:"priority" yaml:"priority" json:"priority"`
+
+	// HeadingOffset moves every heading in the file's sections this many
+	// levels deeper, or shallower when negative, so a fragment written from
+	// level 1 can nest under a heading of the combined document
+	HeadingOffset int `toml:"heading_offset" yaml:"heading_offset" json:"heading_offset,omitempty"`
+
+	// Extra holds metadata fields the tool doesn't interpret, such as tags
+	// or authors in markdown frontmatter
+	Extra map[string]interface{} `toml:"extra" yaml:"extra" json:"extra,omitempty"`
 }
 
 // Section represents a piece of content in the final document
 type Section struct {
-	Order       int      `toml:"order" yaml:"order"`
-	Parent      string   `toml:"parent" yaml:"parent"`
-	MergeID     string   `toml:"merge_id" yaml:"merge_id"`
-	Content     string   `toml:"content" yaml:"content"`
-	MergePoints []string `toml:"merge_points" yaml:"merge_points"`
-	Priority    Priority `toml:"priority" yaml:"priority"`
+	Order       int      `toml:"order" yaml:"order" json:"order"`
+	Parent      string   `toml:"parent" yaml:"parent" json:"parent"`
+	MergeID     string   `toml:"merge_id" yaml:"merge_id" json:"merge_id"`
+	Content     string   `toml:"content" yaml:"content" json:"content"`
+	ContentFile string   `toml:"content_file" yaml:"content_file" json:"content_file"`
+	Includes    []string `toml:"includes" yaml:"includes" json:"includes,omitempty"`
+	Strategy    string   `toml:"strategy" yaml:"strategy" json:"strategy,omitempty"`
+	MergePoints []string `toml:"merge_points" yaml:"merge_points" json:"merge_points"`
+	Priority    Priority `toml:"priority" yaml:"priority" json:"priority"`
+	Condition   string   `toml:"condition" yaml:"condition" json:"condition"`
+	Anchor      string   `toml:"anchor" yaml:"anchor" json:"anchor"`
+	Alias       string   `toml:"alias" yaml:"alias" json:"alias"`
+	Tags        []string `toml:"tags" yaml:"tags" json:"tags,omitempty"`
+
+	// Heading is the section's markdown heading line exactly as written in
+	// its source, such as "## STRICT REQUIREMENTS!!". Output starts the
+	// section with it whenever the content has no heading of its own, so
+	// punctuation and casing lost from the key survive the merge.
+	Heading string `toml:"heading" yaml:"heading" json:"heading,omitempty"`
+
+	// Final locks the section: once merged, no later config replaces it,
+	// whatever its priority
+	Final bool `toml:"final" yaml:"final" json:"final,omitempty"`
+
+	// Seq numbers sections in the order they were loaded: by where they
+	// appear in their file, and with SequenceSections across every file of
+	// a run. It breaks ties between equal orders, so output follows the
+	// order sections were written in. Zero means unknown, as for sections
+	// built in code. It is never read from or written to a config.
+	Seq int64 `toml:"-" yaml:"-" json:"-"`
 }
 
 // MergePoint defines a place where content can be inserted
 type MergePoint struct {
-	Placeholder string   `toml:"placeholder" yaml:"placeholder"`
-	Default     string   `toml:"default" yaml:"default"`
-	Priority    Priority `toml:"priority" yaml:"priority"`
+	Placeholder string   `toml:"placeholder" yaml:"placeholder" json:"placeholder"`
+	Default     string   `toml:"default" yaml:"default" json:"default"`
+	Priority    Priority `toml:"priority" yaml:"priority" json:"priority"`
 }
 
 // MergeTarget is content that fills a merge point
 type MergeTarget struct {
-	Strategy string   `toml:"strategy" yaml:"strategy"`
-	Content  string   `toml:"content" yaml:"content"`
-	Priority Priority `toml:"priority" yaml:"priority"`
+	Strategy    string   `toml:"strategy" yaml:"strategy" json:"strategy"`
+	Content     string   `toml:"content" yaml:"content" json:"content"`
+	ContentFile string   `toml:"content_file" yaml:"content_file" json:"content_file"`
+	Priority    Priority `toml:"priority" yaml:"priority" json:"priority"`
+	Source      string   `toml:"-" yaml:"-" json:"source,omitempty"` // File the target was merged from
 }
 
 // Priority represents merge priority with explicit > relative > order-based
 type Priority struct {
-	Type  PriorityType `toml:"type" yaml:"type"`
-	Value int          `toml:"value" yaml:"value"`
+	Type  PriorityType `toml:"type" yaml:"type" json:"type"`
+	Value int          `toml:"value" yaml:"value" json:"value"`
+
+	// Tier is a named priority tier that has not been resolved to a value yet
+	Tier string `toml:"-" yaml:"-" json:"tier,omitempty"`
+}
+
+// priorityFields decodes the table form of a priority without recursing into
+// Priority's own unmarshalers
+type priorityFields struct {
+	Type  PriorityType `toml:"type" yaml:"type" json:"type"`
+	Value int          `toml:"value" yaml:"value" json:"value"`
+}
+
+// parsePriorityName turns the short string form of a priority into a
+// Priority: "none", "explicit:N", and "relative:N" are taken as written, a
+// number is an explicit value, and anything else is a tier name resolved
+// later against the priority_tiers tables
+func parsePriorityName(name string) Priority {
+	name = strings.TrimSpace(name)
+	if strings.EqualFold(name, "none") {
+		return Priority{}
+	}
+
+	value, err := strconv.Atoi(name)
+	if err == nil {
+		return NewExplicitPriority(value)
+	}
+
+	if typeName, valueText, ok := strings.Cut(name, ":"); ok {
+		var priorityType PriorityType
+		value, err := strconv.Atoi(strings.TrimSpace(valueText))
+		if err == nil && priorityType.UnmarshalText([]byte(strings.TrimSpace(typeName))) == nil {
+			return Priority{Type: priorityType, Value: value}
+		}
+	}
+	return Priority{Type: PriorityExplicit, Tier: name}
+}
+
+// UnmarshalTOML implements the toml.Unmarshaler interface, accepting a
+// priority table, a tier name, or a bare explicit value
+func (p *Priority) UnmarshalTOML(data interface{}) error {
+	switch v := data.(type) {
+	case string:
+		*p = parsePriorityName(v)
+	case int64:
+		*p = NewExplicitPriority(int(v))
+	case map[string]interface{}:
+		var fields priorityFields
+		if typeName, ok := v["type"].(string); ok {
+			err := fields.Type.UnmarshalText([]byte(typeName))
+			if err != nil {
+				return err
+			}
+		}
+		if value, ok := v["value"].(int64); ok {
+			fields.Value = int(value)
+		}
+		*p = Priority{Type: fields.Type, Value: fields.Value}
+	default:
+		return fmt.Errorf("invalid priority: %v", data)
+	}
+	return nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface, accepting a
+// priority mapping, a tier name, or a bare explicit value
+func (p *Priority) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*p = parsePriorityName(node.Value)
+		return nil
+	}
+
+	var fields priorityFields
+	err := node.Decode(&fields)
+	if err != nil {
+		return err
+	}
+	*p = Priority{Type: fields.Type, Value: fields.Value}
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, accepting a
+// priority object, a tier name, or a bare explicit value
+func (p *Priority) UnmarshalJSON(data []byte) error {
+	var name string
+	if json.Unmarshal(data, &name) == nil {
+		*p = parsePriorityName(name)
+		return nil
+	}
+
+	var value int
+	if json.Unmarshal(data, &value) == nil {
+		*p = NewExplicitPriority(value)
+		return nil
+	}
+
+	var fields priorityFields
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+	*p = Priority{Type: fields.Type, Value: fields.Value}
+	return nil
 }
 
 type PriorityType int
@@ -172,6 +333,33 @@ func (p Priority) TakesPrecedenceOverOrEqual(other Priority) bool {
 	return false
 }
 
+// ExplainPrecedence describes how priority a compares with priority b, for
+// debug output, such as "explicit(5) beats relative(10) because explicit
+// always wins". It agrees with TakesPrecedenceOver: a beats b exactly when
+// a.TakesPrecedenceOver(b), and a tie is left to file order.
+func ExplainPrecedence(a, b Priority) string {
+	var verb, reason string
+	switch {
+	case a.Type == PriorityExplicit && b.Type != PriorityExplicit:
+		verb, reason = "beats", "explicit always wins"
+	case b.Type == PriorityExplicit && a.Type != PriorityExplicit:
+		verb, reason = "loses to", "explicit always wins"
+	case a.Type != b.Type && a.Type == PriorityRelative:
+		verb, reason = "beats", "any relative priority beats none"
+	case a.Type != b.Type:
+		verb, reason = "loses to", "any relative priority beats none"
+	case a.Value > b.Value:
+		verb, reason = "beats", "the higher value wins"
+	case a.Value < b.Value:
+		verb, reason = "loses to", "the higher value wins"
+	case a.Type == PriorityNone:
+		verb, reason = "ties with", "neither has a priority"
+	default:
+		verb, reason = "ties with", "the values are equal"
+	}
+	return fmt.Sprintf("%s %s %s because %s", a, verb, b, reason)
+}
+
 // DetectFormat determines file format from extension
 func DetectFormat(filename string) (FileFormat, error) {
 	lower := strings.ToLower(filename)
@@ -182,30 +370,88 @@ func DetectFormat(filename string) (FileFormat, error) {
 		return FormatYAML, nil
 	case strings.HasSuffix(lower, ".md") || strings.HasSuffix(lower, ".markdown"):
 		return FormatMarkdown, nil
+	case strings.HasSuffix(lower, ".json"):
+		return FormatJSON, nil
 	default:
-		return FormatTOML, fmt.Errorf("unsupported file format for %s", filename)
+		return FormatTOML, &ConfigError{Kind: KindUnsupportedFormat, Filename: filename}
 	}
 }
 
 // ParseConfig parses configuration data based on format
 func ParseConfig(data []byte, format FileFormat) (*Config, error) {
+	return ParseConfigReader(bytes.NewReader(data), format)
+}
+
+// ParseConfigMulti parses configuration data that may hold several configs.
+// A JSON document whose top level is an array yields one config per element,
+// in array order; every other document yields a single config.
+func ParseConfigMulti(data []byte, format FileFormat) ([]*Config, error) {
+	data = StripBOM(data)
+	if format != FormatJSON || !isJSONArray(data) {
+		config, err := ParseConfig(data, format)
+		if err != nil {
+			return nil, err
+		}
+		return []*Config{config}, nil
+	}
+
+	var elements []json.RawMessage
+	err := json.Unmarshal(data, &elements)
+	if err != nil {
+		return nil, fmt.Errorf("JSON parse error: %w", err)
+	}
+
+	configs := make([]*Config, 0, len(elements))
+	for i, element := range elements {
+		config, err := ParseConfig(element, FormatJSON)
+		if err != nil {
+			return nil, fmt.Errorf("config %d: %w", i, err)
+		}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+// isJSONArray reports whether a JSON document's top level is an array
+func isJSONArray(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// ParseConfigReader parses configuration data read from r based on format,
+// ignoring a leading UTF-8 byte order mark.
+// TOML, YAML, and JSON are decoded as a stream; Markdown is read in full because
+// frontmatter detection needs the whole document.
+func ParseConfigReader(r io.Reader, format FileFormat) (*Config, error) {
 	var config Config
+	r = stripBOMReader(r)
 
 	switch format {
 	case FormatTOML:
-		err := toml.Unmarshal(data, &config)
+		_, err := toml.NewDecoder(r).Decode(&config)
 		if err != nil {
 			return nil, fmt.Errorf("TOML parse error: %w", err)
 		}
 
 	case FormatYAML:
-		err := yaml.Unmarshal(data, &config)
-		if err != nil {
+		err := yaml.NewDecoder(r).Decode(&config)
+		// An empty document decodes to io.EOF, which is an empty config
+		if err != nil && !errors.Is(err, io.EOF) {
 			return nil, fmt.Errorf("YAML parse error: %w", err)
 		}
 
+	case FormatJSON:
+		err := json.NewDecoder(r).Decode(&config)
+		// An empty document is an empty config
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("JSON parse error: %w", err)
+		}
+
 	case FormatMarkdown:
-		var err error
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("Markdown read error: %w", err)
+		}
 		config, err = parseMarkdown(data)
 		if err != nil {
 			return nil, fmt.Errorf("Markdown parse error: %w", err)
@@ -215,7 +461,12 @@ func ParseConfig(data []byte, format FileFormat) (*Config, error) {
 		return nil, fmt.Errorf("unsupported format: %v", format)
 	}
 
-	// Initialize maps if nil
+	initConfig(&config, format)
+	return &config, nil
+}
+
+// initConfig gives a freshly parsed config non-nil maps and records its format
+func initConfig(config *Config, format FileFormat) {
 	if config.Sections == nil {
 		config.Sections = make(map[string]Section)
 	}
@@ -227,7 +478,6 @@ func ParseConfig(data []byte, format FileFormat) (*Config, error) {
 	}
 
 	config.SourceFormat = format
-	return &config, nil
 }
 
 // parseMarkdown handles markdown files with frontmatter and parses headers/lists
@@ -235,57 +485,91 @@ func parseMarkdown(data []byte) (Config, error) {
 	var config Config
 	content := string(data)
 
+	// A blank document has no content, not an empty untitled section
+	if strings.TrimSpace(content) == "" {
+		return config, nil
+	}
+
 	// Check for frontmatter
-	if strings.HasPrefix(content, "---") {
-		parts := strings.SplitN(content, "---", 3)
-		if len(parts) >= 3 {
-			// Parse frontmatter as YAML into metadata
-			frontmatter := strings.TrimSpace(parts[1])
-			var metadata map[string]interface{}
-			err := yaml.Unmarshal([]byte(frontmatter), &metadata)
-			if err != nil {
-				return config, fmt.Errorf("failed to parse frontmatter: %w", err)
-			}
+	frontmatter, body, delimiter := splitFrontmatter(content)
+	if delimiter != "" {
+		// Parse frontmatter into metadata: "+++" blocks are TOML, "---"
+		// blocks are YAML
+		var metadata map[string]interface{}
+		var err error
+		if delimiter == "+++" {
+			_, err = toml.Decode(frontmatter, &metadata)
+			normalizeTOMLInts(metadata)
+		} else {
+			err = yaml.Unmarshal([]byte(strings.TrimSpace(frontmatter)), &metadata)
+		}
+		if err != nil {
+			return config, fmt.Errorf("failed to parse frontmatter: %w", err)
+		}
 
-			// Extract metadata fields
-			if title, ok := metadata["title"].(string); ok {
-				config.Metadata.Title = title
-			}
-			if description, ok := metadata["description"].(string); ok {
-				config.Metadata.Description = description
-			}
-			if version, ok := metadata["version"].(string); ok {
-				config.Metadata.Version = version
+		// Extract metadata fields
+		if title, ok := metadata["title"].(string); ok {
+			config.Metadata.Title = title
+		}
+		if description, ok := metadata["description"].(string); ok {
+			config.Metadata.Description = description
+		}
+		if version, ok := metadata["version"].(string); ok {
+			config.Metadata.Version = version
+		}
+		if language, ok := metadata["language"].(string); ok {
+			config.Metadata.Language = language
+		}
+		if extends, ok := metadata["extends"].(string); ok {
+			config.Metadata.Extends = extends
+		}
+		if offset, ok := metadata["heading_offset"].(int); ok {
+			config.Metadata.HeadingOffset = offset
+		}
+
+		// Keep every other field as an extra
+		for key, value := range metadata {
+			switch key {
+			case "title", "description", "version", "language", "extends", "priority", "heading_offset":
+				continue
 			}
-			if language, ok := metadata["language"].(string); ok {
-				config.Metadata.Language = language
+			if config.Metadata.Extra == nil {
+				config.Metadata.Extra = make(map[string]interface{})
 			}
+			config.Metadata.Extra[key] = value
+		}
 
-			// Parse priority if present
-			if priorityMap, ok := metadata["priority"].(map[string]interface{}); ok {
-				if priorityType, typeOk := priorityMap["type"].(string); typeOk {
-					if priorityValue, valueOk := priorityMap["value"].(int); valueOk {
-						config.Metadata.Priority.Value = priorityValue
-						switch strings.ToLower(priorityType) {
-						case "explicit":
-							config.Metadata.Priority.Type = PriorityExplicit
-						case "relative":
-							config.Metadata.Priority.Type = PriorityRelative
-						}
+		// Parse priority if present, either as a table or as a tier name or
+		// explicit value
+		switch priority := metadata["priority"].(type) {
+		case string:
+			config.Metadata.Priority = parsePriorityName(priority)
+		case int:
+			config.Metadata.Priority = NewExplicitPriority(priority)
+		}
+		if priorityMap, ok := metadata["priority"].(map[string]interface{}); ok {
+			if priorityType, typeOk := priorityMap["type"].(string); typeOk {
+				if priorityValue, valueOk := priorityMap["value"].(int); valueOk {
+					config.Metadata.Priority.Value = priorityValue
+					switch strings.ToLower(priorityType) {
+					case "explicit":
+						config.Metadata.Priority.Type = PriorityExplicit
+					case "relative":
+						config.Metadata.Priority.Type = PriorityRelative
 					}
 				}
 			}
+		}
 
-			// For markdown files, treat the entire content as a single section
-			markdownContent := strings.TrimSpace(parts[2])
-			if markdownContent != "" {
-				if config.Sections == nil {
-					config.Sections = make(map[string]Section)
-				}
-				config.Sections["content"] = Section{
-					Order:   1,
-					Content: markdownContent,
-				}
+		// For markdown files, treat the entire content as a single section
+		markdownContent := strings.TrimSpace(body)
+		if markdownContent != "" {
+			if config.Sections == nil {
+				config.Sections = make(map[string]Section)
+			}
+			config.Sections["content"] = Section{
+				Order:   1,
+				Content: markdownContent,
 			}
 		}
 	} else {
@@ -306,6 +590,56 @@ func parseMarkdown(data []byte) (Config, error) {
 	return config, nil
 }
 
+// splitFrontmatter separates a leading frontmatter block from the rest of a
+// markdown document, returning the delimiter used. Frontmatter must open with
+// a "---" (YAML) or "+++" (TOML) line and close with the same delimiter;
+// anything else, including an unterminated block, means the document has no
+// frontmatter and the delimiter is empty.
+func splitFrontmatter(content string) (string, string, string) {
+	firstLine, rest, found := strings.Cut(content, "\n")
+	delimiter := strings.TrimRight(firstLine, " \t\r")
+	if !found || (delimiter != "---" && delimiter != "+++") {
+		return "", "", ""
+	}
+
+	offset := 0
+	for offset <= len(rest) {
+		line, _, _ := strings.Cut(rest[offset:], "\n")
+		if strings.TrimRight(line, " \t\r") == delimiter {
+			end := offset + len(line)
+			if end < len(rest) {
+				end++
+			}
+			return rest[:offset], rest[end:], delimiter
+		}
+		offset += len(line) + 1
+	}
+	return "", "", ""
+}
+
+// normalizeTOMLInts converts the int64 values TOML decodes into int, matching
+// what YAML frontmatter produces, so both share the field extraction
+func normalizeTOMLInts(values map[string]interface{}) {
+	for key, value := range values {
+		switch v := value.(type) {
+		case int64:
+			values[key] = int(v)
+		case map[string]interface{}:
+			normalizeTOMLInts(v)
+		}
+	}
+}
+
+// splitMarkdownBody replaces the content section of a parsed markdown config
+// with the sections parseMarkdownSections finds in it
+func splitMarkdownBody(config *Config) {
+	body, ok := config.Sections["content"]
+	if !ok {
+		return
+	}
+	config.Sections = parseMarkdownSections(body.Content)
+}
+
 // parseMarkdownSections parses markdown content into sections based on headers and lists
 func parseMarkdownSections(content string) map[string]Section {
 	sections := make(map[string]Section)
@@ -313,6 +647,8 @@ func parseMarkdownSections(content string) map[string]Section {
 
 	currentSection := ""
 	currentContent := ""
+	currentHeading := ""
+	orderedList := ""
 	order := 1
 
 	// Regex patterns for different markdown elements
@@ -323,6 +659,11 @@ func parseMarkdownSections(content string) map[string]Section {
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 
+		// Any other line ends the current ordered list
+		if !orderedListRegex.MatchString(line) {
+			orderedList = ""
+		}
+
 		// Check for headers
 		if headerRegex.MatchString(line) {
 			// Save previous section if exists
@@ -330,6 +671,7 @@ func parseMarkdownSections(content string) map[string]Section {
 				sections[currentSection] = Section{
 					Order:   order,
 					Content: strings.TrimSpace(currentContent),
+					Heading: currentHeading,
 				}
 				order++
 			}
@@ -338,8 +680,9 @@ func parseMarkdownSections(content string) map[string]Section {
 			matches := headerRegex.FindStringSubmatch(line)
 			level := len(matches[1])
 			title := matches[2]
-			currentSection = fmt.Sprintf("header_%d_%s", level, sanitizeName(title))
+			currentSection = fmt.Sprintf("header_%d_%s", level, SanitizeName(title))
 			currentContent = line
+			currentHeading = line
 			continue
 		}
 
@@ -350,6 +693,7 @@ func parseMarkdownSections(content string) map[string]Section {
 				sections[currentSection] = Section{
 					Order:   order,
 					Content: strings.TrimSpace(currentContent),
+					Heading: currentHeading,
 				}
 				order++
 			}
@@ -363,6 +707,7 @@ func parseMarkdownSections(content string) map[string]Section {
 			order++
 			currentSection = ""
 			currentContent = ""
+			currentHeading = ""
 			continue
 		}
 
@@ -373,21 +718,30 @@ func parseMarkdownSections(content string) map[string]Section {
 				sections[currentSection] = Section{
 					Order:   order,
 					Content: strings.TrimSpace(currentContent),
+					Heading: currentHeading,
 				}
 				order++
 			}
 
-			// Create ordered list section
-			matches := orderedListRegex.FindStringSubmatch(line)
-			listNum := matches[1]
-			sectionName := fmt.Sprintf("ordered_list_%s", listNum)
-			sections[sectionName] = Section{
+			// Consecutive ordered-list lines belong to the same list
+			if orderedList != "" {
+				list := sections[orderedList]
+				list.Content += "\n" + line
+				sections[orderedList] = list
+				continue
+			}
+
+			// Create ordered list section, keyed by appearance so lists
+			// that restart their numbering do not collide
+			orderedList = fmt.Sprintf("ordered_list_%d", order)
+			sections[orderedList] = Section{
 				Order:   order,
 				Content: line,
 			}
 			order++
 			currentSection = ""
 			currentContent = ""
+			currentHeading = ""
 			continue
 		}
 
@@ -408,17 +762,19 @@ func parseMarkdownSections(content string) map[string]Section {
 		sections[currentSection] = Section{
 			Order:   order,
 			Content: strings.TrimSpace(currentContent),
+			Heading: currentHeading,
 		}
 	}
 
 	return sections
 }
 
-// sanitizeName converts a title to a valid section name
-func sanitizeName(title string) string {
+// SanitizeName converts a title to a valid section name: lowercase letters
+// and digits, with every other run of characters replaced by an underscore
+func SanitizeName(title string) string {
 	// Convert to lowercase and replace spaces/special chars with underscores
 	name := strings.ToLower(title)
 	name = regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(name, "_")
 	name = strings.Trim(name, "_")
 	return name
-}
\ No newline at end of file
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -121,6 +123,39 @@ func TestPriority_Comparison(t *testing.T) {
 	}
 }
 
+func TestExplainPrecedence(t *testing.T) {
+	tests := []struct {
+		a, b     Priority
+		expected string
+	}{
+		{NewExplicitPriority(5), NewRelativePriority(10), "explicit(5) beats relative(10) because explicit always wins"},
+		{NewExplicitPriority(1), Priority{}, "explicit(1) beats none because explicit always wins"},
+		{NewRelativePriority(10), NewExplicitPriority(5), "relative(10) loses to explicit(5) because explicit always wins"},
+		{Priority{}, NewExplicitPriority(1), "none loses to explicit(1) because explicit always wins"},
+		{NewRelativePriority(1), Priority{}, "relative(1) beats none because any relative priority beats none"},
+		{Priority{}, NewRelativePriority(1), "none loses to relative(1) because any relative priority beats none"},
+		{NewExplicitPriority(10), NewExplicitPriority(5), "explicit(10) beats explicit(5) because the higher value wins"},
+		{NewExplicitPriority(5), NewExplicitPriority(10), "explicit(5) loses to explicit(10) because the higher value wins"},
+		{NewExplicitPriority(5), NewExplicitPriority(5), "explicit(5) ties with explicit(5) because the values are equal"},
+		{NewRelativePriority(10), NewRelativePriority(5), "relative(10) beats relative(5) because the higher value wins"},
+		{NewRelativePriority(5), NewRelativePriority(10), "relative(5) loses to relative(10) because the higher value wins"},
+		{NewRelativePriority(5), NewRelativePriority(5), "relative(5) ties with relative(5) because the values are equal"},
+		{NewRelativePriority(-1), Priority{}, "relative(-1) beats none because any relative priority beats none"},
+		{Priority{}, Priority{}, "none ties with none because neither has a priority"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			explanation := ExplainPrecedence(tt.a, tt.b)
+			assert.Equal(t, tt.expected, explanation)
+
+			// The explanation must agree with the comparison it describes
+			assert.Equal(t, tt.a.TakesPrecedenceOver(tt.b), strings.HasPrefix(explanation, tt.a.String()+" beats "))
+			assert.Equal(t, tt.b.TakesPrecedenceOver(tt.a), strings.HasPrefix(explanation, tt.a.String()+" loses to "))
+		})
+	}
+}
+
 func TestDetectFormat(t *testing.T) {
 	tests := []struct {
 		filename string
@@ -163,4 +198,278 @@ func TestPriority_StringRepresentation(t *testing.T) {
 			assert.Equal(t, tt.expected, tt.priority.String())
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestParseConfigReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		format  FileFormat
+		title   string
+	}{
+		{
+			name:    "TOML",
+			content: "[metadata]\ntitle = \"TOML Reader\"\n",
+			format:  FormatTOML,
+			title:   "TOML Reader",
+		},
+		{
+			name:    "YAML",
+			content: "metadata:\n  title: \"YAML Reader\"\n",
+			format:  FormatYAML,
+			title:   "YAML Reader",
+		},
+		{
+			name:    "empty YAML",
+			content: "",
+			format:  FormatYAML,
+			title:   "",
+		},
+		{
+			name:    "Markdown",
+			content: "---\ntitle: \"Markdown Reader\"\n---\n\n# Body\n",
+			format:  FormatMarkdown,
+			title:   "Markdown Reader",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseConfigReader(strings.NewReader(tt.content), tt.format)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.title, config.Metadata.Title)
+			assert.Equal(t, tt.format, config.SourceFormat)
+			assert.NotNil(t, config.Sections)
+			assert.NotNil(t, config.MergePoints)
+			assert.NotNil(t, config.MergeTargets)
+		})
+	}
+}
+
+func TestParseConfigReader_Error(t *testing.T) {
+	_, err := ParseConfigReader(strings.NewReader("metadata: [unclosed"), FormatYAML)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "YAML parse error")
+}
+
+func TestConfig_MarshalJSON(t *testing.T) {
+	config := &Config{
+		Metadata: Metadata{
+			Title:    "JSON Test",
+			Priority: NewExplicitPriority(10),
+		},
+		Sections: map[string]Section{
+			"header": {Order: 1, Content: "# Header", Priority: NewRelativePriority(5)},
+		},
+		SourceFormat: FormatTOML,
+	}
+
+	data, err := json.Marshal(config)
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	metadata := decoded["metadata"].(map[string]interface{})
+	assert.Equal(t, "JSON Test", metadata["title"])
+	assert.Equal(t, map[string]interface{}{"type": "explicit", "value": float64(10)}, metadata["priority"])
+
+	header := decoded["sections"].(map[string]interface{})["header"].(map[string]interface{})
+	assert.Equal(t, "# Header", header["content"])
+	assert.Equal(t, map[string]interface{}{"type": "relative", "value": float64(5)}, header["priority"])
+
+	assert.NotContains(t, decoded, "source_file")
+	assert.NotContains(t, decoded, "SourceFormat")
+}
+
+func TestParseMarkdownSections_OrderedLists(t *testing.T) {
+	content := "# Setup\n" +
+		"1. Install Go\n" +
+		"2. Clone the repo\n" +
+		"\n" +
+		"# Release\n" +
+		"1. Tag the commit\n" +
+		"2. Push the tag\n" +
+		"3. Publish notes"
+
+	sections := parseMarkdownSections(content)
+
+	assert.Equal(t, Section{Order: 2, Content: "1. Install Go\n2. Clone the repo"}, sections["ordered_list_2"])
+	assert.Equal(t, Section{Order: 4, Content: "1. Tag the commit\n2. Push the tag\n3. Publish notes"}, sections["ordered_list_4"])
+	assert.Equal(t, "# Setup", sections["header_1_setup"].Content)
+	assert.Equal(t, "# Release", sections["header_1_release"].Content)
+	assert.Len(t, sections, 4)
+}
+
+func TestParseMarkdownSections_Heading(t *testing.T) {
+	sections := parseMarkdownSections("Intro text\n\n## STRICT REQUIREMENTS!! 🚨\nNo exceptions.\n\n- a list")
+
+	section := sections["header_2_strict_requirements"]
+	assert.Equal(t, "## STRICT REQUIREMENTS!! 🚨", section.Heading)
+	assert.Equal(t, "## STRICT REQUIREMENTS!! 🚨\nNo exceptions.", section.Content)
+	assert.Empty(t, sections["content"].Heading, "text before any heading has none")
+	assert.Empty(t, sections["list_3"].Heading)
+}
+
+func TestParseMarkdown_FrontmatterEdgeCases(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantTitle   string
+		wantContent string
+	}{
+		{
+			name:        "lone marker",
+			input:       "---",
+			wantTitle:   "Untitled Document",
+			wantContent: "---",
+		},
+		{
+			name:        "unterminated frontmatter",
+			input:       "---\ntitle: Open\n# Body",
+			wantTitle:   "Untitled Document",
+			wantContent: "---\ntitle: Open\n# Body",
+		},
+		{
+			name:        "markers inside values",
+			input:       "---\ntitle: a---b\n---\n# Body --- text",
+			wantTitle:   "a---b",
+			wantContent: "# Body --- text",
+		},
+		{
+			name:        "empty frontmatter",
+			input:       "---\n---\n# Body",
+			wantContent: "# Body",
+		},
+		{
+			name:        "frontmatter only",
+			input:       "---\ntitle: Only\n---",
+			wantTitle:   "Only",
+			wantContent: "",
+		},
+		{
+			name:        "CRLF line endings",
+			input:       "---\r\ntitle: Windows\r\n---\r\n# Body",
+			wantTitle:   "Windows",
+			wantContent: "# Body",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseConfig([]byte(tt.input), FormatMarkdown)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTitle, config.Metadata.Title)
+			assert.Equal(t, tt.wantContent, config.Sections["content"].Content)
+		})
+	}
+}
+
+func FuzzParseMarkdown(f *testing.F) {
+	f.Add([]byte("---\ntitle: Test\n---\n# Body"))
+	f.Add([]byte("---"))
+	f.Add([]byte("---\n---"))
+	f.Add([]byte("+++\ntitle = \"x\"\n+++\n# Body"))
+	f.Add([]byte("---\ntitle: x\n---\n---\n---"))
+	f.Add([]byte("---\npriority:\n  type: explicit\n  value: 10\n---\n"))
+	f.Add([]byte("# " + strings.Repeat("heading ", 200)))
+	f.Add([]byte("1. one\n1. one\n- item\n## h"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		config, err := parseMarkdown(data)
+		if err != nil {
+			return
+		}
+		for _, section := range config.Sections {
+			assert.Equal(t, strings.TrimSpace(section.Content), section.Content)
+		}
+		parseMarkdownSections(string(data))
+	})
+}
+
+func TestParseMarkdown_TOMLFrontmatter(t *testing.T) {
+	mdContent := `+++
+title = "TOML Frontmatter"
+language = "go"
+
+[priority]
+type = "explicit"
+value = 10
++++
+
+# Body
+
+---
+A thematic break, not frontmatter.
+`
+
+	config, err := ParseConfig([]byte(mdContent), FormatMarkdown)
+	require.NoError(t, err)
+
+	assert.Equal(t, "TOML Frontmatter", config.Metadata.Title)
+	assert.Equal(t, "go", config.Metadata.Language)
+	assert.Equal(t, NewExplicitPriority(10), config.Metadata.Priority)
+	assert.Equal(t, "# Body\n\n---\nA thematic break, not frontmatter.", config.Sections["content"].Content)
+
+	config, err = ParseConfig([]byte("+++\npriority = \"team\"\n+++\n# Body"), FormatMarkdown)
+	require.NoError(t, err)
+	assert.Equal(t, Priority{Type: PriorityExplicit, Tier: "team"}, config.Metadata.Priority)
+
+	_, err = ParseConfig([]byte("+++\ntitle = \n+++\n# Body"), FormatMarkdown)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse frontmatter")
+
+	config, err = ParseConfig([]byte("+++\ntitle = \"Mixed\"\n---\n# Body"), FormatMarkdown)
+	require.NoError(t, err)
+	assert.Equal(t, "Untitled Document", config.Metadata.Title, "delimiters must match")
+}
+
+func TestParseMarkdown_FrontmatterExtra(t *testing.T) {
+	config, err := ParseConfig([]byte("---\ntitle: Guide\nextends: common.md\ntags: [go, style]\nowner: team\n---\n# Body"), FormatMarkdown)
+	require.NoError(t, err)
+	assert.Equal(t, "Guide", config.Metadata.Title)
+	assert.Equal(t, "common.md", config.Metadata.Extends)
+	assert.Equal(t, map[string]interface{}{
+		"tags":  []interface{}{"go", "style"},
+		"owner": "team",
+	}, config.Metadata.Extra)
+
+	config, err = ParseConfig([]byte("+++\ntitle = \"Guide\"\nreviews = 2\n+++\n# Body"), FormatMarkdown)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"reviews": 2}, config.Metadata.Extra)
+
+	config, err = ParseConfig([]byte("---\ntitle: Guide\n---\n# Body"), FormatMarkdown)
+	require.NoError(t, err)
+	assert.Nil(t, config.Metadata.Extra)
+}
+
+func TestConfig_IsEmpty(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		format  FileFormat
+		want    bool
+	}{
+		{name: "zero-byte TOML", content: "", format: FormatTOML, want: true},
+		{name: "comment-only TOML", content: "# nothing yet\n", format: FormatTOML, want: true},
+		{name: "whitespace YAML", content: "\n  \n", format: FormatYAML, want: true},
+		{name: "empty JSON object", content: "{}", format: FormatJSON, want: true},
+		{name: "whitespace markdown", content: " \n\t\n", format: FormatMarkdown, want: true},
+		{name: "metadata priority only", content: "[metadata]\npriority = 5\n", format: FormatTOML, want: true},
+		{name: "title", content: "[metadata]\ntitle = \"T\"\n", format: FormatTOML, want: false},
+		{name: "section", content: "sections:\n  intro:\n    content: Hi\n", format: FormatYAML, want: false},
+		{name: "tiers", content: "[priority_tiers]\nbase = 0\n", format: FormatTOML, want: false},
+		{name: "markdown body", content: "# Body\n", format: FormatMarkdown, want: false},
+		{name: "frontmatter only", content: "---\ntitle: Only\n---\n", format: FormatMarkdown, want: false},
+		{name: "extra metadata", content: "[metadata.extra]\nowner = \"team\"\n", format: FormatTOML, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseConfig([]byte(tt.content), tt.format)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, config.IsEmpty())
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package config
+
+import "golang.org/x/text/unicode/norm"
+
+// UnicodeForm is a Unicode normalization form applied to section content
+type UnicodeForm string
+
+const (
+	// UnicodeNone leaves content as written
+	UnicodeNone UnicodeForm = ""
+
+	// UnicodeNFC composes characters, the form most editors write
+	UnicodeNFC UnicodeForm = "nfc"
+
+	// UnicodeNFD decomposes characters, as macOS file names do
+	UnicodeNFD UnicodeForm = "nfd"
+
+	// UnicodeNFKC composes characters after replacing compatibility
+	// characters, such as ligatures, with their plain equivalents
+	UnicodeNFKC UnicodeForm = "nfkc"
+
+	// UnicodeNFKD decomposes characters after replacing compatibility
+	// characters
+	UnicodeNFKD UnicodeForm = "nfkd"
+)
+
+// IsValid checks if a form string is valid
+func (f UnicodeForm) IsValid() bool {
+	_, ok := unicodeForms[f]
+	return ok || f == UnicodeNone
+}
+
+// unicodeForms maps each form to its normalizer
+var unicodeForms = map[UnicodeForm]norm.Form{
+	UnicodeNFC:  norm.NFC,
+	UnicodeNFD:  norm.NFD,
+	UnicodeNFKC: norm.NFKC,
+	UnicodeNFKD: norm.NFKD,
+}
+
+// NormalizeUnicode rewrites the content and heading of every section, the
+// default of every merge point, and the content of every merge target of
+// cfg in form, so text that looks the same compares equal whichever editor
+// wrote it. UnicodeNone and unknown forms leave cfg unchanged.
+func NormalizeUnicode(cfg *Config, form UnicodeForm) {
+	normalizer, ok := unicodeForms[form]
+	if !ok {
+		return
+	}
+	for key, section := range cfg.Sections {
+		section.Content = normalizer.String(section.Content)
+		section.Heading = normalizer.String(section.Heading)
+		cfg.Sections[key] = section
+	}
+	for key, point := range cfg.MergePoints {
+		point.Default = normalizer.String(point.Default)
+		cfg.MergePoints[key] = point
+	}
+	for key, target := range cfg.MergeTargets {
+		target.Content = normalizer.String(target.Content)
+		cfg.MergeTargets[key] = target
+	}
+}
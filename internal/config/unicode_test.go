@@ -0,0 +1,50 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnicodeForm_IsValid(t *testing.T) {
+	for _, form := range []UnicodeForm{UnicodeNone, UnicodeNFC, UnicodeNFD, UnicodeNFKC, UnicodeNFKD} {
+		assert.True(t, form.IsValid(), form)
+	}
+	assert.False(t, UnicodeForm("NFC").IsValid())
+	assert.False(t, UnicodeForm("utf8").IsValid())
+}
+
+func TestNormalizeUnicode(t *testing.T) {
+	// "é" written precomposed and as "e" plus a combining acute accent
+	composed := "Caf\u00e9"
+	decomposed := "Cafe\u0301"
+	build := func() *Config {
+		return &Config{
+			Sections:     map[string]Section{"intro": {Content: "## " + decomposed, Heading: "## " + decomposed}},
+			MergePoints:  map[string]MergePoint{"menu": {Default: decomposed}},
+			MergeTargets: map[string]MergeTarget{"menu": {Content: "\ufb01le " + decomposed}},
+		}
+	}
+
+	tests := []struct {
+		form    UnicodeForm
+		section string
+		target  string
+	}{
+		{form: UnicodeNone, section: "## " + decomposed, target: "\ufb01le " + decomposed},
+		{form: UnicodeNFC, section: "## " + composed, target: "\ufb01le " + composed},
+		{form: UnicodeNFD, section: "## " + decomposed, target: "\ufb01le " + decomposed},
+		{form: UnicodeNFKC, section: "## " + composed, target: "file " + composed},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.form), func(t *testing.T) {
+			cfg := build()
+			NormalizeUnicode(cfg, tt.form)
+			assert.Equal(t, tt.section, cfg.Sections["intro"].Content)
+			assert.Equal(t, tt.section, cfg.Sections["intro"].Heading)
+			assert.Equal(t, tt.section[3:], cfg.MergePoints["menu"].Default)
+			assert.Equal(t, tt.target, cfg.MergeTargets["menu"].Content)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Fetcher returns the body of the document at a URL
+type Fetcher func(url string) ([]byte, error)
+
+// IsURL reports whether name is an http or https URL rather than a file
+// path
+func IsURL(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// LoadConfigMultiURL loads the configs at rawURL, read with fetch, like
+// LoadConfigMulti does from disk. The format is detected from the
+// extension of the URL's path, and content files and includes are fetched
+// relative to the URL.
+func LoadConfigMultiURL(rawURL string, fetch Fetcher, opts LoadOptions) ([]*Config, error) {
+	return loadConfigMulti(fileSource{fetch: fetch}, rawURL, opts)
+}
+
+// urlDir returns a URL with its last path segment, query, and fragment
+// removed, so relative references resolve against its directory
+func urlDir(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.RawQuery = ""
+	u.Fragment = ""
+	u.Path = u.Path[:strings.LastIndex(u.Path, "/")+1]
+	u.RawPath = ""
+	return u.String()
+}
+
+// resolveURL resolves ref against base, keeping ref as it is if either
+// doesn't parse
+func resolveURL(base, ref string) string {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return baseURL.ResolveReference(refURL).String()
+}
+
+// urlPath returns the path of a URL, or the URL itself if it doesn't parse
+func urlPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Path
+}
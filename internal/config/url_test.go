@@ -0,0 +1,77 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsURL(t *testing.T) {
+	assert.True(t, IsURL("https://example.com/go.toml"))
+	assert.True(t, IsURL("HTTP://example.com/go.toml"))
+	assert.False(t, IsURL("go.toml"))
+	assert.False(t, IsURL("/tmp/https/go.toml"))
+	assert.False(t, IsURL("ftp://example.com/go.toml"))
+}
+
+func TestLoadConfigMultiURL(t *testing.T) {
+	documents := map[string]string{
+		"https://example.com/bases/go.toml?ref=main": `
+[metadata]
+title = "Go"
+
+[sections.testing]
+content_file = "snippets/testing.md"
+
+[sections.style]
+content = "Use gofmt."
+includes = ["/shared/lint.md"]
+`,
+		"https://example.com/bases/snippets/testing.md": "Run go test ./...\n",
+		"https://example.com/shared/lint.md":            "Run golangci-lint.",
+	}
+	var fetched []string
+	fetch := func(url string) ([]byte, error) {
+		fetched = append(fetched, url)
+		body, ok := documents[url]
+		if !ok {
+			return nil, errors.New("server returned 404 Not Found")
+		}
+		return []byte(body), nil
+	}
+
+	configs, err := LoadConfigMultiURL("https://example.com/bases/go.toml?ref=main", fetch, LoadOptions{})
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+	cfg := configs[0]
+	assert.Equal(t, "Go", cfg.Metadata.Title)
+	assert.Equal(t, FormatTOML, cfg.SourceFormat, "the format comes from the path, not the query")
+	assert.Equal(t, "Run go test ./...", cfg.Sections["testing"].Content)
+	assert.Equal(t, "Use gofmt.\nRun golangci-lint.", cfg.Sections["style"].Content)
+	assert.Equal(t, "https://example.com/bases/go.toml?ref=main", cfg.SourceFile)
+	assert.Len(t, fetched, 3)
+
+	_, err = LoadConfigMultiURL("https://example.com/missing.md", fetch, LoadOptions{})
+	var configErr *ConfigError
+	require.ErrorAs(t, err, &configErr)
+	assert.Equal(t, KindNotFound, configErr.Kind)
+	assert.Contains(t, err.Error(), "404")
+}
+
+func TestURLDir(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://example.com/a/b/go.toml?ref=main#x", want: "https://example.com/a/b/"},
+		{url: "https://example.com/go.toml", want: "https://example.com/"},
+		{url: "https://example.com", want: "https://example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.want, urlDir(tt.url))
+		})
+	}
+}
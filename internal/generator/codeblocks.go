@@ -0,0 +1,155 @@
+package generator
+
+import (
+	"go/format"
+	"strings"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// FormatGoCodeBlocks runs the contents of every ```go fenced block in
+// markdown through gofmt. Blocks in other languages, and Go blocks that fail
+// to parse, are left untouched.
+func FormatGoCodeBlocks(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	var out []string
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		indent, fence, lang, ok := config.ParseFence(line)
+		if !ok {
+			out = append(out, line)
+			continue
+		}
+
+		// Find the closing fence; an unclosed block runs to the end of input
+		// and is left as is
+		end := -1
+		for j := i + 1; j < len(lines); j++ {
+			if config.IsClosingFence(lines[j], fence) {
+				end = j
+				break
+			}
+		}
+		if end < 0 {
+			out = append(out, lines[i:]...)
+			break
+		}
+
+		body := lines[i+1 : end]
+		if lang == "go" {
+			body = formatGoBody(body, indent)
+		}
+		out = append(out, line)
+		out = append(out, body...)
+		out = append(out, lines[end])
+		i = end
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// formatGoBody formats the lines of a Go block, keeping the original lines
+// if they do not parse
+func formatGoBody(body []string, indent string) []string {
+	src := make([]string, len(body))
+	for i, line := range body {
+		src[i] = strings.TrimPrefix(line, indent)
+	}
+
+	formatted, err := format.Source([]byte(strings.Join(src, "\n")))
+	if err != nil {
+		return body
+	}
+
+	result := strings.Split(strings.TrimRight(string(formatted), "\n"), "\n")
+	for i, line := range result {
+		if line != "" {
+			result[i] = indent + line
+		}
+	}
+	return result
+}
+
+// DefaultFenceAliases returns the code fence languages NormalizeFenceLanguages
+// rewrites by default, mapping each lowercase alias to its canonical name
+func DefaultFenceAliases() map[string]string {
+	return map[string]string{
+		"golang":  "go",
+		"py":      "python",
+		"py3":     "python",
+		"python3": "python",
+		"js":      "javascript",
+		"ts":      "typescript",
+		"rb":      "ruby",
+		"rs":      "rust",
+		"kt":      "kotlin",
+		"cs":      "csharp",
+		"c#":      "csharp",
+		"c++":     "cpp",
+		"cxx":     "cpp",
+		"yml":     "yaml",
+		"md":      "markdown",
+		"ps1":     "powershell",
+		"pwsh":    "powershell",
+	}
+}
+
+// NormalizeFenceLanguages rewrites the language of every fenced code block
+// in markdown that aliases names, looked up in lowercase, to its canonical
+// name, so ```golang becomes ```go. The rest of the info string, the code
+// itself, and all text outside the opening fence lines are left untouched.
+func NormalizeFenceLanguages(markdown string, aliases map[string]string) string {
+	if len(aliases) == 0 {
+		return markdown
+	}
+
+	lines := strings.Split(markdown, "\n")
+	fence := ""
+	for i, line := range lines {
+		if fence != "" {
+			if config.IsClosingFence(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+
+		indent, marker, lang, ok := config.ParseFence(line)
+		if !ok {
+			continue
+		}
+		fence = marker
+		canonical, found := aliases[lang]
+		if lang == "" || !found || canonical == "" {
+			continue
+		}
+
+		start := len(indent) + len(marker)
+		info := line[start:]
+		pad := len(info) - len(strings.TrimLeft(info, " \t"))
+		word := strings.Fields(info)[0]
+		lines[i] = line[:start+pad] + canonical + info[pad+len(word):]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ApplyFenceAliases rewrites the fence languages in the section content,
+// merge point defaults, and merge target content of cfg, as
+// NormalizeFenceLanguages does
+func ApplyFenceAliases(cfg *config.Config, aliases map[string]string) {
+	if len(aliases) == 0 {
+		return
+	}
+	for key, section := range cfg.Sections {
+		section.Content = NormalizeFenceLanguages(section.Content, aliases)
+		cfg.Sections[key] = section
+	}
+	for key, point := range cfg.MergePoints {
+		point.Default = NormalizeFenceLanguages(point.Default, aliases)
+		cfg.MergePoints[key] = point
+	}
+	for key, target := range cfg.MergeTargets {
+		target.Content = NormalizeFenceLanguages(target.Content, aliases)
+		cfg.MergeTargets[key] = target
+	}
+}
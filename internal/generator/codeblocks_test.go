@@ -0,0 +1,138 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatGoCodeBlocks(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "formats go block",
+			input: "# Example\n\n```go\nfunc main(){\nx:=1\n_ = x\n}\n```\n",
+			want:  "# Example\n\n```go\nfunc main() {\n\tx := 1\n\t_ = x\n}\n```\n",
+		},
+		{
+			name:  "statements without a package clause",
+			input: "```go\nx:=1\n```",
+			want:  "```go\nx := 1\n```",
+		},
+		{
+			name:  "other languages untouched",
+			input: "```python\nx=1\n```",
+			want:  "```python\nx=1\n```",
+		},
+		{
+			name:  "malformed go kept",
+			input: "```go\nfunc main( {\n```",
+			want:  "```go\nfunc main( {\n```",
+		},
+		{
+			name:  "unclosed block kept",
+			input: "```go\nx:=1",
+			want:  "```go\nx:=1",
+		},
+		{
+			name:  "indented block in a list",
+			input: "- step\n  ```go\n  x:=1\n  ```",
+			want:  "- step\n  ```go\n  x := 1\n  ```",
+		},
+		{
+			name:  "tilde fence and longer closing fence",
+			input: "~~~go\nx:=1\n~~~~",
+			want:  "~~~go\nx := 1\n~~~~",
+		},
+		{
+			name:  "no code blocks",
+			input: "Plain text",
+			want:  "Plain text",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatGoCodeBlocks(tt.input))
+		})
+	}
+}
+
+func TestNormalizeFenceLanguages(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "rewrites aliases",
+			input: "```golang\nx := 1\n```\n\n```py\nx = 1\n```",
+			want:  "```go\nx := 1\n```\n\n```python\nx = 1\n```",
+		},
+		{
+			name:  "case insensitive",
+			input: "```Golang\nx := 1\n```",
+			want:  "```go\nx := 1\n```",
+		},
+		{
+			name:  "keeps the rest of the info string",
+			input: "```  golang title=\"main.go\" {1,3}\nx := 1\n```",
+			want:  "```  go title=\"main.go\" {1,3}\nx := 1\n```",
+		},
+		{
+			name:  "prose untouched",
+			input: "Write golang, not py.\n\n`golang` inline",
+			want:  "Write golang, not py.\n\n`golang` inline",
+		},
+		{
+			name:  "fence lines inside a block untouched",
+			input: "````markdown\n```golang\nx := 1\n```\n````",
+			want:  "````markdown\n```golang\nx := 1\n```\n````",
+		},
+		{
+			name:  "canonical and unknown languages untouched",
+			input: "```go\n```\n```brainfuck\n```\n```\n```",
+			want:  "```go\n```\n```brainfuck\n```\n```\n```",
+		},
+		{
+			name:  "indented tilde fence",
+			input: "- step\n  ~~~yml\n  a: 1\n  ~~~",
+			want:  "- step\n  ~~~yaml\n  a: 1\n  ~~~",
+		},
+		{
+			name:  "keeps CRLF line endings",
+			input: "```golang\r\nx := 1\r\n```\r\n",
+			want:  "```go\r\nx := 1\r\n```\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeFenceLanguages(tt.input, DefaultFenceAliases()))
+		})
+	}
+}
+
+func TestNormalizeFenceLanguages_CustomAliases(t *testing.T) {
+	aliases := map[string]string{"sh": "bash", "golang": "golang"}
+	assert.Equal(t, "```bash\n```\n```golang\n```", NormalizeFenceLanguages("```sh\n```\n```golang\n```", aliases))
+	assert.Equal(t, "```golang\n```", NormalizeFenceLanguages("```golang\n```", nil))
+}
+
+func TestApplyFenceAliases(t *testing.T) {
+	cfg := &config.Config{
+		Sections:     map[string]config.Section{"build": {Content: "```golang\nx := 1\n```"}},
+		MergePoints:  map[string]config.MergePoint{"cmd": {Default: "```sh\nmake\n```"}},
+		MergeTargets: map[string]config.MergeTarget{"cmd": {Content: "```yml\na: 1\n```"}},
+	}
+
+	ApplyFenceAliases(cfg, DefaultFenceAliases())
+
+	assert.Equal(t, "```go\nx := 1\n```", cfg.Sections["build"].Content)
+	assert.Equal(t, "```sh\nmake\n```", cfg.MergePoints["cmd"].Default)
+	assert.Equal(t, "```yaml\na: 1\n```", cfg.MergeTargets["cmd"].Content)
+}
@@ -0,0 +1,236 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// configDocument is the TOML and YAML output form of a merged config. Empty
+// fields are left out, so the output reads like a hand-written config and
+// loads back into the same merged config.
+type configDocument struct {
+	Metadata      configMetadata               `toml:"metadata" yaml:"metadata"`
+	Sections      map[string]configSection     `toml:"sections,omitempty" yaml:"sections,omitempty"`
+	MergePoints   map[string]configMergePoint  `toml:"merge_points,omitempty" yaml:"merge_points,omitempty"`
+	MergeTargets  map[string]configMergeTarget `toml:"merge_targets,omitempty" yaml:"merge_targets,omitempty"`
+	PriorityTiers map[string]int               `toml:"priority_tiers,omitempty" yaml:"priority_tiers,omitempty"`
+}
+
+// configMetadata is the output form of config.Metadata
+type configMetadata struct {
+	Title       string           `toml:"title,omitempty" yaml:"title,omitempty"`
+	Description string           `toml:"description,omitempty" yaml:"description,omitempty"`
+	Version     string           `toml:"version,omitempty" yaml:"version,omitempty"`
+	Language    string           `toml:"language,omitempty" yaml:"language,omitempty"`
+	Extends     string           `toml:"extends,omitempty" yaml:"extends,omitempty"`
+	Priority    *config.Priority `toml:"priority,omitempty" yaml:"priority,omitempty"`
+
+	Extra map[string]interface{} `toml:"extra,omitempty" yaml:"extra,omitempty"`
+}
+
+// configSection is the output form of config.Section. Content files,
+// includes, and aliases were already resolved while merging, so they are
+// dropped rather than applied a second time on reload.
+type configSection struct {
+	Order       int              `toml:"order" yaml:"order"`
+	Parent      string           `toml:"parent,omitempty" yaml:"parent,omitempty"`
+	MergeID     string           `toml:"merge_id,omitempty" yaml:"merge_id,omitempty"`
+	Content     string           `toml:"content" yaml:"content"`
+	MergePoints []string         `toml:"merge_points,omitempty" yaml:"merge_points,omitempty"`
+	Priority    *config.Priority `toml:"priority,omitempty" yaml:"priority,omitempty"`
+	Condition   string           `toml:"condition,omitempty" yaml:"condition,omitempty"`
+	Anchor      string           `toml:"anchor,omitempty" yaml:"anchor,omitempty"`
+	Heading     string           `toml:"heading,omitempty" yaml:"heading,omitempty"`
+	Tags        []string         `toml:"tags,omitempty" yaml:"tags,omitempty"`
+	Final       bool             `toml:"final,omitempty" yaml:"final,omitempty"`
+}
+
+// configMergePoint is the output form of config.MergePoint
+type configMergePoint struct {
+	Placeholder string           `toml:"placeholder" yaml:"placeholder"`
+	Default     string           `toml:"default,omitempty" yaml:"default,omitempty"`
+	Priority    *config.Priority `toml:"priority,omitempty" yaml:"priority,omitempty"`
+}
+
+// configMergeTarget is the output form of config.MergeTarget, with its
+// content file already resolved
+type configMergeTarget struct {
+	Strategy string           `toml:"strategy,omitempty" yaml:"strategy,omitempty"`
+	Content  string           `toml:"content" yaml:"content"`
+	Priority *config.Priority `toml:"priority,omitempty" yaml:"priority,omitempty"`
+}
+
+// GenerateConfigTOML renders cfg as a TOML config that can be loaded again.
+// Each section is preceded by a "# merged from FILE" comment naming its
+// source in provenance, which may be nil. Only the stamp fields of opts are
+// used.
+func GenerateConfigTOML(cfg *config.Config, provenance map[string]string, opts Options) (string, error) {
+	doc := newConfigDocument(cfg)
+
+	var buf bytes.Buffer
+	buf.WriteString("# " + configHeader(opts) + "\n\n")
+
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	err := enc.Encode(struct {
+		Metadata configMetadata `toml:"metadata"`
+	}{doc.Metadata})
+	if err != nil {
+		return "", err
+	}
+
+	// Sections are encoded one at a time so each can carry its comment; the
+	// parent table is written once, ahead of the first
+	if len(doc.Sections) > 0 {
+		buf.WriteString("\n[sections]\n")
+	}
+	for _, key := range sortedKeys(cfg.Sections) {
+		var section bytes.Buffer
+		enc := toml.NewEncoder(&section)
+		enc.Indent = ""
+		err = enc.Encode(map[string]map[string]configSection{"sections": {key: doc.Sections[key]}})
+		if err != nil {
+			return "", err
+		}
+
+		buf.WriteString("\n")
+		if source := provenance[key]; source != "" {
+			buf.WriteString("# merged from " + source + "\n")
+		}
+		buf.WriteString(strings.TrimPrefix(section.String(), "[sections]\n"))
+	}
+
+	var rest bytes.Buffer
+	enc = toml.NewEncoder(&rest)
+	enc.Indent = ""
+	err = enc.Encode(struct {
+		MergePoints   map[string]configMergePoint  `toml:"merge_points,omitempty"`
+		MergeTargets  map[string]configMergeTarget `toml:"merge_targets,omitempty"`
+		PriorityTiers map[string]int               `toml:"priority_tiers,omitempty"`
+	}{doc.MergePoints, doc.MergeTargets, doc.PriorityTiers})
+	if err != nil {
+		return "", err
+	}
+	if rest.Len() > 0 {
+		buf.WriteString("\n")
+		buf.Write(rest.Bytes())
+	}
+
+	return strings.TrimRight(buf.String(), "\n") + "\n", nil
+}
+
+// GenerateConfigYAML renders cfg as a YAML config that can be loaded again,
+// with the same comments as GenerateConfigTOML
+func GenerateConfigYAML(cfg *config.Config, provenance map[string]string, opts Options) (string, error) {
+	var root yaml.Node
+	err := root.Encode(newConfigDocument(cfg))
+	if err != nil {
+		return "", err
+	}
+	root.HeadComment = configHeader(opts)
+
+	// Comment each key of the sections mapping with its source
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "sections" {
+			continue
+		}
+		sections := root.Content[i+1]
+		for j := 0; j+1 < len(sections.Content); j += 2 {
+			if source := provenance[sections.Content[j].Value]; source != "" {
+				sections.Content[j].HeadComment = "merged from " + source
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	err = enc.Encode(&root)
+	if err != nil {
+		return "", err
+	}
+	err = enc.Close()
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// newConfigDocument converts cfg to its output form
+func newConfigDocument(cfg *config.Config) configDocument {
+	doc := configDocument{
+		Metadata: configMetadata{
+			Title:       cfg.Metadata.Title,
+			Description: cfg.Metadata.Description,
+			Version:     cfg.Metadata.Version,
+			Language:    cfg.Metadata.Language,
+			Extends:     cfg.Metadata.Extends,
+			Priority:    outputPriority(cfg.Metadata.Priority),
+			Extra:       cfg.Metadata.Extra,
+		},
+		PriorityTiers: cfg.PriorityTiers,
+	}
+
+	if len(cfg.Sections) > 0 {
+		doc.Sections = make(map[string]configSection, len(cfg.Sections))
+	}
+	for key, section := range cfg.Sections {
+		doc.Sections[key] = configSection{
+			Order:       section.Order,
+			Parent:      section.Parent,
+			MergeID:     section.MergeID,
+			Content:     section.Content,
+			MergePoints: section.MergePoints,
+			Priority:    outputPriority(section.Priority),
+			Condition:   section.Condition,
+			Anchor:      section.Anchor,
+			Heading:     section.Heading,
+			Tags:        section.Tags,
+			Final:       section.Final,
+		}
+	}
+	if len(cfg.MergePoints) > 0 {
+		doc.MergePoints = make(map[string]configMergePoint, len(cfg.MergePoints))
+	}
+	for name, point := range cfg.MergePoints {
+		doc.MergePoints[name] = configMergePoint{
+			Placeholder: point.Placeholder,
+			Default:     point.Default,
+			Priority:    outputPriority(point.Priority),
+		}
+	}
+
+	if len(cfg.MergeTargets) > 0 {
+		doc.MergeTargets = make(map[string]configMergeTarget, len(cfg.MergeTargets))
+	}
+	for name, target := range cfg.MergeTargets {
+		doc.MergeTargets[name] = configMergeTarget{
+			Strategy: target.Strategy,
+			Content:  target.Content,
+			Priority: outputPriority(target.Priority),
+		}
+	}
+	return doc
+}
+
+// outputPriority returns nil for the zero priority, so it is left out
+func outputPriority(priority config.Priority) *config.Priority {
+	if priority.Type == config.PriorityNone && priority.Value == 0 {
+		return nil
+	}
+	return &priority
+}
+
+// configHeader is the leading comment of config output, without comment
+// syntax
+func configHeader(opts Options) string {
+	if opts.Stamp {
+		return stampText(opts.Sources, opts.Version)
+	}
+	return "Generated by claude-merge"
+}
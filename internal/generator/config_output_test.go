@@ -0,0 +1,136 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOutputConfig() *config.Config {
+	return &config.Config{
+		Metadata: config.Metadata{Title: "Guide", Language: "go", Priority: config.NewExplicitPriority(2)},
+		Sections: map[string]config.Section{
+			"intro":   {Order: 1, Content: "# Intro\nWelcome", ContentFile: "intro.md"},
+			"testing": {Order: 2, Content: "## Testing", Priority: config.NewRelativePriority(1), MergePoints: []string{"commands"}},
+		},
+		MergePoints: map[string]config.MergePoint{
+			"commands": {Placeholder: "<!-- COMMANDS -->", Default: "make test"},
+		},
+		MergeTargets: map[string]config.MergeTarget{
+			"commands": {Strategy: "append", Content: "go test ./...", Source: "go.toml"},
+		},
+	}
+}
+
+func TestGenerateConfigTOML(t *testing.T) {
+	provenance := map[string]string{"intro": "base.md", "testing": "go.toml"}
+
+	out, err := GenerateConfigTOML(newOutputConfig(), provenance, Options{})
+	require.NoError(t, err)
+
+	expected := `# Generated by claude-merge
+
+[metadata]
+title = "Guide"
+language = "go"
+[metadata.priority]
+type = "explicit"
+value = 2
+
+[sections]
+
+# merged from base.md
+[sections.intro]
+order = 1
+content = "# Intro\nWelcome"
+
+# merged from go.toml
+[sections.testing]
+order = 2
+content = "## Testing"
+merge_points = ["commands"]
+[sections.testing.priority]
+type = "relative"
+value = 1
+
+[merge_points]
+[merge_points.commands]
+placeholder = "<!-- COMMANDS -->"
+default = "make test"
+
+[merge_targets]
+[merge_targets.commands]
+strategy = "append"
+content = "go test ./..."
+`
+	assert.Equal(t, expected, out)
+}
+
+func TestGenerateConfigYAML(t *testing.T) {
+	provenance := map[string]string{"intro": "base.md"}
+	opts := Options{Stamp: true, Sources: []string{"base.md", "go.toml"}}
+
+	out, err := GenerateConfigYAML(newOutputConfig(), provenance, opts)
+	require.NoError(t, err)
+
+	expected := `# Generated by claude-merge from: base.md, go.toml. Do not edit.
+metadata:
+  title: Guide
+  language: go
+  priority:
+    type: explicit
+    value: 2
+sections:
+  # merged from base.md
+  intro:
+    order: 1
+    content: |-
+      # Intro
+      Welcome
+  testing:
+    order: 2
+    content: '## Testing'
+    merge_points:
+      - commands
+    priority:
+      type: relative
+      value: 1
+merge_points:
+  commands:
+    placeholder: <!-- COMMANDS -->
+    default: make test
+merge_targets:
+  commands:
+    strategy: append
+    content: go test ./...
+`
+	assert.Equal(t, expected, out)
+}
+
+func TestGenerateConfig_RoundTrip(t *testing.T) {
+	cfg := newOutputConfig()
+	generators := map[config.FileFormat]func(*config.Config, map[string]string, Options) (string, error){
+		config.FormatTOML: GenerateConfigTOML,
+		config.FormatYAML: GenerateConfigYAML,
+	}
+
+	for format, generate := range generators {
+		out, err := generate(cfg, map[string]string{"intro": "base.md"}, Options{})
+		require.NoError(t, err)
+
+		parsed, err := config.ParseConfig([]byte(out), format)
+		require.NoError(t, err, out)
+		assert.Equal(t, cfg.Metadata, parsed.Metadata)
+		assert.Equal(t, cfg.MergePoints, parsed.MergePoints)
+		assert.Len(t, parsed.Sections, 2)
+		for key, section := range cfg.Sections {
+			assert.Equal(t, section.Content, parsed.Sections[key].Content)
+			assert.Equal(t, section.Order, parsed.Sections[key].Order)
+			assert.Equal(t, section.Priority, parsed.Sections[key].Priority)
+			assert.Empty(t, parsed.Sections[key].ContentFile)
+		}
+		assert.Equal(t, "go test ./...", parsed.MergeTargets["commands"].Content)
+	}
+}
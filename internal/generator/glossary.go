@@ -0,0 +1,134 @@
+package generator
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// protectedRegex matches the parts of a line glossary links must not touch:
+// inline code, existing links and images, HTML tags and comments, and bare
+// URLs
+var protectedRegex = regexp.MustCompile("`[^`]*`|!?\\[[^\\]]*\\]\\([^)]*\\)|<[^>]*>|https?://\\S+")
+
+// Glossary links known terms in markdown to their documentation
+type Glossary struct {
+	// Terms maps each term to the URL it links to. Terms match case
+	// sensitively.
+	Terms map[string]string
+
+	// AllOccurrences links every occurrence of a term instead of only the
+	// first one in the document
+	AllOccurrences bool
+
+	// WholeWord only matches a term that is not part of a longer word, so
+	// "MCP" is not linked inside "MCPServer"
+	WholeWord bool
+}
+
+// LinkGlossary replaces occurrences of the glossary's terms in markdown with
+// markdown links. Fenced code blocks, inline code, existing links, HTML, and
+// URLs are left alone. Longer terms are linked first, so "MCP server" wins
+// over "MCP" where both match.
+func LinkGlossary(markdown string, glossary Glossary) string {
+	terms := make([]string, 0, len(glossary.Terms))
+	for term := range glossary.Terms {
+		if term != "" {
+			terms = append(terms, term)
+		}
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if len(terms[i]) != len(terms[j]) {
+			return len(terms[i]) > len(terms[j])
+		}
+		return terms[i] < terms[j]
+	})
+
+	lines := strings.Split(markdown, "\n")
+	for _, term := range terms {
+		link := "[" + term + "](" + glossary.Terms[term] + ")"
+		remaining := -1
+		if !glossary.AllOccurrences {
+			remaining = 1
+		}
+
+		fence := ""
+		for i, line := range lines {
+			if remaining == 0 {
+				break
+			}
+			if fence != "" {
+				if config.IsClosingFence(line, fence) {
+					fence = ""
+				}
+				continue
+			}
+			if _, f, _, ok := config.ParseFence(line); ok {
+				fence = f
+				continue
+			}
+			lines[i], remaining = linkTerm(line, term, link, glossary.WholeWord, remaining)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// linkTerm replaces up to limit unprotected occurrences of term in line with
+// link; a negative limit replaces them all. It returns the new line and the
+// remaining limit.
+func linkTerm(line, term, link string, wholeWord bool, limit int) (string, int) {
+	var builder strings.Builder
+	start := 0
+	for _, span := range append(protectedRegex.FindAllStringIndex(line, -1), []int{len(line), len(line)}) {
+		gap := line[start:span[0]]
+		for limit != 0 {
+			idx := indexTerm(gap, term, wholeWord)
+			if idx < 0 {
+				break
+			}
+			builder.WriteString(gap[:idx])
+			builder.WriteString(link)
+			gap = gap[idx+len(term):]
+			limit--
+		}
+		builder.WriteString(gap)
+		builder.WriteString(line[span[0]:span[1]])
+		start = span[1]
+	}
+	return builder.String(), limit
+}
+
+// indexTerm returns the index of the first occurrence of term in text, or -1.
+// With wholeWord, occurrences touching a letter, digit, or underscore are
+// skipped.
+func indexTerm(text, term string, wholeWord bool) int {
+	offset := 0
+	for {
+		idx := strings.Index(text[offset:], term)
+		if idx < 0 {
+			return -1
+		}
+		idx += offset
+		if !wholeWord || isWordBoundary(text, idx, idx+len(term)) {
+			return idx
+		}
+		offset = idx + 1
+	}
+}
+
+// isWordBoundary reports whether text[start:end] has no word character
+// directly before or after it
+func isWordBoundary(text string, start, end int) bool {
+	before, _ := utf8.DecodeLastRuneInString(text[:start])
+	after, _ := utf8.DecodeRuneInString(text[end:])
+	return !isWordRune(before) && !isWordRune(after)
+}
+
+// isWordRune reports whether r is part of a word
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkGlossary(t *testing.T) {
+	terms := map[string]string{
+		"MCP":        "https://mcp.example",
+		"MCP server": "https://mcp.example/server",
+		"C++":        "https://cpp.example",
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		glossary Glossary
+		expected string
+	}{
+		{
+			name:     "first occurrence only",
+			input:    "Use MCP.\nMCP again.",
+			glossary: Glossary{Terms: terms, WholeWord: true},
+			expected: "Use [MCP](https://mcp.example).\nMCP again.",
+		},
+		{
+			name:     "all occurrences",
+			input:    "Use MCP.\nMCP and MCP again.",
+			glossary: Glossary{Terms: terms, AllOccurrences: true, WholeWord: true},
+			expected: "Use [MCP](https://mcp.example).\n[MCP](https://mcp.example) and [MCP](https://mcp.example) again.",
+		},
+		{
+			name:     "longer term wins",
+			input:    "Start the MCP server, then MCP.",
+			glossary: Glossary{Terms: terms, WholeWord: true},
+			expected: "Start the [MCP server](https://mcp.example/server), then [MCP](https://mcp.example).",
+		},
+		{
+			name:     "whole word skips partial match",
+			input:    "MCPServer is not MCP.",
+			glossary: Glossary{Terms: terms, WholeWord: true},
+			expected: "MCPServer is not [MCP](https://mcp.example).",
+		},
+		{
+			name:     "partial match without whole word",
+			input:    "MCPServer is not MCP.",
+			glossary: Glossary{Terms: terms},
+			expected: "[MCP](https://mcp.example)Server is not MCP.",
+		},
+		{
+			name:     "term ending in punctuation",
+			input:    "Write C++ code.",
+			glossary: Glossary{Terms: terms, WholeWord: true},
+			expected: "Write [C++](https://cpp.example) code.",
+		},
+		{
+			name:     "code fences skipped",
+			input:    "```\nMCP\n```\nMCP",
+			glossary: Glossary{Terms: terms, WholeWord: true},
+			expected: "```\nMCP\n```\n[MCP](https://mcp.example)",
+		},
+		{
+			name:     "inline code, links, and comments skipped",
+			input:    "<!-- MCP --> `MCP` [MCP docs](https://x/MCP) MCP",
+			glossary: Glossary{Terms: terms, WholeWord: true},
+			expected: "<!-- MCP --> `MCP` [MCP docs](https://x/MCP) [MCP](https://mcp.example)",
+		},
+		{
+			name:     "no terms",
+			input:    "MCP",
+			glossary: Glossary{},
+			expected: "MCP",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, LinkGlossary(tt.input, tt.glossary))
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// HeadingOverflow decides what happens to headings deeper than the maximum
+// depth given to LimitHeadingDepth
+type HeadingOverflow string
+
+const (
+	// OverflowBold turns a too-deep heading into a paragraph of bold text
+	OverflowBold HeadingOverflow = "bold"
+
+	// OverflowClamp raises a too-deep heading to the maximum depth
+	OverflowClamp HeadingOverflow = "clamp"
+)
+
+// IsValid checks if an overflow mode is valid
+func (o HeadingOverflow) IsValid() bool {
+	return o == OverflowBold || o == OverflowClamp
+}
+
+// LimitHeadingDepth rewrites every ATX heading in markdown deeper than depth
+// according to overflow, so the document outline stays shallow. Content
+// below a rewritten heading is untouched, as are headings in fenced code
+// blocks. A depth below 1 leaves markdown unchanged.
+func LimitHeadingDepth(markdown string, depth int, overflow HeadingOverflow) string {
+	if depth < 1 {
+		return markdown
+	}
+
+	lines := strings.Split(markdown, "\n")
+	fence := ""
+	for i, line := range lines {
+		if fence != "" {
+			if config.IsClosingFence(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if _, f, _, ok := config.ParseFence(line); ok {
+			fence = f
+			continue
+		}
+
+		match := config.HeadingRegex.FindStringSubmatch(line)
+		if match == nil || len(match[1]) <= depth {
+			continue
+		}
+		text := match[2]
+		switch {
+		case overflow == OverflowClamp:
+			lines[i] = strings.TrimSpace(strings.Repeat("#", depth) + " " + text)
+		case text != "":
+			lines[i] = "**" + text + "**"
+		default:
+			lines[i] = ""
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ApplyHeadingOffset shifts the headings of every section of cfg by its
+// Metadata.HeadingOffset, then clears the offset so it is applied only once
+func ApplyHeadingOffset(cfg *config.Config) {
+	offset := cfg.Metadata.HeadingOffset
+	if offset == 0 {
+		return
+	}
+	for key, section := range cfg.Sections {
+		section.Content = config.ShiftHeadings(section.Content, offset)
+		section.Heading = config.ShiftHeadings(section.Heading, offset)
+		cfg.Sections[key] = section
+	}
+	cfg.Metadata.HeadingOffset = 0
+}
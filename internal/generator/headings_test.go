@@ -0,0 +1,76 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitHeadingDepth(t *testing.T) {
+	input := "# Title\n## Setup\n#### Details ####\nBody text\n##### Deeper\n######\n```sh\n#### not a heading\n```"
+
+	tests := []struct {
+		name     string
+		depth    int
+		overflow HeadingOverflow
+		want     string
+	}{
+		{
+			name:     "bold",
+			depth:    3,
+			overflow: OverflowBold,
+			want:     "# Title\n## Setup\n**Details**\nBody text\n**Deeper**\n\n```sh\n#### not a heading\n```",
+		},
+		{
+			name:     "clamp",
+			depth:    2,
+			overflow: OverflowClamp,
+			want:     "# Title\n## Setup\n## Details\nBody text\n## Deeper\n##\n```sh\n#### not a heading\n```",
+		},
+		{
+			name:     "deep enough",
+			depth:    6,
+			overflow: OverflowBold,
+			want:     input,
+		},
+		{
+			name:     "disabled",
+			depth:    0,
+			overflow: OverflowBold,
+			want:     input,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, LimitHeadingDepth(input, tt.depth, tt.overflow))
+		})
+	}
+}
+
+func TestHeadingOverflow_IsValid(t *testing.T) {
+	assert.True(t, OverflowBold.IsValid())
+	assert.True(t, OverflowClamp.IsValid())
+	assert.False(t, HeadingOverflow("drop").IsValid())
+}
+
+func TestApplyHeadingOffset(t *testing.T) {
+	cfg := &config.Config{
+		Metadata: config.Metadata{HeadingOffset: 1},
+		Sections: map[string]config.Section{
+			"content": {Content: "# Go\n## Testing\nRun go test"},
+			"rules":   {Content: "Be nice", Heading: "## Rules"},
+		},
+	}
+
+	ApplyHeadingOffset(cfg)
+
+	assert.Equal(t, "## Go\n### Testing\nRun go test", cfg.Sections["content"].Content)
+	assert.Equal(t, "Be nice", cfg.Sections["rules"].Content)
+	assert.Equal(t, "### Rules", cfg.Sections["rules"].Heading)
+	assert.Zero(t, cfg.Metadata.HeadingOffset, "the offset is applied only once")
+
+	ApplyHeadingOffset(cfg)
+	assert.Equal(t, "## Go\n### Testing\nRun go test", cfg.Sections["content"].Content)
+}
@@ -0,0 +1,217 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/arustydev/claude-merge/internal/merger"
+)
+
+// LintRule names one check made by LintMarkdown
+type LintRule string
+
+const (
+	// LintDuplicateHeading flags a heading with the same level and text as
+	// an earlier one
+	LintDuplicateHeading LintRule = "duplicate-heading"
+
+	// LintEmptySection flags a heading with no content before the next
+	// heading of the same or a higher level
+	LintEmptySection LintRule = "empty-section"
+
+	// LintTrailingWhitespace flags lines ending in spaces or tabs
+	LintTrailingWhitespace LintRule = "trailing-whitespace"
+
+	// LintHeadingSkip flags a heading more than one level deeper than the
+	// heading before it, such as an H3 directly under an H1
+	LintHeadingSkip LintRule = "heading-skip"
+
+	// LintUnresolvedPlaceholder flags placeholder tags left in the output
+	LintUnresolvedPlaceholder LintRule = "unresolved-placeholder"
+)
+
+// LintSeverity is how seriously a rule's findings are taken
+type LintSeverity string
+
+const (
+	// SeverityError findings fail a lint run
+	SeverityError LintSeverity = "error"
+
+	// SeverityWarning findings are reported without failing
+	SeverityWarning LintSeverity = "warning"
+
+	// SeverityOff turns a rule off
+	SeverityOff LintSeverity = "off"
+)
+
+// IsValid checks if a severity is valid
+func (s LintSeverity) IsValid() bool {
+	return s == SeverityError || s == SeverityWarning || s == SeverityOff
+}
+
+// LintSeverities maps each rule to its severity
+type LintSeverities map[LintRule]LintSeverity
+
+// DefaultLintSeverities returns the severity of every rule when none is
+// configured: leftover placeholders are errors, everything else a warning
+func DefaultLintSeverities() LintSeverities {
+	return LintSeverities{
+		LintDuplicateHeading:      SeverityWarning,
+		LintEmptySection:          SeverityWarning,
+		LintTrailingWhitespace:    SeverityWarning,
+		LintHeadingSkip:           SeverityWarning,
+		LintUnresolvedPlaceholder: SeverityError,
+	}
+}
+
+// ParseLintSeverities parses comma-separated rule=severity pairs, such as
+// "heading-skip=error,trailing-whitespace=off", over the defaults
+func ParseLintSeverities(spec string) (LintSeverities, error) {
+	severities := DefaultLintSeverities()
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		rule, severity, found := strings.Cut(pair, "=")
+		rule = strings.TrimSpace(rule)
+		severity = strings.TrimSpace(severity)
+		if !found {
+			return nil, fmt.Errorf("lint severity %q must be written as rule=severity", pair)
+		}
+		if _, known := severities[LintRule(rule)]; !known {
+			return nil, fmt.Errorf("unknown lint rule %q", rule)
+		}
+		if !LintSeverity(severity).IsValid() {
+			return nil, fmt.Errorf("lint severity for %s must be error, warning, or off, got %q", rule, severity)
+		}
+		severities[LintRule(rule)] = LintSeverity(severity)
+	}
+	return severities, nil
+}
+
+// LintFinding is one issue found by LintMarkdown
+type LintFinding struct {
+	// Line is the 1-based line number in the linted markdown
+	Line     int
+	Rule     LintRule
+	Severity LintSeverity
+	Message  string
+}
+
+// String formats the finding as "line N: severity: message (rule)"
+func (f LintFinding) String() string {
+	return fmt.Sprintf("line %d: %s: %s (%s)", f.Line, f.Severity, f.Message, f.Rule)
+}
+
+// LintMarkdown checks markdown for the issues this tool's output tends to
+// have and returns the findings in line order. Rules missing from severities
+// are off. Fenced code blocks are only checked for trailing whitespace and
+// never for headings or placeholders.
+func LintMarkdown(markdown string, severities LintSeverities) []LintFinding {
+	var findings []LintFinding
+	report := func(line int, rule LintRule, format string, args ...interface{}) {
+		severity := severities[rule]
+		if severity == "" || severity == SeverityOff {
+			return
+		}
+		findings = append(findings, LintFinding{Line: line, Rule: rule, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	type heading struct {
+		line, level int
+		text        string
+		hasContent  bool
+	}
+	var open []heading
+	seen := make(map[string]int)
+	previousLevel := 0
+	closeHeadings := func(level int) {
+		for len(open) > 0 && open[len(open)-1].level >= level {
+			h := open[len(open)-1]
+			open = open[:len(open)-1]
+			if !h.hasContent {
+				report(h.line, LintEmptySection, "section %q has no content", h.text)
+			}
+		}
+	}
+	markContent := func() {
+		for i := range open {
+			open[i].hasContent = true
+		}
+	}
+
+	fence := ""
+	for i, line := range strings.Split(markdown, "\n") {
+		number := i + 1
+		if strings.TrimRight(line, " \t") != line {
+			report(number, LintTrailingWhitespace, "trailing whitespace")
+		}
+
+		if fence != "" {
+			if config.IsClosingFence(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if _, f, _, ok := config.ParseFence(line); ok {
+			fence = f
+			markContent()
+			continue
+		}
+
+		match := config.HeadingRegex.FindStringSubmatch(line)
+		if match == nil {
+			if strings.TrimSpace(line) != "" && !isHTMLComment(line) {
+				markContent()
+			}
+			for _, tag := range merger.FindLeftoverTags(line) {
+				report(number, LintUnresolvedPlaceholder, "unresolved placeholder tag %s", tag)
+			}
+			continue
+		}
+
+		level := len(match[1])
+		text := strings.TrimSpace(match[2])
+		closeHeadings(level)
+		markContent()
+		open = append(open, heading{line: number, level: level, text: text})
+
+		if previousLevel > 0 && level > previousLevel+1 {
+			report(number, LintHeadingSkip, "heading level %d follows level %d", level, previousLevel)
+		}
+		previousLevel = level
+
+		key := fmt.Sprintf("%d:%s", level, text)
+		if first, ok := seen[key]; ok {
+			report(number, LintDuplicateHeading, "heading %q repeats line %d", text, first)
+		} else {
+			seen[key] = number
+		}
+	}
+	closeHeadings(1)
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Line < findings[j].Line
+	})
+	return findings
+}
+
+// HasLintErrors reports whether any finding has error severity
+func HasLintErrors(findings []LintFinding) bool {
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// isHTMLComment reports whether line is a complete HTML comment, such as the
+// generated-by comment, which doesn't count as section content
+func isHTMLComment(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "<!--") && strings.HasSuffix(trimmed, "-->")
+}
@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintMarkdown(t *testing.T) {
+	markdown := "<!-- Generated by claude-merge -->\n" +
+		"\n" +
+		"# Guide\n" +
+		"Intro text \n" +
+		"### Too deep\n" +
+		"Text\n" +
+		"## Empty\n" +
+		"<!-- just a comment -->\n" +
+		"## Testing\n" +
+		"```\n" +
+		"## not a heading <language-specific-x>\n" +
+		"```\n" +
+		"## Testing\n" +
+		"<language-specific-test-commands-here>\n" +
+		"## Last"
+
+	findings := LintMarkdown(markdown, DefaultLintSeverities())
+
+	var got []string
+	for _, finding := range findings {
+		got = append(got, finding.String())
+	}
+	assert.Equal(t, []string{
+		"line 4: warning: trailing whitespace (trailing-whitespace)",
+		"line 5: warning: heading level 3 follows level 1 (heading-skip)",
+		"line 7: warning: section \"Empty\" has no content (empty-section)",
+		"line 13: warning: heading \"Testing\" repeats line 9 (duplicate-heading)",
+		"line 14: error: unresolved placeholder tag <language-specific-test-commands-here> (unresolved-placeholder)",
+		"line 15: warning: section \"Last\" has no content (empty-section)",
+	}, got)
+	assert.True(t, HasLintErrors(findings))
+}
+
+func TestLintMarkdown_Severities(t *testing.T) {
+	severities, err := ParseLintSeverities("heading-skip=error, trailing-whitespace=off,unresolved-placeholder=warning")
+	require.NoError(t, err)
+
+	findings := LintMarkdown("# A\n### B \n<language-specific-x>", severities)
+	assert.Equal(t, []LintFinding{
+		{Line: 2, Rule: LintHeadingSkip, Severity: SeverityError, Message: "heading level 3 follows level 1"},
+		{Line: 3, Rule: LintUnresolvedPlaceholder, Severity: SeverityWarning, Message: "unresolved placeholder tag <language-specific-x>"},
+	}, findings)
+
+	assert.Empty(t, LintMarkdown("# A\n### B", LintSeverities{}))
+	assert.False(t, HasLintErrors(nil))
+}
+
+func TestParseLintSeverities(t *testing.T) {
+	severities, err := ParseLintSeverities("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultLintSeverities(), severities)
+
+	tests := []struct {
+		spec string
+		err  string
+	}{
+		{spec: "heading-skip", err: "must be written as rule=severity"},
+		{spec: "no-such-rule=error", err: "unknown lint rule"},
+		{spec: "heading-skip=fatal", err: "must be error, warning, or off"},
+	}
+	for _, tt := range tests {
+		_, err := ParseLintSeverities(tt.spec)
+		assert.ErrorContains(t, err, tt.err, tt.spec)
+	}
+}
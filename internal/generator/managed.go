@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Default markers around the managed block of a document updated with
+// ReplaceManagedBlock
+const (
+	DefaultManagedStart = "<!-- CLAUDE:START -->"
+	DefaultManagedEnd   = "<!-- CLAUDE:END -->"
+)
+
+// ErrNoManagedBlock is returned by ReplaceManagedBlock when the document
+// lacks either marker
+var ErrNoManagedBlock = errors.New("no managed block")
+
+// ReplaceManagedBlock returns document with everything between the first
+// start marker and the end marker after it replaced by content, on lines of
+// its own. The markers and everything outside them are kept as written.
+func ReplaceManagedBlock(document, content, start, end string) (string, error) {
+	i := strings.Index(document, start)
+	if i < 0 {
+		return "", fmt.Errorf("%w: start marker %q not found", ErrNoManagedBlock, start)
+	}
+	blockStart := i + len(start)
+	j := strings.Index(document[blockStart:], end)
+	if j < 0 {
+		return "", fmt.Errorf("%w: end marker %q not found after %q", ErrNoManagedBlock, end, start)
+	}
+	blockEnd := blockStart + j
+
+	newline := "\n"
+	if strings.Contains(document, "\r\n") {
+		newline = "\r\n"
+	}
+	content = strings.Trim(content, "\r\n")
+	if newline == "\r\n" {
+		content = strings.ReplaceAll(strings.ReplaceAll(content, "\r\n", "\n"), "\n", "\r\n")
+	}
+
+	var builder strings.Builder
+	builder.WriteString(document[:blockStart])
+	builder.WriteString(newline)
+	if content != "" {
+		builder.WriteString(content)
+		builder.WriteString(newline)
+	}
+	builder.WriteString(document[blockEnd:])
+	return builder.String(), nil
+}
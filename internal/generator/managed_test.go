@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceManagedBlock(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		content  string
+		start    string
+		end      string
+		want     string
+	}{
+		{
+			name:     "replaces the block",
+			document: "# Readme\n\nIntro\n\n<!-- CLAUDE:START -->\nold\n<!-- CLAUDE:END -->\n\nOutro\n",
+			content:  "# Merged\n\nNew\n",
+			want:     "# Readme\n\nIntro\n\n<!-- CLAUDE:START -->\n# Merged\n\nNew\n<!-- CLAUDE:END -->\n\nOutro\n",
+		},
+		{
+			name:     "fills an empty block",
+			document: "<!-- CLAUDE:START --><!-- CLAUDE:END -->",
+			content:  "New",
+			want:     "<!-- CLAUDE:START -->\nNew\n<!-- CLAUDE:END -->",
+		},
+		{
+			name:     "empties the block",
+			document: "<!-- CLAUDE:START -->\nold\n<!-- CLAUDE:END -->\n",
+			content:  "",
+			want:     "<!-- CLAUDE:START -->\n<!-- CLAUDE:END -->\n",
+		},
+		{
+			name:     "only the first block",
+			document: "<!-- CLAUDE:START -->\nA\n<!-- CLAUDE:END -->\n<!-- CLAUDE:START -->\nB\n<!-- CLAUDE:END -->\n",
+			content:  "New",
+			want:     "<!-- CLAUDE:START -->\nNew\n<!-- CLAUDE:END -->\n<!-- CLAUDE:START -->\nB\n<!-- CLAUDE:END -->\n",
+		},
+		{
+			name:     "custom markers",
+			document: "Text\n# BEGIN\nold\n# END\n",
+			content:  "New",
+			start:    "# BEGIN",
+			end:      "# END",
+			want:     "Text\n# BEGIN\nNew\n# END\n",
+		},
+		{
+			name:     "keeps CRLF line endings",
+			document: "Intro\r\n<!-- CLAUDE:START -->\r\nold\r\n<!-- CLAUDE:END -->\r\n",
+			content:  "A\nB\n",
+			want:     "Intro\r\n<!-- CLAUDE:START -->\r\nA\r\nB\r\n<!-- CLAUDE:END -->\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := tt.start, tt.end
+			if start == "" {
+				start, end = DefaultManagedStart, DefaultManagedEnd
+			}
+			got, err := ReplaceManagedBlock(tt.document, tt.content, start, end)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReplaceManagedBlock_MissingMarkers(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		wantErr  string
+	}{
+		{name: "no markers", document: "# Readme\n", wantErr: `start marker "<!-- CLAUDE:START -->" not found`},
+		{name: "no end marker", document: "<!-- CLAUDE:START -->\nold\n", wantErr: `end marker "<!-- CLAUDE:END -->" not found`},
+		{name: "end before start", document: "<!-- CLAUDE:END -->\n<!-- CLAUDE:START -->\n", wantErr: `end marker "<!-- CLAUDE:END -->" not found`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReplaceManagedBlock(tt.document, "New", DefaultManagedStart, DefaultManagedEnd)
+			require.ErrorIs(t, err, ErrNoManagedBlock)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
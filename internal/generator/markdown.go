@@ -1,20 +1,123 @@
 package generator
 
 import (
+	"context"
 	"fmt"
+	"html"
+	"io"
+	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/arustydev/claude-merge/internal/config"
 	"github.com/arustydev/claude-merge/internal/merger"
 )
 
+// titleHeadingRegex matches a level 1 ATX heading line
+var titleHeadingRegex = regexp.MustCompile(`^ {0,3}#(\s|$)`)
+
+// Options controls how markdown is rendered
+type Options struct {
+	// SectionGap is the number of blank lines between sections; negative
+	// values are treated as 0
+	SectionGap int
+
+	// Stamp replaces the plain "Generated by" comment with one that names
+	// the tool version and source files and warns against hand edits
+	Stamp bool
+
+	// Sources lists the input files named by the stamp
+	Sources []string
+
+	// Version is the tool version named by the stamp, if known
+	Version string
+
+	// Heading, if not empty, is written as the document's H1 ahead of every
+	// section; see RenderTitle
+	Heading string
+
+	// Timestamp, if set, adds a footer after every section giving the
+	// generation time, formatted with TimestampLayout (time.RFC3339 if empty)
+	Timestamp       time.Time
+	TimestampLayout string
+
+	// Frontmatter starts the document with YAML frontmatter holding the
+	// merged metadata fields and Metadata.Extra
+	Frontmatter bool
+
+	// NoTitle leaves out the document's H1: Heading is ignored and a level 1
+	// heading opening the first section is dropped, for output embedded in
+	// a document that supplies its own title
+	NoTitle bool
+
+	// Divider, if not empty, is written between consecutive sections, such
+	// as "---" for a horizontal rule. It is never written before the first
+	// section or after the last, and always has a blank line on each side so
+	// it can't turn the line above it into a heading.
+	Divider string
+
+	// NoOrder writes sections in the order they were loaded, ignoring their
+	// order fields, to tell layout problems caused by orders from ones
+	// caused by the merge sequence
+	NoOrder bool
+}
+
+// DefaultOptions returns the options GenerateMarkdown uses
+func DefaultOptions() Options {
+	return Options{SectionGap: 1}
+}
+
 // GenerateMarkdown converts a config into markdown content
 func GenerateMarkdown(cfg *config.Config) string {
+	return GenerateMarkdownWithOptions(cfg, DefaultOptions())
+}
+
+// GenerateMarkdownWithOptions converts a config into markdown content using
+// the given rendering options
+func GenerateMarkdownWithOptions(cfg *config.Config, opts Options) string {
+	// The background context is never cancelled and parsed metadata always
+	// encodes as YAML, so there is no error
+	markdown, _ := generateMarkdown(context.Background(), cfg, opts)
+	return markdown
+}
+
+// WriteMarkdown renders cfg as markdown to w, aborting with ctx.Err() if the
+// context is cancelled while sections are being rendered
+func WriteMarkdown(ctx context.Context, w io.Writer, cfg *config.Config) error {
+	markdown, err := generateMarkdown(ctx, cfg, DefaultOptions())
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, markdown)
+	return err
+}
+
+// generateMarkdown renders cfg, checking ctx before each section
+func generateMarkdown(ctx context.Context, cfg *config.Config, opts Options) (string, error) {
 	var builder strings.Builder
+	separator := strings.Repeat("\n", max(opts.SectionGap, 0)+1)
+	divider := strings.Trim(opts.Divider, "\n")
+	sectionSeparator := separator
+	if divider != "" {
+		sectionSeparator = strings.Repeat("\n", max(opts.SectionGap, 1)+1)
+	}
+
+	// Frontmatter must open the document for other tools to find it
+	if opts.Frontmatter {
+		frontmatter, err := GenerateFrontmatter(cfg.Metadata)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(frontmatter)
+	}
 
 	// Write metadata as HTML comment
-	builder.WriteString("<!-- Generated by claude-merge -->\n")
+	if opts.Stamp {
+		builder.WriteString(fmt.Sprintf("<!-- %s -->\n", stampText(opts.Sources, opts.Version)))
+	} else {
+		builder.WriteString("<!-- Generated by claude-merge -->\n")
+	}
 	if cfg.Metadata.Title != "" {
 		builder.WriteString(fmt.Sprintf("<!-- Title: %s -->\n", cfg.Metadata.Title))
 	}
@@ -23,22 +126,85 @@ func GenerateMarkdown(cfg *config.Config) string {
 	}
 	builder.WriteString("\n")
 
+	if opts.Heading != "" && !opts.NoTitle {
+		builder.WriteString("# " + opts.Heading)
+		builder.WriteString(separator)
+	}
+
 	// Apply merge targets to merge points in sections
 	processedConfig := applyMergeTargets(cfg)
 
-	// Sort sections by order
-	sections := sortSections(processedConfig.Sections)
+	// Sort sections by order, or keep them in load order under NoOrder
+	var sections []config.Section
+	if opts.NoOrder {
+		sections = loadOrderSections(processedConfig.Sections)
+	} else {
+		sections = sortSections(processedConfig.Sections)
+	}
 
 	// Write each section
-	for _, section := range sections {
+	written := 0
+	for i, section := range sections {
+		err := ctx.Err()
+		if err != nil {
+			return "", err
+		}
+
+		if opts.NoTitle && i == 0 {
+			section.Content = dropTitleHeading(section.Content)
+			if section.Content == "" && section.Anchor == "" {
+				continue
+			}
+		}
+
+		if divider != "" && written > 0 {
+			builder.WriteString(divider)
+			builder.WriteString(sectionSeparator)
+		}
+
+		// An explicit anchor keeps deep links stable when headings change
+		if section.Anchor != "" {
+			builder.WriteString(fmt.Sprintf("<a id=\"%s\"></a>\n", html.EscapeString(section.Anchor)))
+		}
 		builder.WriteString(section.Content)
-		builder.WriteString("\n\n")
+		builder.WriteString(sectionSeparator)
+		written++
+	}
+
+	// The footer comes after every section, whatever their order
+	if !opts.Timestamp.IsZero() {
+		builder.WriteString(timestampFooter(opts.Timestamp, opts.TimestampLayout))
 	}
 
-	return strings.TrimSpace(builder.String())
+	return strings.TrimSpace(builder.String()), nil
 }
 
-// sortSections returns sections sorted by their order field
+// dropTitleHeading removes a level 1 heading opening content, along with the
+// blank lines after it
+func dropTitleHeading(content string) string {
+	trimmed := strings.TrimLeft(content, "\n")
+	first, rest, _ := strings.Cut(trimmed, "\n")
+	if !titleHeadingRegex.MatchString(first) {
+		return content
+	}
+	return strings.TrimLeft(rest, "\n")
+}
+
+// stampText describes where generated output came from, without any comment
+// syntax so each output format can wrap it appropriately
+func stampText(sources []string, version string) string {
+	text := "Generated by claude-merge"
+	if version != "" {
+		text += " " + version
+	}
+	if len(sources) > 0 {
+		text += " from: " + strings.Join(sources, ", ")
+	}
+	return text + ". Do not edit."
+}
+
+// sortSections returns sections sorted by their order field, then by the
+// order they were read in
 func sortSections(sections map[string]config.Section) []config.Section {
 	// First, collect all sections
 	var list []config.Section
@@ -46,14 +212,46 @@ func sortSections(sections map[string]config.Section) []config.Section {
 		list = append(list, section)
 	}
 
-	// Sort by order
+	// Sort by order, then by load position
 	sort.Slice(list, func(i, j int) bool {
-		return list[i].Order < list[j].Order
+		if list[i].Order != list[j].Order {
+			return list[i].Order < list[j].Order
+		}
+		return list[i].Seq < list[j].Seq
 	})
 
 	return list
 }
 
+// loadOrderSections returns sections in the order they were loaded, by Seq,
+// ignoring their order fields. Sections without a load position, such as
+// those built while merging, come first, by key.
+func loadOrderSections(sections map[string]config.Section) []config.Section {
+	keys := make([]string, 0, len(sections))
+	for key := range sections {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := sections[keys[i]], sections[keys[j]]
+		if a.Seq != b.Seq {
+			return a.Seq < b.Seq
+		}
+		return keys[i] < keys[j]
+	})
+
+	list := make([]config.Section, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, sections[key])
+	}
+	return list
+}
+
+// sortedKeys returns the section keys in output order: by order field, then
+// by the order the sections were read in, then by key
+func sortedKeys(sections map[string]config.Section) []string {
+	return config.SortedSectionKeys(sections)
+}
+
 // applyMergeTargets applies merge targets to merge points in the content
 func applyMergeTargets(cfg *config.Config) *config.Config {
 	// Create a copy of the config
@@ -76,7 +274,7 @@ func applyMergeTargets(cfg *config.Config) *config.Config {
 					// Apply the merge strategy
 					oldContent := mergePoint.Default
 					newContent := target.Content
-					mergedContent := merger.ApplyStrategy(merger.MergeStrategy(target.Strategy), oldContent, newContent)
+					mergedContent := merger.ApplyStrategyFrom(merger.MergeStrategy(target.Strategy), oldContent, newContent, target.Source)
 
 					// Replace the placeholder with the merged content
 					content = strings.Replace(content, mergePoint.Placeholder, mergedContent, -1)
@@ -84,9 +282,25 @@ func applyMergeTargets(cfg *config.Config) *config.Config {
 			}
 		}
 
-		processedSection.Content = content
+		processedSection.Content = withHeading(section.Heading, content)
 		result.Sections[name] = processedSection
 	}
 
 	return result
+}
+
+// withHeading starts content with heading unless heading is empty or the
+// content already opens with a heading, as markdown inputs do
+func withHeading(heading, content string) string {
+	if heading == "" {
+		return content
+	}
+	trimmed := strings.TrimSpace(content)
+	if config.HeadingRegex.MatchString(strings.SplitN(trimmed, "\n", 2)[0]) {
+		return content
+	}
+	if trimmed == "" {
+		return heading
+	}
+	return heading + "\n" + content
 }
\ No newline at end of file
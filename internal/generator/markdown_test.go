@@ -1,11 +1,17 @@
 package generator
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/arustydev/claude-merge/internal/merger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGenerateMarkdown(t *testing.T) {
@@ -124,6 +130,41 @@ func TestSortSections_SameOrder(t *testing.T) {
 	assert.Equal(t, 2, sorted[2].Order)
 }
 
+func TestSortSections_SameOrderBySeq(t *testing.T) {
+	sections := map[string]config.Section{
+		"a": {Order: 1, Seq: 3, Content: "A"},
+		"b": {Order: 1, Seq: 2, Content: "B"},
+		"c": {Order: 1, Content: "C"},
+		"d": {Order: 0, Seq: 9, Content: "D"},
+	}
+
+	sorted := sortSections(sections)
+
+	require.Len(t, sorted, 4)
+	// Within same order, sections follow the order they were read in
+	assert.Equal(t, "D", sorted[0].Content)
+	assert.Equal(t, "C", sorted[1].Content)
+	assert.Equal(t, "B", sorted[2].Content)
+	assert.Equal(t, "A", sorted[3].Content)
+}
+
+func TestGenerateMarkdownWithOptions_NoOrder(t *testing.T) {
+	cfg := &config.Config{
+		Sections: map[string]config.Section{
+			"setup":   {Order: 1, Seq: 3, Content: "## Setup"},
+			"testing": {Order: 2, Seq: 1, Content: "## Testing"},
+			"style":   {Order: 3, Seq: 2, Content: "## Style"},
+			"built":   {Order: 9, Content: "## Built"},
+		},
+	}
+
+	opts := DefaultOptions()
+	assert.Contains(t, GenerateMarkdownWithOptions(cfg, opts), "## Setup\n\n## Testing\n\n## Style\n\n## Built")
+
+	opts.NoOrder = true
+	assert.Contains(t, GenerateMarkdownWithOptions(cfg, opts), "## Built\n\n## Testing\n\n## Style\n\n## Setup", "load order, with unplaced sections first")
+}
+
 func TestGenerateMarkdown_WithMergeTargets(t *testing.T) {
 	cfg := &config.Config{
 		Metadata: config.Metadata{
@@ -155,4 +196,277 @@ func TestGenerateMarkdown_WithMergeTargets(t *testing.T) {
 	assert.Contains(t, result, "Merged content")
 	assert.NotContains(t, result, "<!-- MERGE:example -->")
 	assert.NotContains(t, result, "Default content")
-}
\ No newline at end of file
+}
+
+func TestGenerateMarkdown_CollapseMergeTarget(t *testing.T) {
+	cfg := &config.Config{
+		Sections: map[string]config.Section{
+			"notes": {Order: 1, Content: "# Notes\n\n<!-- MERGE:notes -->"},
+		},
+		MergePoints: map[string]config.MergePoint{
+			"notes": {Placeholder: "<!-- MERGE:notes -->", Default: "Shared notes"},
+		},
+		MergeTargets: map[string]config.MergeTarget{
+			"notes": {Strategy: "collapse", Content: "Go notes", Source: "go.toml"},
+		},
+	}
+
+	result := GenerateMarkdown(cfg)
+	assert.Contains(t, result, "# Notes\n\nShared notes\n<details>\n<summary>go.toml</summary>\n\nGo notes\n\n</details>")
+}
+
+func TestGenerateMarkdown_Anchor(t *testing.T) {
+	cfg := &config.Config{
+		Sections: map[string]config.Section{
+			"testing": {
+				Order:   1,
+				Content: "## Testing Guidelines (v2)\n\nRun the tests.",
+				Anchor:  "testing",
+			},
+			"plain": {
+				Order:   2,
+				Content: "## Plain",
+			},
+		},
+	}
+
+	result := GenerateMarkdown(cfg)
+
+	assert.Contains(t, result, "<a id=\"testing\"></a>\n## Testing Guidelines (v2)")
+	assert.Equal(t, 1, strings.Count(result, "<a id="))
+}
+
+func TestGenerateMarkdown_Heading(t *testing.T) {
+	cfg := &config.Config{
+		Sections: map[string]config.Section{
+			"header_2_strict_requirements": {
+				Order:   1,
+				Content: "No exceptions.",
+				Heading: "## STRICT REQUIREMENTS!!",
+			},
+			"header_2_setup": {
+				Order:   2,
+				Content: "## Setup, revised\nRun make.",
+				Heading: "## Setup",
+			},
+		},
+	}
+
+	result := GenerateMarkdown(cfg)
+
+	assert.Contains(t, result, "## STRICT REQUIREMENTS!!\nNo exceptions.")
+	assert.Contains(t, result, "## Setup, revised\nRun make.")
+	assert.NotContains(t, result, "## Setup\n", "content with its own heading keeps it")
+}
+
+func TestGenerateMarkdown_LoadedHeading(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "common.md")
+	override := filepath.Join(dir, "team.toml")
+	require.NoError(t, os.WriteFile(base, []byte("## STRICT REQUIREMENTS!! 🚨\nNo force pushes.\n"), 0644))
+	require.NoError(t, os.WriteFile(override, []byte("[sections.header_2_strict_requirements]\ncontent = \"Only merge reviewed changes.\"\n"), 0644))
+
+	var configs []*config.Config
+	for _, path := range []string{base, override} {
+		cfg, err := config.LoadConfigWithOptions(path, config.LoadOptions{SplitMarkdown: true})
+		require.NoError(t, err)
+		configs = append(configs, cfg)
+	}
+	merged, err := merger.NewPriorityMerger(false).MergeAll(configs)
+	require.NoError(t, err)
+
+	result := GenerateMarkdown(merged)
+
+	assert.Contains(t, result, "## STRICT REQUIREMENTS!! 🚨\nOnly merge reviewed changes.")
+}
+
+func TestWithHeading(t *testing.T) {
+	tests := []struct {
+		name    string
+		heading string
+		content string
+		want    string
+	}{
+		{name: "no heading", content: "Body", want: "Body"},
+		{name: "content without heading", heading: "## Rules!", content: "Body", want: "## Rules!\nBody"},
+		{name: "content with heading", heading: "## Rules!", content: "## Rules\nBody", want: "## Rules\nBody"},
+		{name: "leading blank lines", heading: "## Rules!", content: "\n### Sub\nBody", want: "\n### Sub\nBody"},
+		{name: "empty content", heading: "## Rules!", content: "", want: "## Rules!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, withHeading(tt.heading, tt.content))
+		})
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	cfg := &config.Config{
+		Sections: map[string]config.Section{
+			"only": {Order: 1, Content: "# Only"},
+		},
+	}
+
+	var buf strings.Builder
+	err := WriteMarkdown(context.Background(), &buf, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, GenerateMarkdown(cfg), buf.String())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	buf.Reset()
+	err = WriteMarkdown(ctx, &buf, cfg)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, buf.String())
+}
+
+func TestGenerateMarkdownWithOptions_SectionGap(t *testing.T) {
+	cfg := &config.Config{
+		Sections: map[string]config.Section{
+			"one": {Order: 1, Content: "# One\n\n```go\nx := 1\n\n\ny := 2\n```"},
+			"two": {Order: 2, Content: "# Two"},
+		},
+	}
+
+	tests := []struct {
+		gap  int
+		want string
+	}{
+		{gap: 0, want: "```\n# Two"},
+		{gap: 1, want: "```\n\n# Two"},
+		{gap: 2, want: "```\n\n\n# Two"},
+		{gap: -1, want: "```\n# Two"},
+	}
+
+	for _, tt := range tests {
+		result := GenerateMarkdownWithOptions(cfg, Options{SectionGap: tt.gap})
+		assert.Contains(t, result, tt.want, "gap %d", tt.gap)
+		assert.Contains(t, result, "x := 1\n\n\ny := 2", "code blocks are unaffected")
+		assert.True(t, strings.HasSuffix(result, "# Two"), "no trailing separator")
+	}
+
+	assert.Equal(t, GenerateMarkdown(cfg), GenerateMarkdownWithOptions(cfg, DefaultOptions()))
+}
+
+func TestGenerateMarkdownWithOptions_Divider(t *testing.T) {
+	cfg := &config.Config{
+		Sections: map[string]config.Section{
+			"one":   {Order: 1, Content: "# One\nText"},
+			"two":   {Order: 2, Content: "## Two\nMore"},
+			"three": {Order: 3, Content: "## Three"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{
+			name: "horizontal rule",
+			opts: Options{SectionGap: 1, Divider: "---"},
+			want: "# One\nText\n\n---\n\n## Two\nMore\n\n---\n\n## Three",
+		},
+		{
+			name: "gap 0 keeps blank lines around the divider",
+			opts: Options{SectionGap: 0, Divider: "---"},
+			want: "# One\nText\n\n---\n\n## Two\nMore\n\n---\n\n## Three",
+		},
+		{
+			name: "gap 2",
+			opts: Options{SectionGap: 2, Divider: "* * *"},
+			want: "# One\nText\n\n\n* * *\n\n\n## Two\nMore\n\n\n* * *\n\n\n## Three",
+		},
+		{
+			name: "multi-line divider",
+			opts: Options{SectionGap: 1, Divider: "<br>\n<hr>\n"},
+			want: "# One\nText\n\n<br>\n<hr>\n\n## Two\nMore\n\n<br>\n<hr>\n\n## Three",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GenerateMarkdownWithOptions(cfg, tt.opts)
+			body := strings.TrimPrefix(result, "<!-- Generated by claude-merge -->\n\n")
+			assert.Equal(t, tt.want, body)
+		})
+	}
+}
+
+func TestGenerateMarkdownWithOptions_DividerSkipsTitleAndFooter(t *testing.T) {
+	cfg := &config.Config{
+		Metadata: config.Metadata{Title: "Guide"},
+		Sections: map[string]config.Section{
+			"title": {Order: 1, Content: "# Guide"},
+			"rules": {Order: 2, Content: "## Rules"},
+		},
+	}
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	result := GenerateMarkdownWithOptions(cfg, Options{
+		SectionGap:  1,
+		Divider:     "---",
+		Frontmatter: true,
+		NoTitle:     true,
+		Timestamp:   at,
+	})
+
+	assert.Equal(t, 2, strings.Count(result, "---\n"), "only the frontmatter delimiters")
+	assert.True(t, strings.HasSuffix(result, "<!-- Title: Guide -->\n\n## Rules\n\n"+timestampFooter(at, "")))
+}
+
+func TestGenerateMarkdownWithOptions_Stamp(t *testing.T) {
+	cfg := &config.Config{
+		Metadata: config.Metadata{Title: "Stamped"},
+		Sections: map[string]config.Section{
+			"only": {Order: 1, Content: "# Only"},
+		},
+	}
+
+	result := GenerateMarkdownWithOptions(cfg, Options{
+		SectionGap: 1,
+		Stamp:      true,
+		Sources:    []string{"a.md", "b.toml"},
+		Version:    "1.2.3",
+	})
+	assert.True(t, strings.HasPrefix(result, "<!-- Generated by claude-merge 1.2.3 from: a.md, b.toml. Do not edit. -->\n<!-- Title: Stamped -->"))
+	assert.Equal(t, 1, strings.Count(result, "Generated by claude-merge"))
+
+	result = GenerateMarkdownWithOptions(cfg, Options{Stamp: true})
+	assert.True(t, strings.HasPrefix(result, "<!-- Generated by claude-merge. Do not edit. -->"))
+}
+
+func TestGenerateMarkdownWithOptions_NoTitle(t *testing.T) {
+	cfg := &config.Config{
+		Metadata: config.Metadata{Title: "Guide"},
+		Sections: map[string]config.Section{
+			"title": {Order: 1, Content: "# Guide\n\nIntro text."},
+			"rules": {Order: 2, Content: "# Rules\n## Testing"},
+		},
+	}
+
+	result := GenerateMarkdownWithOptions(cfg, Options{SectionGap: 1, Heading: "Guide", NoTitle: true})
+
+	assert.Equal(t, "<!-- Generated by claude-merge -->\n<!-- Title: Guide -->\n\nIntro text.\n\n# Rules\n## Testing", result)
+}
+
+func TestDropTitleHeading(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{content: "# Guide\n\nBody", want: "Body"},
+		{content: "\n# Guide\nBody", want: "Body"},
+		{content: "# Guide", want: ""},
+		{content: "## Setup\nBody", want: "## Setup\nBody"},
+		{content: "#hashtag\nBody", want: "#hashtag\nBody"},
+		{content: "Body\n# Later", want: "Body\n# Later"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			assert.Equal(t, tt.want, dropTitleHeading(tt.content))
+		})
+	}
+}
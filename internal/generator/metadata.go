@@ -0,0 +1,111 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// MetadataDocument is the metadata-only output: the merged metadata fields,
+// without the priority that decided them
+type MetadataDocument struct {
+	Title       string `json:"title,omitempty" yaml:"title,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Version     string `json:"version,omitempty" yaml:"version,omitempty"`
+	Language    string `json:"language,omitempty" yaml:"language,omitempty"`
+	Extends     string `json:"extends,omitempty" yaml:"extends,omitempty"`
+}
+
+// newMetadataDocument copies the rendered fields of metadata
+func newMetadataDocument(metadata config.Metadata) MetadataDocument {
+	return MetadataDocument{
+		Title:       metadata.Title,
+		Description: metadata.Description,
+		Version:     metadata.Version,
+		Language:    metadata.Language,
+		Extends:     metadata.Extends,
+	}
+}
+
+// GenerateMetadataJSON renders metadata as an indented MetadataDocument
+func GenerateMetadataJSON(metadata config.Metadata) ([]byte, error) {
+	return json.MarshalIndent(newMetadataDocument(metadata), "", "  ")
+}
+
+// GenerateMetadataMarkdown renders metadata as YAML frontmatter followed by a
+// short markdown block with the title and description. The result is itself
+// a valid markdown input.
+func GenerateMetadataMarkdown(metadata config.Metadata) (string, error) {
+	var builder strings.Builder
+
+	doc := newMetadataDocument(metadata)
+	if doc != (MetadataDocument{}) {
+		frontmatter, err := yaml.Marshal(doc)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode metadata: %w", err)
+		}
+		builder.WriteString("---\n")
+		builder.Write(frontmatter)
+		builder.WriteString("---\n")
+	}
+
+	if metadata.Title != "" {
+		builder.WriteString(fmt.Sprintf("\n# %s\n", metadata.Title))
+	}
+	if metadata.Description != "" {
+		builder.WriteString(fmt.Sprintf("\n%s\n", metadata.Description))
+	}
+	return builder.String(), nil
+}
+
+// GenerateFrontmatter renders metadata as a YAML frontmatter block: the
+// fields of MetadataDocument first, then the Metadata.Extra fields sorted by
+// key. An extra field named like one of the standard fields is left out. It
+// returns "" when there is nothing to write.
+func GenerateFrontmatter(metadata config.Metadata) (string, error) {
+	var root yaml.Node
+	err := root.Encode(newMetadataDocument(metadata))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	keys := make([]string, 0, len(metadata.Extra))
+	for key := range metadata.Extra {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if hasMappingKey(&root, key) {
+			continue
+		}
+		var value yaml.Node
+		err := value.Encode(metadata.Extra[key])
+		if err != nil {
+			return "", fmt.Errorf("failed to encode metadata field %s: %w", key, err)
+		}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &value)
+	}
+	if len(root.Content) == 0 {
+		return "", nil
+	}
+
+	frontmatter, err := yaml.Marshal(&root)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode metadata: %w", err)
+	}
+	return "---\n" + string(frontmatter) + "---\n", nil
+}
+
+// hasMappingKey reports whether the mapping node has key
+func hasMappingKey(mapping *yaml.Node, key string) bool {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateMetadataMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata config.Metadata
+		want     string
+	}{
+		{
+			name: "all fields",
+			metadata: config.Metadata{
+				Title:       "Go Guide",
+				Description: "Conventions for Go projects",
+				Version:     "1.2.0",
+				Language:    "go",
+				Extends:     "common.md",
+				Priority:    config.NewExplicitPriority(10),
+			},
+			want: "---\ntitle: Go Guide\ndescription: Conventions for Go projects\nversion: 1.2.0\nlanguage: go\nextends: common.md\n---\n\n# Go Guide\n\nConventions for Go projects\n",
+		},
+		{
+			name:     "title only",
+			metadata: config.Metadata{Title: "Guide"},
+			want:     "---\ntitle: Guide\n---\n\n# Guide\n",
+		},
+		{
+			name:     "empty",
+			metadata: config.Metadata{},
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateMetadataMarkdown(tt.metadata)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGenerateMetadataMarkdown_RoundTrip(t *testing.T) {
+	metadata := config.Metadata{Title: "Guide: Go", Description: "Notes", Version: "2", Language: "go"}
+
+	markdown, err := GenerateMetadataMarkdown(metadata)
+	require.NoError(t, err)
+
+	parsed, err := config.ParseConfig([]byte(markdown), config.FormatMarkdown)
+	require.NoError(t, err)
+	assert.Equal(t, metadata, parsed.Metadata)
+}
+
+func TestGenerateMetadataJSON(t *testing.T) {
+	data, err := GenerateMetadataJSON(config.Metadata{
+		Title:    "Guide",
+		Language: "go",
+		Priority: config.NewExplicitPriority(10),
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"title": "Guide", "language": "go"}`, string(data))
+}
+
+func TestGenerateFrontmatter(t *testing.T) {
+	got, err := GenerateFrontmatter(config.Metadata{
+		Title: "Guide",
+		Extra: map[string]interface{}{
+			"tags":  []interface{}{"go", "style"},
+			"owner": "team",
+			"title": "Ignored",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "---\ntitle: Guide\nowner: team\ntags:\n    - go\n    - style\n---\n", got)
+
+	got, err = GenerateFrontmatter(config.Metadata{})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
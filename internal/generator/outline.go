@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// slugRegex matches runs of characters that are not part of a slug
+var slugRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ParseOutline returns the slugs of the headings in a reference markdown
+// document, in document order. Headings inside fenced code blocks are
+// ignored.
+func ParseOutline(markdown string) []string {
+	var outline []string
+	fence := ""
+
+	for _, line := range strings.Split(markdown, "\n") {
+		if fence != "" {
+			if config.IsClosingFence(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if _, f, _, ok := config.ParseFence(line); ok {
+			fence = f
+			continue
+		}
+
+		if match := config.HeadingRegex.FindStringSubmatch(line); match != nil {
+			if slug := outlineSlug(match[2]); slug != "" {
+				outline = append(outline, slug)
+			}
+		}
+	}
+	return outline
+}
+
+// ApplyOutline rewrites the Order of cfg's sections to follow outline. A
+// section matches an outline entry by its key or by its first heading, which
+// is its Heading when the content has none. Sections
+// that match nothing keep their relative order and are placed after all
+// matched sections.
+func ApplyOutline(cfg *config.Config, outline []string) {
+	positions := make(map[string]int, len(outline))
+	for i, slug := range outline {
+		if _, exists := positions[slug]; !exists {
+			positions[slug] = i
+		}
+	}
+
+	var unmatched []string
+	for name, section := range cfg.Sections {
+		pos, ok := positions[outlineSlug(name)]
+		if !ok {
+			pos, ok = positions[outlineSlug(firstHeading(withHeading(section.Heading, section.Content)))]
+		}
+		if !ok {
+			unmatched = append(unmatched, name)
+			continue
+		}
+		section.Order = pos + 1
+		cfg.Sections[name] = section
+	}
+
+	sort.Slice(unmatched, func(i, j int) bool {
+		a, b := cfg.Sections[unmatched[i]], cfg.Sections[unmatched[j]]
+		if a.Order != b.Order {
+			return a.Order < b.Order
+		}
+		return unmatched[i] < unmatched[j]
+	})
+	for i, name := range unmatched {
+		section := cfg.Sections[name]
+		section.Order = len(outline) + i + 1
+		cfg.Sections[name] = section
+	}
+}
+
+// firstHeading returns the text of the first heading in content, or "" if
+// there is none
+func firstHeading(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if match := config.HeadingRegex.FindStringSubmatch(line); match != nil {
+			return match[2]
+		}
+	}
+	return ""
+}
+
+// outlineSlug normalizes a heading or section key so that "Code Style",
+// "code-style", and "code_style" compare equal
+func outlineSlug(text string) string {
+	return strings.Trim(slugRegex.ReplaceAllString(strings.ToLower(text), "_"), "_")
+}
@@ -0,0 +1,42 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOutline(t *testing.T) {
+	reference := "# Guidelines\n\nIntro\n\n## Code Style ##\n\n```bash\n# not a heading\n```\n\n### Testing\n#hashtag\n"
+
+	assert.Equal(t, []string{"guidelines", "code_style", "testing"}, ParseOutline(reference))
+}
+
+func TestApplyOutline(t *testing.T) {
+	cfg := &config.Config{
+		Sections: map[string]config.Section{
+			"testing":    {Order: 1, Content: "## Testing"},
+			"style":      {Order: 2, Content: "## Code Style\n\nUse gofmt."},
+			"license":    {Order: 3, Content: "## License"},
+			"extra":      {Order: 9, Content: "## Extra"},
+			"appendix":   {Order: 9, Content: "## Appendix"},
+			"code-owner": {Order: 4, Content: "Owners"},
+		},
+	}
+
+	ApplyOutline(cfg, []string{"code_style", "code_owner", "testing"})
+
+	var got []string
+	for _, section := range sortSections(cfg.Sections) {
+		got = append(got, section.Content)
+	}
+	assert.Equal(t, []string{
+		"## Code Style\n\nUse gofmt.",
+		"Owners",
+		"## Testing",
+		"## License",
+		"## Appendix",
+		"## Extra",
+	}, got)
+}
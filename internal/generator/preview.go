@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// ANSI escape sequences used by Preview
+const (
+	ansiBold   = "\x1b[1m"
+	ansiDim    = "\x1b[2m"
+	ansiItalic = "\x1b[3m"
+	ansiReset  = "\x1b[0m"
+)
+
+var (
+	// inlineCodeRegex matches an inline code span, which is never styled
+	inlineCodeRegex = regexp.MustCompile("`[^`]*`")
+
+	// strongRegex matches **bold** and __bold__ text
+	strongRegex = regexp.MustCompile(`\*\*([^*\s](?:[^*]*[^*\s])?)\*\*|__([^_\s](?:[^_]*[^_\s])?)__`)
+
+	// starEmphasisRegex matches *italic* text
+	starEmphasisRegex = regexp.MustCompile(`\*([^*\s](?:[^*]*[^*\s])?)\*`)
+
+	// underscoreEmphasisRegex matches _italic_ text whose underscores don't
+	// touch a word character, so snake_case names are left alone
+	underscoreEmphasisRegex = regexp.MustCompile(`(^|\W)_([^_\s](?:[^_]*[^_\s])?)_(\W|$)`)
+)
+
+// Preview styles markdown for reading in a terminal with ANSI escapes:
+// headings are bold, **strong** and *emphasized* text is bold and italic
+// with its markers removed, and fenced code blocks, fences included, are
+// dim. Everything else, including inline code, is left as written.
+func Preview(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	fence := ""
+	for i, line := range lines {
+		switch {
+		case fence != "":
+			if config.IsClosingFence(line, fence) {
+				fence = ""
+			}
+			lines[i] = styled(ansiDim, line)
+		case isFenceLine(line, &fence):
+			lines[i] = styled(ansiDim, line)
+		case config.HeadingRegex.MatchString(line):
+			lines[i] = styled(ansiBold, line)
+		default:
+			lines[i] = styleInline(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isFenceLine reports whether line opens a fenced code block, storing its
+// fence
+func isFenceLine(line string, fence *string) bool {
+	_, f, _, ok := config.ParseFence(line)
+	if ok {
+		*fence = f
+	}
+	return ok
+}
+
+// styled wraps a non-empty line in an ANSI style
+func styled(code, line string) string {
+	if line == "" {
+		return line
+	}
+	return code + line + ansiReset
+}
+
+// styleInline styles strong and emphasized text outside inline code spans
+func styleInline(line string) string {
+	var builder strings.Builder
+	start := 0
+	for _, span := range append(inlineCodeRegex.FindAllStringIndex(line, -1), []int{len(line), len(line)}) {
+		gap := line[start:span[0]]
+		gap = strongRegex.ReplaceAllString(gap, ansiBold+"$1$2"+ansiReset)
+		gap = starEmphasisRegex.ReplaceAllString(gap, ansiItalic+"$1"+ansiReset)
+		gap = underscoreEmphasisRegex.ReplaceAllString(gap, "$1"+ansiItalic+"$2"+ansiReset+"$3")
+		builder.WriteString(gap)
+		builder.WriteString(line[span[0]:span[1]])
+		start = span[1]
+	}
+	return builder.String()
+}
@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreview(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "headings are bold",
+			markdown: "# Title\n\n## Setup\nText",
+			want:     "\x1b[1m# Title\x1b[0m\n\n\x1b[1m## Setup\x1b[0m\nText",
+		},
+		{
+			name:     "hashtag is not a heading",
+			markdown: "#hashtag",
+			want:     "#hashtag",
+		},
+		{
+			name:     "strong and emphasis",
+			markdown: "Use **gofmt** and __vet__, *always* or _mostly_.",
+			want:     "Use \x1b[1mgofmt\x1b[0m and \x1b[1mvet\x1b[0m, \x1b[3malways\x1b[0m or \x1b[3mmostly\x1b[0m.",
+		},
+		{
+			name:     "snake case and list markers untouched",
+			markdown: "* set max_file_size and min_size",
+			want:     "* set max_file_size and min_size",
+		},
+		{
+			name:     "inline code untouched",
+			markdown: "Run `go test **/*` *now*",
+			want:     "Run `go test **/*` \x1b[3mnow\x1b[0m",
+		},
+		{
+			name:     "code fences dimmed",
+			markdown: "```go\n# not a heading\n**x**\n\n```\nAfter",
+			want:     "\x1b[2m```go\x1b[0m\n\x1b[2m# not a heading\x1b[0m\n\x1b[2m**x**\x1b[0m\n\n\x1b[2m```\x1b[0m\nAfter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Preview(tt.markdown))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// ReplaceRule rewrites every match of a regular expression
+type ReplaceRule struct {
+	// Pattern is the expression to match
+	Pattern *regexp.Regexp
+
+	// Replacement is the text each match becomes; $1 and ${name} expand to
+	// the matched groups
+	Replacement string
+}
+
+// ParseReplaceRule parses a "pattern=>replacement" rule. The pattern must be
+// a valid, non-empty regular expression; the replacement may be empty to
+// delete matches.
+func ParseReplaceRule(spec string) (ReplaceRule, error) {
+	pattern, replacement, ok := strings.Cut(spec, "=>")
+	if !ok {
+		return ReplaceRule{}, fmt.Errorf("invalid replace rule %q: expected pattern=>replacement", spec)
+	}
+	if pattern == "" {
+		return ReplaceRule{}, fmt.Errorf("invalid replace rule %q: empty pattern", spec)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return ReplaceRule{}, fmt.Errorf("invalid replace rule %q: %w", spec, err)
+	}
+	return ReplaceRule{Pattern: re, Replacement: replacement}, nil
+}
+
+// Replace applies rules, in order, to markdown. Fenced code blocks, fences
+// included, are left alone unless inCode is set. Outside code, each run of
+// lines between blocks is rewritten as a whole, so a pattern may span lines.
+func Replace(markdown string, rules []ReplaceRule, inCode bool) string {
+	if len(rules) == 0 {
+		return markdown
+	}
+	if inCode {
+		return replaceAll(markdown, rules)
+	}
+
+	var out, prose []string
+	flush := func() {
+		if prose != nil {
+			out = append(out, strings.Split(replaceAll(strings.Join(prose, "\n"), rules), "\n")...)
+			prose = nil
+		}
+	}
+	fence := ""
+	for _, line := range strings.Split(markdown, "\n") {
+		switch {
+		case fence != "":
+			if config.IsClosingFence(line, fence) {
+				fence = ""
+			}
+			out = append(out, line)
+		case isFenceLine(line, &fence):
+			flush()
+			out = append(out, line)
+		default:
+			prose = append(prose, line)
+		}
+	}
+	flush()
+	return strings.Join(out, "\n")
+}
+
+// replaceAll applies every rule to text in order
+func replaceAll(text string, rules []ReplaceRule) string {
+	for _, rule := range rules {
+		text = rule.Pattern.ReplaceAllString(text, rule.Replacement)
+	}
+	return text
+}
+
+// ApplyReplacements rewrites the section content and headings, merge point
+// defaults, and merge target content of cfg with rules, as Replace does, so
+// text filled into placeholders at generation time is rewritten too
+func ApplyReplacements(cfg *config.Config, rules []ReplaceRule, inCode bool) {
+	if len(rules) == 0 {
+		return
+	}
+	for name, section := range cfg.Sections {
+		section.Content = Replace(section.Content, rules, inCode)
+		section.Heading = Replace(section.Heading, rules, inCode)
+		cfg.Sections[name] = section
+	}
+	for name, point := range cfg.MergePoints {
+		point.Default = Replace(point.Default, rules, inCode)
+		cfg.MergePoints[name] = point
+	}
+	for name, target := range cfg.MergeTargets {
+		target.Content = Replace(target.Content, rules, inCode)
+		cfg.MergeTargets[name] = target
+	}
+}
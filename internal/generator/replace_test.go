@@ -0,0 +1,146 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+func TestParseReplaceRule(t *testing.T) {
+	rule, err := ParseReplaceRule(`npm (\w+)=>pnpm $1`)
+	require.NoError(t, err)
+	assert.Equal(t, `npm (\w+)`, rule.Pattern.String())
+	assert.Equal(t, "pnpm $1", rule.Replacement)
+
+	rule, err = ParseReplaceRule(`TODO:? =>`)
+	require.NoError(t, err)
+	assert.Equal(t, "", rule.Replacement, "an empty replacement deletes matches")
+
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{spec: "no arrow", want: "expected pattern=>replacement"},
+		{spec: "=>x", want: "empty pattern"},
+		{spec: "a(b=>c", want: "missing closing )"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := ParseReplaceRule(tt.spec)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+			assert.Contains(t, err.Error(), tt.spec)
+		})
+	}
+}
+
+func TestReplace(t *testing.T) {
+	rules := func(specs ...string) []ReplaceRule {
+		var parsed []ReplaceRule
+		for _, spec := range specs {
+			rule, err := ParseReplaceRule(spec)
+			require.NoError(t, err)
+			parsed = append(parsed, rule)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name     string
+		markdown string
+		rules    []ReplaceRule
+		inCode   bool
+		want     string
+	}{
+		{
+			name:     "capture groups",
+			markdown: "Run npm install and npm test",
+			rules:    rules(`npm (\w+)=>pnpm $1`),
+			want:     "Run pnpm install and pnpm test",
+		},
+		{
+			name:     "rules apply in order",
+			markdown: "foo",
+			rules:    rules("foo=>bar", "bar=>baz"),
+			want:     "baz",
+		},
+		{
+			name:     "code blocks skipped",
+			markdown: "Use npm\n\n```sh\nnpm install\n```\n\nnpm again",
+			rules:    rules("npm=>pnpm"),
+			want:     "Use pnpm\n\n```sh\nnpm install\n```\n\npnpm again",
+		},
+		{
+			name:     "code blocks rewritten with inCode",
+			markdown: "Use npm\n\n```sh\nnpm install\n```",
+			rules:    rules("npm=>pnpm"),
+			inCode:   true,
+			want:     "Use pnpm\n\n```sh\npnpm install\n```",
+		},
+		{
+			name:     "pattern spans lines outside code",
+			markdown: "first\nsecond\n~~~\nfirst\nsecond\n~~~",
+			rules:    rules(`first\nsecond=>joined`),
+			want:     "joined\n~~~\nfirst\nsecond\n~~~",
+		},
+		{
+			name:     "unclosed fence protects the rest",
+			markdown: "npm\n```\nnpm",
+			rules:    rules("npm=>pnpm"),
+			want:     "pnpm\n```\nnpm",
+		},
+		{
+			name:     "no rules",
+			markdown: "unchanged",
+			want:     "unchanged",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Replace(tt.markdown, tt.rules, tt.inCode))
+		})
+	}
+}
+
+func TestApplyReplacements(t *testing.T) {
+	rule, err := ParseReplaceRule("Acme=>Initech")
+	require.NoError(t, err)
+
+	cfg := &config.Config{Sections: config.Sections{
+		"intro": {Content: "Welcome to Acme", Order: 1},
+		"code":  {Content: "```\nAcme\n```", Order: 2},
+	}}
+	ApplyReplacements(cfg, []ReplaceRule{rule}, false)
+
+	assert.Equal(t, "Welcome to Initech", cfg.Sections["intro"].Content)
+	assert.Equal(t, 1, cfg.Sections["intro"].Order)
+	assert.Equal(t, "```\nAcme\n```", cfg.Sections["code"].Content)
+}
+
+func TestApplyReplacements_MergeTargets(t *testing.T) {
+	rule, err := ParseReplaceRule(`http://internal\.corp=>https://public.example`)
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		Sections: config.Sections{
+			"links": {Order: 1, Heading: "## http://internal.corp", Content: "See {{LINKS}}"},
+		},
+		MergePoints: map[string]config.MergePoint{
+			"links": {Placeholder: "{{LINKS}}", Default: "http://internal.corp/home"},
+		},
+		MergeTargets: map[string]config.MergeTarget{
+			"links": {Strategy: "replace", Content: "http://internal.corp/wiki"},
+		},
+	}
+	ApplyReplacements(cfg, []ReplaceRule{rule}, false)
+
+	assert.Equal(t, "## https://public.example", cfg.Sections["links"].Heading)
+	assert.Equal(t, "https://public.example/home", cfg.MergePoints["links"].Default)
+	output := GenerateMarkdown(cfg)
+	assert.Contains(t, output, "See https://public.example/wiki")
+	assert.NotContains(t, output, "internal.corp")
+}
@@ -0,0 +1,116 @@
+package generator
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// SectionFile is one file of per-section output
+type SectionFile struct {
+	// Name is the file name, without a directory
+	Name string
+
+	// Key is the section key, empty for the index
+	Key string
+
+	// Label is the link text of the file in the index, empty for the index
+	Label string
+
+	Content string
+}
+
+// SectionIndexName is the name of the index file written with per-section
+// output
+const SectionIndexName = "index.md"
+
+// GenerateSectionFiles renders each section of cfg as its own markdown file,
+// in output order, followed by an index holding the metadata and a link to
+// every section file. Files are named <order>-<key>.md, with the order zero
+// padded to three digits and the key passed through config.SanitizeName, so
+// names are the same on every run; a name already taken gets a numeric
+// suffix. Merge targets are applied as in markdown output, and the stamp
+// fields of opts add a stamp comment to each file.
+func GenerateSectionFiles(cfg *config.Config, opts Options) ([]SectionFile, error) {
+	processedConfig := applyMergeTargets(cfg)
+
+	var files []SectionFile
+	used := make(map[string]bool)
+	for _, key := range sortedKeys(processedConfig.Sections) {
+		section := processedConfig.Sections[key]
+
+		name := sectionFileName(section.Order, key, used)
+		used[name] = true
+
+		var builder strings.Builder
+		if opts.Stamp {
+			builder.WriteString(fmt.Sprintf("<!-- %s -->\n\n", stampText(opts.Sources, opts.Version)))
+		}
+		if section.Anchor != "" {
+			builder.WriteString(fmt.Sprintf("<a id=\"%s\"></a>\n", html.EscapeString(section.Anchor)))
+		}
+		builder.WriteString(strings.TrimSpace(section.Content))
+		builder.WriteString("\n")
+
+		files = append(files, SectionFile{Name: name, Key: key, Label: indexLabel(key, section.Heading), Content: builder.String()})
+	}
+
+	index, err := GenerateSectionIndex(cfg.Metadata, files, opts)
+	if err != nil {
+		return nil, err
+	}
+	return append(files, index), nil
+}
+
+// GenerateSectionIndex renders the index of per-section output: the
+// metadata and a link to each of files, in order. GenerateSectionFiles
+// includes it; call it again to leave out files that were not written.
+func GenerateSectionIndex(metadata config.Metadata, files []SectionFile, opts Options) (SectionFile, error) {
+	var index strings.Builder
+	for _, file := range files {
+		index.WriteString(fmt.Sprintf("- [%s](%s)\n", file.Label, file.Name))
+	}
+
+	header, err := GenerateMetadataMarkdown(metadata)
+	if err != nil {
+		return SectionFile{}, err
+	}
+	var builder strings.Builder
+	if opts.Stamp {
+		builder.WriteString(fmt.Sprintf("<!-- %s -->\n\n", stampText(opts.Sources, opts.Version)))
+	}
+	builder.WriteString(header)
+	if index.Len() > 0 {
+		if header != "" {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(index.String())
+	}
+	return SectionFile{Name: SectionIndexName, Content: builder.String()}, nil
+}
+
+// indexLabel returns the index link text for a section: the text of its
+// original heading, or its key if it has none
+func indexLabel(key, heading string) string {
+	if match := config.HeadingRegex.FindStringSubmatch(heading); match != nil && match[2] != "" {
+		return match[2]
+	}
+	return key
+}
+
+// sectionFileName returns the file name for a section, unique among used
+func sectionFileName(order int, key string, used map[string]bool) string {
+	base := config.SanitizeName(key)
+	if base == "" {
+		base = "section"
+	}
+	base = fmt.Sprintf("%03d-%s", order, base)
+
+	name := base + ".md"
+	for n := 2; used[name]; n++ {
+		name = fmt.Sprintf("%s_%d.md", base, n)
+	}
+	return name
+}
@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSectionFiles(t *testing.T) {
+	cfg := &config.Config{
+		Metadata: config.Metadata{Title: "Guide"},
+		Sections: map[string]config.Section{
+			"Testing Rules": {Order: 2, Content: "## Testing\n<!-- COMMANDS -->\n"},
+			"intro":         {Order: 1, Content: "# Intro", Anchor: "start"},
+			"testing-rules": {Order: 2, Content: "## More testing"},
+			"!!!":           {Order: 10, Content: "Symbols"},
+		},
+		MergePoints:  map[string]config.MergePoint{"commands": {Placeholder: "<!-- COMMANDS -->"}},
+		MergeTargets: map[string]config.MergeTarget{"commands": {Strategy: "replace", Content: "go test ./..."}},
+	}
+
+	files, err := GenerateSectionFiles(cfg, Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []SectionFile{
+		{Name: "001-intro.md", Key: "intro", Label: "intro", Content: "<a id=\"start\"></a>\n# Intro\n"},
+		{Name: "002-testing_rules.md", Key: "Testing Rules", Label: "Testing Rules", Content: "## Testing\ngo test ./...\n"},
+		{Name: "002-testing_rules_2.md", Key: "testing-rules", Label: "testing-rules", Content: "## More testing\n"},
+		{Name: "010-section.md", Key: "!!!", Label: "!!!", Content: "Symbols\n"},
+		{Name: "index.md", Content: "---\ntitle: Guide\n---\n\n# Guide\n\n" +
+			"- [intro](001-intro.md)\n" +
+			"- [Testing Rules](002-testing_rules.md)\n" +
+			"- [testing-rules](002-testing_rules_2.md)\n" +
+			"- [!!!](010-section.md)\n"},
+	}, files)
+}
+
+func TestGenerateSectionFiles_Heading(t *testing.T) {
+	cfg := &config.Config{
+		Sections: map[string]config.Section{
+			"header_2_strict_requirements": {Order: 1, Content: "No exceptions.", Heading: "## STRICT REQUIREMENTS!!"},
+		},
+	}
+
+	files, err := GenerateSectionFiles(cfg, Options{})
+	require.NoError(t, err)
+
+	require.Len(t, files, 2)
+	assert.Equal(t, "## STRICT REQUIREMENTS!!\nNo exceptions.\n", files[0].Content)
+	assert.Equal(t, "- [STRICT REQUIREMENTS!!](001-header_2_strict_requirements.md)\n", files[1].Content)
+}
+
+func TestGenerateSectionIndex(t *testing.T) {
+	files := []SectionFile{
+		{Name: "001-intro.md", Key: "intro", Label: "Intro"},
+		{Name: "002-testing.md", Key: "testing", Label: "Testing"},
+	}
+
+	index, err := GenerateSectionIndex(config.Metadata{}, files[1:], Options{})
+	require.NoError(t, err)
+	assert.Equal(t, SectionFile{Name: "index.md", Content: "- [Testing](002-testing.md)\n"}, index)
+
+	index, err = GenerateSectionIndex(config.Metadata{Title: "Guide"}, nil, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "---\ntitle: Guide\n---\n\n# Guide\n", index.Content)
+}
+
+func TestGenerateSectionFiles_Stamp(t *testing.T) {
+	cfg := &config.Config{Sections: map[string]config.Section{"intro": {Order: 1, Content: "# Intro"}}}
+
+	files, err := GenerateSectionFiles(cfg, Options{Stamp: true, Sources: []string{"a.md"}, Version: "1.0.0"})
+	require.NoError(t, err)
+
+	require.Len(t, files, 2)
+	stamp := "<!-- Generated by claude-merge 1.0.0 from: a.md. Do not edit. -->\n\n"
+	assert.Equal(t, stamp+"# Intro\n", files[0].Content)
+	assert.Equal(t, stamp+"- [intro](001-intro.md)\n", files[1].Content)
+}
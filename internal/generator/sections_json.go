@@ -0,0 +1,47 @@
+package generator
+
+import (
+	"encoding/json"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// SectionsDocument is the sections-json output: the merged title and every
+// section as a flat list in output order
+type SectionsDocument struct {
+	Title    string         `json:"title"`
+	Sections []SectionEntry `json:"sections"`
+}
+
+// SectionEntry is one rendered section of a SectionsDocument
+type SectionEntry struct {
+	Key      string          `json:"key"`
+	Order    int             `json:"order"`
+	Content  string          `json:"content"`
+	Priority config.Priority `json:"priority"`
+	Source   string          `json:"source,omitempty"`
+}
+
+// GenerateSectionsJSON renders cfg as an indented SectionsDocument. Merge
+// targets are applied and sections are ordered exactly as in markdown output.
+// provenance maps section keys to their source file and may be nil.
+func GenerateSectionsJSON(cfg *config.Config, provenance map[string]string) ([]byte, error) {
+	processedConfig := applyMergeTargets(cfg)
+
+	doc := SectionsDocument{
+		Title:    cfg.Metadata.Title,
+		Sections: []SectionEntry{},
+	}
+	for _, key := range sortedKeys(processedConfig.Sections) {
+		section := processedConfig.Sections[key]
+		doc.Sections = append(doc.Sections, SectionEntry{
+			Key:      key,
+			Order:    section.Order,
+			Content:  section.Content,
+			Priority: section.Priority,
+			Source:   provenance[key],
+		})
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSectionsJSON(t *testing.T) {
+	cfg := &config.Config{
+		Metadata: config.Metadata{Title: "Guidelines"},
+		Sections: map[string]config.Section{
+			"b":     {Order: 1, Content: "B"},
+			"a":     {Order: 1, Content: "A"},
+			"first": {Order: 0, Content: "Use <!-- MERGE:tool -->", Priority: config.NewExplicitPriority(5)},
+		},
+		MergePoints: map[string]config.MergePoint{
+			"tool": {Placeholder: "<!-- MERGE:tool -->"},
+		},
+		MergeTargets: map[string]config.MergeTarget{
+			"tool": {Strategy: "replace", Content: "gofmt"},
+		},
+	}
+
+	data, err := GenerateSectionsJSON(cfg, map[string]string{"first": "base.toml", "a": "team.toml"})
+	require.NoError(t, err)
+
+	var doc SectionsDocument
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, SectionsDocument{
+		Title: "Guidelines",
+		Sections: []SectionEntry{
+			{Key: "first", Order: 0, Content: "Use gofmt", Priority: config.NewExplicitPriority(5), Source: "base.toml"},
+			{Key: "a", Order: 1, Content: "A", Source: "team.toml"},
+			{Key: "b", Order: 1, Content: "B"},
+		},
+	}, doc)
+	assert.Contains(t, string(data), `"type": "explicit"`)
+}
+
+func TestGenerateSectionsJSON_Empty(t *testing.T) {
+	data, err := GenerateSectionsJSON(&config.Config{}, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"title": "", "sections": []}`, string(data))
+}
@@ -0,0 +1,30 @@
+package generator
+
+import (
+	"strings"
+	"time"
+)
+
+// TimestampMarker introduces the timestamp footer. Everything from the marker
+// on changes with each run, so comparisons of generated output should drop it
+// with StripTimestampFooter.
+const TimestampMarker = "<!-- claude-merge:timestamp -->"
+
+// timestampFooter renders the footer for generation time t, formatted with
+// layout, or time.RFC3339 if layout is empty
+func timestampFooter(t time.Time, layout string) string {
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	return TimestampMarker + "\n_Generated on " + t.Format(layout) + "_"
+}
+
+// StripTimestampFooter returns markdown without its timestamp footer, if any,
+// so output generated at different times compares equal
+func StripTimestampFooter(markdown string) string {
+	i := strings.LastIndex(markdown, TimestampMarker)
+	if i < 0 {
+		return markdown
+	}
+	return strings.TrimRight(markdown[:i], "\n")
+}
@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateMarkdown_TimestampFooter(t *testing.T) {
+	cfg := &config.Config{
+		Sections: map[string]config.Section{
+			"late":  {Order: 1000, Content: "# Late"},
+			"intro": {Order: 1, Content: "# Intro"},
+		},
+	}
+	generated := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		layout string
+		want   string
+	}{
+		{name: "RFC3339 by default", want: "_Generated on 2025-01-02T15:04:05Z_"},
+		{name: "custom layout", layout: "2006-01-02", want: "_Generated on 2025-01-02_"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.Timestamp = generated
+			opts.TimestampLayout = tt.layout
+
+			got := GenerateMarkdownWithOptions(cfg, opts)
+			assert.Contains(t, got, "# Late\n\n"+TimestampMarker+"\n"+tt.want)
+			assert.Equal(t, tt.want, got[len(got)-len(tt.want):])
+		})
+	}
+
+	assert.NotContains(t, GenerateMarkdown(cfg), TimestampMarker)
+}
+
+func TestStripTimestampFooter(t *testing.T) {
+	cfg := &config.Config{Sections: map[string]config.Section{"intro": {Content: "# Intro"}}}
+	opts := DefaultOptions()
+
+	plain := GenerateMarkdownWithOptions(cfg, opts)
+	opts.Timestamp = time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	first := GenerateMarkdownWithOptions(cfg, opts)
+	opts.Timestamp = opts.Timestamp.Add(time.Hour)
+	second := GenerateMarkdownWithOptions(cfg, opts)
+
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, StripTimestampFooter(first), StripTimestampFooter(second))
+	assert.Equal(t, plain, StripTimestampFooter(first))
+	assert.Equal(t, plain, StripTimestampFooter(plain))
+}
@@ -0,0 +1,27 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// RenderTitle executes the Go template text over metadata to produce the
+// document's top heading, such as "{{.Title}} — {{.Language}}". Whitespace,
+// including newlines, collapses to single spaces, and an empty result means
+// no heading.
+func RenderTitle(text string, metadata config.Metadata) (string, error) {
+	tmpl, err := template.New("title").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid title template: %w", err)
+	}
+
+	var builder strings.Builder
+	err = tmpl.Execute(&builder, metadata)
+	if err != nil {
+		return "", fmt.Errorf("invalid title template: %w", err)
+	}
+	return strings.Join(strings.Fields(builder.String()), " "), nil
+}
@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTitle(t *testing.T) {
+	metadata := config.Metadata{Title: "Guide", Language: "go", Version: "1.0"}
+
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		{name: "title", tmpl: "{{.Title}}", want: "Guide"},
+		{name: "composed", tmpl: "{{.Title}} — {{.Language}}", want: "Guide — go"},
+		{name: "conditional", tmpl: "{{if .Description}}{{.Description}}{{end}}", want: ""},
+		{name: "whitespace collapses", tmpl: "  {{.Title}}\n  v{{.Version}} ", want: "Guide v1.0"},
+		{name: "unknown field", tmpl: "{{.Nope}}", wantErr: true},
+		{name: "syntax error", tmpl: "{{.Title", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderTitle(tt.tmpl, metadata)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "invalid title template")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGenerateMarkdownWithOptions_Heading(t *testing.T) {
+	cfg := &config.Config{
+		Metadata: config.Metadata{Title: "Guide"},
+		Sections: map[string]config.Section{"intro": {Order: 1, Content: "Intro"}},
+	}
+
+	opts := DefaultOptions()
+	opts.Heading = "Guide — go"
+	assert.Equal(t, "<!-- Generated by claude-merge -->\n<!-- Title: Guide -->\n\n# Guide — go\n\nIntro",
+		GenerateMarkdownWithOptions(cfg, opts))
+
+	assert.NotContains(t, GenerateMarkdown(cfg), "# Guide")
+}
@@ -0,0 +1,81 @@
+// Package gitinfo looks up the git revisions that input files come from, so
+// generated output can be tied back to exact sources.
+package gitinfo
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// shortLength is the number of hex digits kept by Short
+const shortLength = 12
+
+// Info describes a file tracked by git
+type Info struct {
+	// Head is the commit checked out in the repository holding the file
+	Head string `json:"head"`
+
+	// Commit is the last commit that changed the file
+	Commit string `json:"commit"`
+
+	// Path is the file's path relative to the repository root
+	Path string `json:"path"`
+}
+
+// String describes the file as path@commit, with the commit shortened
+func (i Info) String() string {
+	return i.Path + "@" + Short(i.Commit)
+}
+
+// Short abbreviates a commit hash
+func Short(hash string) string {
+	if len(hash) > shortLength {
+		return hash[:shortLength]
+	}
+	return hash
+}
+
+// Lookup returns the git information for file. It reports false when git is
+// not installed, the file is not in a repository, or the file has never been
+// committed; callers then simply go without.
+func Lookup(file string) (Info, bool) {
+	dir := filepath.Dir(file)
+	name := filepath.Base(file)
+
+	head, ok := git(dir, "rev-parse", "HEAD")
+	if !ok {
+		return Info{}, false
+	}
+	path, ok := git(dir, "ls-files", "--full-name", "--error-unmatch", "--", name)
+	if !ok {
+		return Info{}, false
+	}
+	commit, ok := git(dir, "log", "-1", "--format=%H", "--", name)
+	if !ok || commit == "" {
+		return Info{}, false
+	}
+
+	return Info{Head: head, Commit: commit, Path: path}, true
+}
+
+// LookupAll looks up each file, leaving out those without git information
+func LookupAll(files []string) map[string]Info {
+	infos := make(map[string]Info, len(files))
+	for _, file := range files {
+		info, ok := Lookup(file)
+		if ok {
+			infos[file] = info
+		}
+	}
+	return infos
+}
+
+// git runs a git command in dir and returns its trimmed output
+func git(dir string, args ...string) (string, bool) {
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(out)), true
+}
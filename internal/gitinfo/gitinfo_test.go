@@ -0,0 +1,68 @@
+package gitinfo
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// initRepo creates a repository in a temporary directory with one committed
+// file, docs/base.md, and one untracked file, skipping if git is missing
+func initRepo(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "docs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "base.md"), []byte("# Base\n"), 0644))
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "docs/base.md"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "base"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "draft.md"), []byte("# Draft\n"), 0644))
+	return dir
+}
+
+func TestLookup(t *testing.T) {
+	dir := initRepo(t)
+
+	info, ok := Lookup(filepath.Join(dir, "docs", "base.md"))
+	require.True(t, ok)
+	assert.Equal(t, "docs/base.md", info.Path)
+	assert.Len(t, info.Head, 40)
+	assert.Equal(t, info.Head, info.Commit)
+	assert.Equal(t, "docs/base.md@"+info.Commit[:12], info.String())
+
+	_, ok = Lookup(filepath.Join(dir, "docs", "draft.md"))
+	assert.False(t, ok, "untracked files have no git information")
+
+	_, ok = Lookup(filepath.Join(t.TempDir(), "outside.md"))
+	assert.False(t, ok, "files outside a repository have no git information")
+}
+
+func TestLookupAll(t *testing.T) {
+	dir := initRepo(t)
+	base := filepath.Join(dir, "docs", "base.md")
+
+	infos := LookupAll([]string{base, filepath.Join(dir, "docs", "draft.md")})
+	assert.Len(t, infos, 1)
+	assert.Equal(t, "docs/base.md", infos[base].Path)
+}
+
+func TestShort(t *testing.T) {
+	assert.Equal(t, "0123456789ab", Short("0123456789abcdef"))
+	assert.Equal(t, "abc", Short("abc"))
+}
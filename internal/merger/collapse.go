@@ -0,0 +1,90 @@
+package merger
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// SectionCollapse folds every merged section whose key matches Pattern into
+// a single section stored under Target
+type SectionCollapse struct {
+	Pattern *regexp.Regexp
+	Target  string
+}
+
+// ParseSectionCollapse parses a "pattern=target" rule. The pattern is a
+// regular expression that must match the whole section key.
+func ParseSectionCollapse(spec string) (SectionCollapse, error) {
+	pattern, target, found := strings.Cut(spec, "=")
+	pattern = strings.TrimSpace(pattern)
+	target = strings.TrimSpace(target)
+	if !found || pattern == "" || target == "" {
+		return SectionCollapse{}, fmt.Errorf("collapse rule %q must be written as pattern=target", spec)
+	}
+
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return SectionCollapse{}, fmt.Errorf("collapse rule %q: %w", spec, err)
+	}
+	return SectionCollapse{Pattern: re, Target: target}, nil
+}
+
+// applyCollapses combines the sections matched by each of m.Collapse, in
+// section order, using m.CollapseStrategy. A section already stored under the
+// target key takes part even if the pattern doesn't match it.
+func (m *PriorityMerger) applyCollapses(result *config.Config) {
+	strategy := m.CollapseStrategy
+	if strategy == "" {
+		strategy = StrategyAppend
+	}
+
+	for _, rule := range m.Collapse {
+		matched := make(map[string]config.Section)
+		for key, section := range result.Sections {
+			if key == rule.Target || rule.Pattern.MatchString(key) {
+				matched[key] = section
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		keys := config.SortedSectionKeys(matched)
+
+		combined := result.Sections[keys[0]]
+		var sources []string
+		for i, key := range keys {
+			section := result.Sections[key]
+			source := m.stats.Provenance[key]
+			if i > 0 {
+				combined.Content = ApplyStrategyFrom(strategy, combined.Content, section.Content, source)
+				combined.MergePoints = unionStrings(combined.MergePoints, section.MergePoints)
+			}
+			if source != "" && !containsString(sources, source) {
+				sources = append(sources, source)
+			}
+			if m.Debug {
+				m.debugf("Collapsing section %s into %s\n", key, rule.Target)
+			}
+			delete(result.Sections, key)
+			delete(m.stats.Provenance, key)
+		}
+
+		result.Sections[rule.Target] = combined
+		if len(sources) > 0 {
+			m.stats.Provenance[rule.Target] = strings.Join(sources, ", ")
+		}
+	}
+}
+
+// containsString reports whether values holds value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
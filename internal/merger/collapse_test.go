@@ -0,0 +1,85 @@
+package merger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSectionCollapse(t *testing.T) {
+	rule, err := ParseSectionCollapse(" changelog_.* = changelog ")
+	require.NoError(t, err)
+	assert.Equal(t, "changelog", rule.Target)
+	assert.True(t, rule.Pattern.MatchString("changelog_20240101"))
+	assert.False(t, rule.Pattern.MatchString("old_changelog_20240101"), "pattern must match the whole key")
+
+	for _, spec := range []string{"changelog_.*", "=changelog", "changelog_.*=", "changelog_(=changelog"} {
+		_, err := ParseSectionCollapse(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestPriorityMerger_Collapse(t *testing.T) {
+	rule, err := ParseSectionCollapse("changelog_.*=changelog")
+	require.NoError(t, err)
+
+	newConfigs := func() []*config.Config {
+		return []*config.Config{
+			{SourceFile: "a.toml", Sections: map[string]config.Section{
+				"intro":              {Order: 1, Content: "Intro"},
+				"changelog_20240315": {Order: 3, Content: "- March"},
+			}},
+			{SourceFile: "b.toml", Sections: map[string]config.Section{
+				"changelog_20240101": {Order: 2, Content: "- January", MergePoints: []string{"notes"}},
+			}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		strategy MergeStrategy
+		want     string
+	}{
+		{name: "default appends", want: "- January\n- March"},
+		{name: "prepend", strategy: StrategyPrepend, want: "- March\n- January"},
+		{name: "replace", strategy: StrategyReplace, want: "- March"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewPriorityMerger(false)
+			m.Collapse = []SectionCollapse{rule}
+			m.CollapseStrategy = tt.strategy
+
+			merged, err := m.MergeAllResult(context.Background(), newConfigs())
+			require.NoError(t, err)
+
+			sections := merged.Config.Sections
+			assert.Len(t, sections, 2)
+			assert.Equal(t, tt.want, sections["changelog"].Content)
+			assert.Equal(t, 2, sections["changelog"].Order)
+			assert.Equal(t, []string{"notes"}, sections["changelog"].MergePoints)
+			assert.Equal(t, "b.toml, a.toml", merged.Provenance["changelog"])
+		})
+	}
+}
+
+func TestPriorityMerger_Collapse_IncludesTarget(t *testing.T) {
+	rule, err := ParseSectionCollapse("changelog_.*=changelog")
+	require.NoError(t, err)
+
+	m := NewPriorityMerger(false)
+	m.Collapse = []SectionCollapse{rule}
+
+	result, err := m.MergeAll([]*config.Config{{Sections: map[string]config.Section{
+		"changelog":          {Order: 1, Content: "## Changelog"},
+		"changelog_20240101": {Order: 2, Content: "- January"},
+	}}})
+	require.NoError(t, err)
+	assert.Equal(t, config.Sections{
+		"changelog": {Order: 1, Content: "## Changelog\n- January"},
+	}, result.Sections)
+}
@@ -0,0 +1,94 @@
+package merger
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// sectionChunk is one file's contribution to a section combined across files
+type sectionChunk struct {
+	content  string
+	source   string
+	strategy MergeStrategy
+	priority config.Priority
+}
+
+// combines reports whether an incoming section is combined with the
+// existing one instead of competing with it: the incoming section sets a
+// strategy that keeps existing content, or a registered one, and neither
+// side has an explicit priority, since explicit priorities always override
+func combines(existing, incoming config.Section) bool {
+	switch strategy := MergeStrategy(incoming.Strategy); strategy {
+	case StrategyAppend, StrategyPrepend, StrategyCollapse, StrategyKeyValue:
+	case StrategyReplace, "":
+		return false
+	default:
+		if !strategy.IsValid() {
+			return false
+		}
+	}
+	return !incoming.Final &&
+		existing.Priority.Type != config.PriorityExplicit &&
+		incoming.Priority.Type != config.PriorityExplicit
+}
+
+// combineSection adds the incoming section to the chunks of the section
+// stored under name and rebuilds its content from them. The first chunk is
+// the existing section.
+func (m *PriorityMerger) combineSection(result *config.Config, name string, incoming config.Section, source string) {
+	existing := result.Sections[name]
+	chunks := m.chunks[name]
+	if len(chunks) == 0 {
+		chunks = []sectionChunk{{
+			content:  existing.Content,
+			source:   m.stats.Provenance[name],
+			strategy: MergeStrategy(existing.Strategy),
+			priority: existing.Priority,
+		}}
+	}
+	chunks = append(chunks, sectionChunk{
+		content:  incoming.Content,
+		source:   source,
+		strategy: MergeStrategy(incoming.Strategy),
+		priority: incoming.Priority,
+	})
+	m.chunks[name] = chunks
+
+	combined := existing
+	combined.Content = combineChunks(chunks)
+	if incoming.Priority.TakesPrecedenceOver(combined.Priority) {
+		combined.Priority = incoming.Priority
+	}
+	combined.MergePoints = unionStrings(existing.MergePoints, incoming.MergePoints)
+	result.Sections[name] = combined
+
+	var sources []string
+	for _, chunk := range chunks {
+		if chunk.source != "" && !containsString(sources, chunk.source) {
+			sources = append(sources, chunk.source)
+		}
+	}
+	m.stats.Provenance[name] = strings.Join(sources, ", ")
+}
+
+// combineChunks orders chunks by descending priority, keeping file order
+// among equal priorities, and joins each chunk to the ones before it with
+// the chunk's own strategy
+func combineChunks(chunks []sectionChunk) string {
+	ordered := append([]sectionChunk(nil), chunks...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].priority.TakesPrecedenceOver(ordered[j].priority)
+	})
+
+	content := ordered[0].content
+	for _, chunk := range ordered[1:] {
+		strategy := chunk.strategy
+		if strategy == "" || strategy == StrategyReplace {
+			strategy = StrategyAppend
+		}
+		content = ApplyStrategyFrom(strategy, content, chunk.content, chunk.source)
+	}
+	return content
+}
@@ -0,0 +1,91 @@
+package merger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fragment returns a config holding one rules section
+func fragment(source, content, strategy string, priority config.Priority) *config.Config {
+	return &config.Config{
+		SourceFile: source,
+		Sections: map[string]config.Section{
+			"rules": {Order: 1, Content: content, Strategy: strategy, Priority: priority},
+		},
+	}
+}
+
+func TestPriorityMerger_CombineByRelativePriority(t *testing.T) {
+	configs := []*config.Config{
+		fragment("base.md", "- base", "", config.Priority{}),
+		fragment("low.toml", "- low", "append", config.NewRelativePriority(1)),
+		fragment("high.toml", "- high", "append", config.NewRelativePriority(9)),
+		fragment("mid-a.toml", "- mid a", "append", config.NewRelativePriority(5)),
+		fragment("mid-b.toml", "- mid b", "append", config.NewRelativePriority(5)),
+	}
+
+	m := NewPriorityMerger(false)
+	result, err := m.MergeAllResult(context.Background(), configs)
+	require.NoError(t, err)
+
+	rules := result.Config.Sections["rules"]
+	assert.Equal(t, "- high\n- mid a\n- mid b\n- low\n- base", rules.Content, "highest priority first, file order among equals")
+	assert.Equal(t, config.NewRelativePriority(9), rules.Priority)
+	assert.Equal(t, "base.md, low.toml, high.toml, mid-a.toml, mid-b.toml", result.Provenance["rules"])
+}
+
+func TestPriorityMerger_CombinePrepend(t *testing.T) {
+	configs := []*config.Config{
+		fragment("base.md", "- base", "", config.NewRelativePriority(5)),
+		fragment("note.toml", "- note", "prepend", config.NewRelativePriority(1)),
+	}
+
+	result, err := NewPriorityMerger(false).MergeAll(configs)
+	require.NoError(t, err)
+
+	assert.Equal(t, "- note\n- base", result.Sections["rules"].Content, "each chunk joins the higher-priority ones with its own strategy")
+}
+
+func TestPriorityMerger_CombineExplicitOverrides(t *testing.T) {
+	configs := []*config.Config{
+		fragment("base.md", "- base", "", config.Priority{}),
+		fragment("extra.toml", "- extra", "append", config.NewRelativePriority(3)),
+		fragment("policy.toml", "- policy", "", config.NewExplicitPriority(1)),
+		fragment("late.toml", "- late", "append", config.NewRelativePriority(9)),
+	}
+
+	result, err := NewPriorityMerger(false).MergeAll(configs)
+	require.NoError(t, err)
+
+	assert.Equal(t, "- policy", result.Sections["rules"].Content, "explicit priority replaces combined content and is never combined with")
+}
+
+func TestPriorityMerger_CombineRestartsAfterOverride(t *testing.T) {
+	configs := []*config.Config{
+		fragment("base.md", "- base", "", config.Priority{}),
+		fragment("extra.toml", "- extra", "append", config.NewRelativePriority(1)),
+		fragment("reset.toml", "- reset", "", config.NewRelativePriority(2)),
+		fragment("more.toml", "- more", "append", config.NewRelativePriority(1)),
+	}
+
+	result, err := NewPriorityMerger(false).MergeAll(configs)
+	require.NoError(t, err)
+
+	assert.Equal(t, "- reset\n- more", result.Sections["rules"].Content)
+}
+
+func TestCombines(t *testing.T) {
+	relative := config.Section{Strategy: "append", Priority: config.NewRelativePriority(1)}
+
+	assert.True(t, combines(config.Section{}, relative))
+	assert.True(t, combines(config.Section{}, config.Section{Strategy: "keyvalue"}))
+	assert.False(t, combines(config.Section{}, config.Section{Strategy: "replace"}))
+	assert.False(t, combines(config.Section{}, config.Section{}))
+	assert.False(t, combines(config.Section{Priority: config.NewExplicitPriority(1)}, relative))
+	assert.False(t, combines(config.Section{}, config.Section{Strategy: "append", Priority: config.NewExplicitPriority(1)}))
+	assert.False(t, combines(config.Section{}, config.Section{Strategy: "append", Final: true}))
+}
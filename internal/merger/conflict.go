@@ -0,0 +1,32 @@
+package merger
+
+import "github.com/arustydev/claude-merge/internal/config"
+
+// Resolution is a ConflictResolver's decision about a section collision
+type Resolution int
+
+const (
+	// ResolveAuto applies the merger's normal equal-priority rules
+	ResolveAuto Resolution = iota
+
+	// KeepExisting keeps the section merged so far and drops the incoming one
+	KeepExisting
+
+	// KeepIncoming replaces the section merged so far with the incoming one
+	KeepIncoming
+
+	// AppendBoth keeps both, with the incoming content after the existing
+	AppendBoth
+)
+
+// Conflict describes two sections of equal priority competing for one key
+type Conflict struct {
+	Section        string
+	Existing       config.Section
+	ExistingSource string
+	Incoming       config.Section
+	IncomingSource string
+}
+
+// ConflictResolver decides how a section conflict is settled
+type ConflictResolver func(conflict Conflict) (Resolution, error)
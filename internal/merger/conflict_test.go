@@ -0,0 +1,96 @@
+package merger
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityMerger_ResolveConflict(t *testing.T) {
+	newConfigs := func(secondPriority config.Priority, secondContent string) []*config.Config {
+		return []*config.Config{
+			{
+				SourceFile: "a.toml",
+				Sections: map[string]config.Section{
+					"intro": {Order: 1, Content: "First intro"},
+				},
+			},
+			{
+				SourceFile: "b.toml",
+				Sections: map[string]config.Section{
+					"intro": {Order: 1, Content: secondContent, Priority: secondPriority},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		resolution Resolution
+		want       string
+		wantSource string
+	}{
+		{name: "auto", resolution: ResolveAuto, want: "Second intro", wantSource: "b.toml"},
+		{name: "keep existing", resolution: KeepExisting, want: "First intro", wantSource: "a.toml"},
+		{name: "keep incoming", resolution: KeepIncoming, want: "Second intro", wantSource: "b.toml"},
+		{name: "append both", resolution: AppendBoth, want: "First intro\n\nSecond intro", wantSource: "b.toml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Conflict
+			m := NewPriorityMerger(false)
+			m.ResolveConflict = func(conflict Conflict) (Resolution, error) {
+				got = append(got, conflict)
+				return tt.resolution, nil
+			}
+
+			result, err := m.MergeAllResult(context.Background(), newConfigs(config.Priority{}, "Second intro"))
+			require.NoError(t, err)
+
+			require.Len(t, got, 1)
+			assert.Equal(t, "intro", got[0].Section)
+			assert.Equal(t, "a.toml", got[0].ExistingSource)
+			assert.Equal(t, "b.toml", got[0].IncomingSource)
+			assert.Equal(t, "First intro", got[0].Existing.Content)
+			assert.Equal(t, "Second intro", got[0].Incoming.Content)
+
+			assert.Equal(t, tt.want, result.Config.Sections["intro"].Content)
+			assert.Equal(t, tt.wantSource, result.Provenance["intro"])
+		})
+	}
+
+	t.Run("not called without a genuine conflict", func(t *testing.T) {
+		calls := 0
+		m := NewPriorityMerger(false)
+		m.ResolveConflict = func(Conflict) (Resolution, error) {
+			calls++
+			return KeepExisting, nil
+		}
+
+		merged, err := m.MergeAll(newConfigs(config.NewExplicitPriority(1), "Second intro"))
+		require.NoError(t, err)
+		assert.Equal(t, "Second intro", merged.Sections["intro"].Content)
+
+		merged, err = m.MergeAll(newConfigs(config.Priority{}, "First intro"))
+		require.NoError(t, err)
+		assert.Equal(t, "First intro", merged.Sections["intro"].Content)
+
+		assert.Zero(t, calls)
+	})
+
+	t.Run("resolver error", func(t *testing.T) {
+		m := NewPriorityMerger(false)
+		m.ResolveConflict = func(Conflict) (Resolution, error) {
+			return ResolveAuto, errors.New("interrupted")
+		}
+
+		_, err := m.MergeAll(newConfigs(config.Priority{}, "Second intro"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "resolving section intro: interrupted")
+	})
+}
@@ -0,0 +1,74 @@
+package merger
+
+import (
+	"reflect"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// mergeExtra combines the Metadata.Extra fields of configs into a new map.
+// Keys listed in m.UnionKeys collect the values of every config, in config
+// order: lists are flattened, a single value counts as a one-item list, and
+// repeated items keep only their first position. Every other key takes the
+// value from the config with the highest metadata priority, with ties
+// settled by EqualPriority. It returns nil when no config has extras.
+func (m *PriorityMerger) mergeExtra(configs []*config.Config) map[string]interface{} {
+	var extra map[string]interface{}
+	priorities := make(map[string]config.Priority)
+	for _, cfg := range configs {
+		for key, value := range cfg.Metadata.Extra {
+			if extra == nil {
+				extra = make(map[string]interface{})
+			}
+
+			if containsString(m.UnionKeys, key) {
+				union, _ := extra[key].([]interface{})
+				for _, item := range extraItems(value) {
+					if !containsItem(union, item) {
+						union = append(union, item)
+					}
+				}
+				extra[key] = union
+				continue
+			}
+
+			existing, exists := priorities[key]
+			if !exists || m.wins(cfg.Metadata.Priority, existing) {
+				if m.Debug {
+					m.debugf("Merging metadata field %s from %s\n", key, cfg.SourceFile)
+				}
+				extra[key] = value
+				priorities[key] = cfg.Metadata.Priority
+			}
+		}
+	}
+	return extra
+}
+
+// extraItems returns value as a list of items
+func extraItems(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	case []string:
+		items := make([]interface{}, len(v))
+		for i, s := range v {
+			items[i] = s
+		}
+		return items
+	default:
+		return []interface{}{value}
+	}
+}
+
+// containsItem reports whether items holds an item equal to item
+func containsItem(items []interface{}, item interface{}) bool {
+	for _, existing := range items {
+		if reflect.DeepEqual(existing, item) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package merger
+
+import (
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeExtra(t *testing.T) {
+	base := &config.Config{
+		SourceFile: "common.md",
+		Metadata: config.Metadata{
+			Priority: config.NewExplicitPriority(1),
+			Extra: map[string]interface{}{
+				"tags":    []interface{}{"go", "style"},
+				"authors": "ann",
+				"owner":   "team-a",
+			},
+		},
+	}
+	lang := &config.Config{
+		SourceFile: "go.md",
+		Metadata: config.Metadata{
+			Priority: config.NewExplicitPriority(5),
+			Extra: map[string]interface{}{
+				"tags":    []interface{}{"style", "testing"},
+				"authors": []interface{}{"bob", "ann"},
+				"owner":   "team-b",
+			},
+		},
+	}
+	low := &config.Config{
+		SourceFile: "extra.md",
+		Metadata: config.Metadata{
+			Priority: config.NewExplicitPriority(0),
+			Extra:    map[string]interface{}{"owner": "team-c", "reviewed": true},
+		},
+	}
+
+	m := NewPriorityMergerWithOptions(Options{UnionKeys: []string{"tags", "authors"}})
+	extra := m.mergeExtra([]*config.Config{base, lang, low})
+
+	assert.Equal(t, map[string]interface{}{
+		"tags":     []interface{}{"go", "style", "testing"},
+		"authors":  []interface{}{"ann", "bob"},
+		"owner":    "team-b",
+		"reviewed": true,
+	}, extra)
+}
+
+func TestMergeExtra_PriorityPicked(t *testing.T) {
+	first := &config.Config{Metadata: config.Metadata{Extra: map[string]interface{}{"tags": []interface{}{"a"}}}}
+	second := &config.Config{Metadata: config.Metadata{Extra: map[string]interface{}{"tags": []interface{}{"b"}}}}
+
+	m := NewPriorityMerger(false)
+	assert.Equal(t, map[string]interface{}{"tags": []interface{}{"b"}}, m.mergeExtra([]*config.Config{first, second}))
+
+	m.EqualPriority = EqualPriorityFirst
+	assert.Equal(t, map[string]interface{}{"tags": []interface{}{"a"}}, m.mergeExtra([]*config.Config{first, second}))
+
+	assert.Nil(t, m.mergeExtra([]*config.Config{{}}))
+}
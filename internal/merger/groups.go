@@ -0,0 +1,113 @@
+package merger
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// ConfigGroup is a named set of configs merged on their own before being
+// merged with other groups, such as the files of one topic directory
+type ConfigGroup struct {
+	Name    string
+	Configs []*config.Config
+}
+
+// MergeGroupsResult merges in two levels: the configs of each group are
+// merged on their own, in order, and the per-group results are then merged
+// in group order. Priorities carry across both levels. A section that wins
+// inside its group keeps its own priority and competes with the winners of
+// the other groups, so a higher priority beats a later group; equal
+// priorities are settled by EqualPriority at each level. Each group's merged
+// metadata carries the priority of the config its title came from.
+//
+// Only sections, merge points, and metadata are merged inside a group. Base
+// template detection, placeholder fills, conditions, tag filters, and
+// collapses run once, at the second level, so a base template in one group
+// is filled from every group. When any config is a base template, the
+// groups are merged as one list, in group order, exactly as MergeAllResult
+// would merge them.
+//
+// Provenance names the original source file of each section, not the group.
+// Overrides, placeholder fills, and warnings are totalled over both levels.
+// Empty groups are skipped.
+func (m *PriorityMerger) MergeGroupsResult(ctx context.Context, groups []ConfigGroup) (*MergeResult, error) {
+	if !m.NoPlaceholders {
+		var all []*config.Config
+		for _, group := range groups {
+			all = append(all, group.Configs...)
+		}
+		if m.findBaseTemplate(all) != nil {
+			return m.MergeAllResult(ctx, all)
+		}
+	}
+
+	var intermediates []*config.Config
+	groupResults := make(map[string]*MergeResult)
+	total := &MergeResult{}
+	for _, group := range groups {
+		if len(group.Configs) == 0 {
+			continue
+		}
+		if _, exists := groupResults[group.Name]; exists {
+			return nil, fmt.Errorf("duplicate group %s", group.Name)
+		}
+
+		if m.Debug {
+			m.debugf("Merging group %s (%d configs)\n", group.Name, len(group.Configs))
+		}
+		result, err := m.mergeGroup(ctx, group.Configs)
+		if err != nil {
+			return nil, fmt.Errorf("group %s: %w", group.Name, err)
+		}
+		result.Config.SourceFile = group.Name
+		groupResults[group.Name] = result
+		intermediates = append(intermediates, result.Config)
+
+		total.Overrides += result.Overrides
+		total.PlaceholdersFilled += result.PlaceholdersFilled
+		total.Warnings = append(total.Warnings, result.Warnings...)
+	}
+
+	result, err := m.MergeAllResult(ctx, intermediates)
+	if err != nil {
+		return nil, err
+	}
+
+	// Trace each section back through its group to the file it came from
+	for key, source := range result.Provenance {
+		if group, ok := groupResults[source]; ok && group.Provenance[key] != "" {
+			result.Provenance[key] = group.Provenance[key]
+		}
+	}
+	result.Overrides += total.Overrides
+	result.PlaceholdersFilled += total.PlaceholdersFilled
+	result.Warnings = append(result.Warnings, total.Warnings...)
+	sort.Strings(result.Warnings)
+	return result, nil
+}
+
+// mergeGroup merges the configs of one group by priority alone, leaving
+// placeholders, conditions, tag filters, and collapses to the second level
+func (m *PriorityMerger) mergeGroup(ctx context.Context, configs []*config.Config) (*MergeResult, error) {
+	m.tracedSource = ""
+	m.stats = &MergeResult{Provenance: make(map[string]string)}
+	m.chunks = make(map[string][]sectionChunk)
+
+	result := &config.Config{
+		Sections:     make(map[string]config.Section),
+		MergePoints:  make(map[string]config.MergePoint),
+		MergeTargets: make(map[string]config.MergeTarget),
+	}
+	err := m.mergeByPriority(ctx, result, configs)
+	if err != nil {
+		return nil, err
+	}
+	m.applyVersionPolicy(&result.Metadata, configs)
+	result.Metadata.Extra = m.mergeExtra(configs)
+
+	m.stats.Config = result
+	return m.stats, nil
+}
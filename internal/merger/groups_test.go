@@ -0,0 +1,97 @@
+package merger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeGroupsResult(t *testing.T) {
+	styleA := &config.Config{
+		SourceFile: "claude/style/a.md",
+		Sections: map[string]config.Section{
+			"style":  {Order: 1, Content: "Style A"},
+			"shared": {Order: 3, Content: "Shared from style", Priority: config.NewExplicitPriority(10)},
+		},
+	}
+	styleB := &config.Config{
+		SourceFile: "claude/style/b.md",
+		Sections: map[string]config.Section{
+			"style": {Order: 1, Content: "Style B"},
+		},
+	}
+	testing := &config.Config{
+		SourceFile: "claude/testing/a.md",
+		Sections: map[string]config.Section{
+			"testing": {Order: 2, Content: "Testing"},
+			"shared":  {Order: 3, Content: "Shared from testing", Priority: config.NewExplicitPriority(1)},
+			"style":   {Order: 1, Content: "Style from testing"},
+		},
+	}
+
+	m := NewPriorityMerger(false)
+	result, err := m.MergeGroupsResult(context.Background(), []ConfigGroup{
+		{Name: "claude/style", Configs: []*config.Config{styleA, styleB}},
+		{Name: "claude/empty"},
+		{Name: "claude/testing", Configs: []*config.Config{testing}},
+	})
+	require.NoError(t, err)
+
+	sections := result.Config.Sections
+	assert.Equal(t, "Style from testing", sections["style"].Content, "equal priorities go to the later group")
+	assert.Equal(t, "Shared from style", sections["shared"].Content, "a higher priority beats a later group")
+	assert.Equal(t, "Testing", sections["testing"].Content)
+
+	assert.Equal(t, map[string]string{
+		"style":   "claude/testing/a.md",
+		"shared":  "claude/style/a.md",
+		"testing": "claude/testing/a.md",
+	}, result.Provenance)
+	assert.Equal(t, 2, result.Overrides)
+}
+
+func TestMergeGroupsResult_BaseTemplate(t *testing.T) {
+	base := &config.Config{
+		SourceFile: "a/COMMON.md",
+		Sections: map[string]config.Section{
+			"content": {Content: "# Base\n" +
+				"<language-specific-test-commands-here>\n</language-specific-test-commands-here>"},
+		},
+	}
+	lang := &config.Config{
+		SourceFile: "b/go.md",
+		Sections: map[string]config.Section{
+			"content": {Content: "### Testing commands\n- go test ./..."},
+		},
+	}
+
+	m := NewPriorityMerger(false)
+	result, err := m.MergeGroupsResult(context.Background(), []ConfigGroup{
+		{Name: "a", Configs: []*config.Config{base}},
+		{Name: "b", Configs: []*config.Config{lang}},
+	})
+	require.NoError(t, err)
+
+	content := result.Config.Sections["content"].Content
+	assert.Contains(t, content, "# Base", "the base template from another group is kept")
+	assert.Contains(t, content, "- go test ./...")
+	assert.Equal(t, 1, result.PlaceholdersFilled)
+	assert.Equal(t, "a/COMMON.md", result.Provenance["content"])
+}
+
+func TestMergeGroupsResult_Errors(t *testing.T) {
+	cfg := &config.Config{Sections: map[string]config.Section{"a": {Content: "A"}}}
+	m := NewPriorityMerger(false)
+
+	_, err := m.MergeGroupsResult(context.Background(), []ConfigGroup{
+		{Name: "x", Configs: []*config.Config{cfg}},
+		{Name: "x", Configs: []*config.Config{cfg}},
+	})
+	assert.ErrorContains(t, err, "duplicate group x")
+
+	_, err = m.MergeGroupsResult(context.Background(), nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,162 @@
+package merger
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// MergeLanguagesResult merges configs into one document covering every
+// language they are written for. Configs without Metadata.Language are
+// shared: they are merged on their own and their sections stay at the top
+// level. The configs of each language, matched case-insensitively, are also
+// merged on their own, so one language never overrides another or a shared
+// section, and their sections follow the shared ones under a "## Language"
+// heading, languages sorted by name. Their headings move one level deeper,
+// to nest under it, and their keys are prefixed with language_<name>_.
+//
+// Each merge fills only its own placeholders: a placeholder in a shared
+// section is filled from shared configs, and a language's placeholders and
+// merge targets from that language's configs, with merge targets applied to
+// its sections before they are nested; shared merge targets apply to the
+// whole document when it is generated. Conditions are checked against the
+// metadata merged for each group. The merged metadata comes from every
+// config, without a language.
+//
+// Without any language, this is MergeAllResult.
+func (m *PriorityMerger) MergeLanguagesResult(ctx context.Context, configs []*config.Config) (*MergeResult, error) {
+	var shared []*config.Config
+	languages := make(map[string][]*config.Config)
+	spellings := make(map[string]string)
+	for _, cfg := range configs {
+		language := strings.TrimSpace(cfg.Metadata.Language)
+		if language == "" {
+			shared = append(shared, cfg)
+			continue
+		}
+		key := strings.ToLower(language)
+		if _, ok := spellings[key]; !ok {
+			spellings[key] = language
+		}
+		languages[key] = append(languages[key], cfg)
+	}
+	if len(languages) == 0 {
+		return m.MergeAllResult(ctx, configs)
+	}
+
+	metadata, err := m.MergeMetadata(configs)
+	if err != nil {
+		return nil, err
+	}
+	metadata.Language = ""
+
+	total := &MergeResult{
+		Config: &config.Config{
+			Sections:     make(map[string]config.Section),
+			MergePoints:  make(map[string]config.MergePoint),
+			MergeTargets: make(map[string]config.MergeTarget),
+		},
+		Provenance: make(map[string]string),
+	}
+	if len(shared) > 0 {
+		result, err := m.MergeAllResult(ctx, shared)
+		if err != nil {
+			return nil, err
+		}
+		total = result
+	}
+	total.Config.Metadata = metadata
+
+	order := 0
+	for _, section := range total.Config.Sections {
+		order = max(order, section.Order)
+	}
+
+	keys := make([]string, 0, len(languages))
+	for key := range languages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		language := spellings[key]
+		if m.Debug {
+			m.debugf("Merging language %s (%d configs)\n", language, len(languages[key]))
+		}
+		result, err := m.MergeAllResult(ctx, languages[key])
+		if err != nil {
+			return nil, fmt.Errorf("language %s: %w", language, err)
+		}
+
+		prefix := "language_" + config.SanitizeName(language)
+		heading := "## " + languageTitle(language)
+		order++
+		err = addLanguageSection(total, prefix, config.Section{Order: order, Content: heading, Heading: heading}, "")
+		if err != nil {
+			return nil, err
+		}
+
+		sections := fillMergeTargets(result.Config)
+		for _, name := range config.SortedSectionKeys(sections) {
+			section := sections[name]
+			order++
+			section.Order = order
+			section.Content = config.ShiftHeadings(section.Content, 1)
+			section.Heading = config.ShiftHeadings(section.Heading, 1)
+			err = addLanguageSection(total, prefix+"_"+name, section, result.Provenance[name])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		total.Overrides += result.Overrides
+		total.PlaceholdersFilled += result.PlaceholdersFilled
+		total.Warnings = append(total.Warnings, result.Warnings...)
+	}
+
+	sort.Strings(total.Warnings)
+	return total, nil
+}
+
+// addLanguageSection stores a section of a language's part of the document
+// under key, which must not be taken
+func addLanguageSection(total *MergeResult, key string, section config.Section, source string) error {
+	if _, exists := total.Config.Sections[key]; exists {
+		return fmt.Errorf("language section %s collides with an existing section", key)
+	}
+	total.Config.Sections[key] = section
+	if source != "" {
+		total.Provenance[key] = source
+	}
+	return nil
+}
+
+// languageTitle returns a language name as a heading, with its first letter
+// in upper case, so "go" becomes "Go"
+func languageTitle(language string) string {
+	r, size := utf8.DecodeRuneInString(language)
+	return string(unicode.ToUpper(r)) + language[size:]
+}
+
+// fillMergeTargets returns the sections of cfg with each merge target
+// applied to the placeholder of its merge point, the way output is
+// generated, so the targets of one language don't reach another's sections
+func fillMergeTargets(cfg *config.Config) map[string]config.Section {
+	sections := make(map[string]config.Section, len(cfg.Sections))
+	for name, section := range cfg.Sections {
+		for targetName, target := range cfg.MergeTargets {
+			point, ok := cfg.MergePoints[targetName]
+			if !ok || point.Placeholder == "" || !strings.Contains(section.Content, point.Placeholder) {
+				continue
+			}
+			fill := ApplyStrategyFrom(MergeStrategy(target.Strategy), point.Default, target.Content, target.Source)
+			section.Content = strings.ReplaceAll(section.Content, point.Placeholder, fill)
+		}
+		sections[name] = section
+	}
+	return sections
+}
@@ -0,0 +1,83 @@
+package merger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityMerger_MergeLanguagesResult(t *testing.T) {
+	configs := []*config.Config{
+		{
+			SourceFile: "common.md",
+			Metadata:   config.Metadata{Title: "Guide"},
+			Sections: map[string]config.Section{
+				"intro":   {Order: 1, Content: "# Guide"},
+				"testing": {Order: 2, Content: "## Testing\nRun the tests."},
+			},
+		},
+		{
+			SourceFile: "python.toml",
+			Metadata:   config.Metadata{Language: "python"},
+			Sections: map[string]config.Section{
+				"testing": {Order: 1, Content: "## Testing\nRun pytest."},
+			},
+		},
+		{
+			SourceFile: "go.toml",
+			Metadata:   config.Metadata{Language: "go"},
+			Sections: map[string]config.Section{
+				"style":   {Order: 2, Content: "## Style\n```go\n# not a heading\n```"},
+				"testing": {Order: 1, Content: "## Testing\n<!-- CMD -->"},
+			},
+			MergePoints:  map[string]config.MergePoint{"cmd": {Placeholder: "<!-- CMD -->"}},
+			MergeTargets: map[string]config.MergeTarget{"cmd": {Strategy: "replace", Content: "go test ./..."}},
+		},
+		{
+			SourceFile: "go-extra.toml",
+			Metadata:   config.Metadata{Language: "Go", Priority: config.NewExplicitPriority(1)},
+			Sections: map[string]config.Section{
+				"vet": {Order: 3, Content: "Run go vet."},
+			},
+		},
+	}
+
+	result, err := NewPriorityMerger(false).MergeLanguagesResult(context.Background(), configs)
+	require.NoError(t, err)
+
+	sections := result.Config.Sections
+	assert.Equal(t, "## Testing\nRun the tests.", sections["testing"].Content, "languages never override shared sections")
+	assert.Equal(t, config.Section{Order: 3, Content: "## Go", Heading: "## Go"}, sections["language_go"])
+	assert.Equal(t, "### Testing\ngo test ./...", sections["language_go_testing"].Content)
+	assert.Equal(t, "### Style\n```go\n# not a heading\n```", sections["language_go_style"].Content)
+	assert.Equal(t, "Run go vet.", sections["language_go_vet"].Content)
+	assert.Equal(t, "## Python", sections["language_python"].Content)
+	assert.Equal(t, "### Testing\nRun pytest.", sections["language_python_testing"].Content)
+
+	var order []string
+	for _, key := range []string{"intro", "testing", "language_go", "language_go_testing", "language_go_style", "language_go_vet", "language_python", "language_python_testing"} {
+		order = append(order, key)
+		assert.Equal(t, len(order), sections[key].Order, key)
+	}
+	assert.Len(t, sections, len(order))
+
+	assert.Equal(t, "Guide", result.Config.Metadata.Title)
+	assert.Empty(t, result.Config.Metadata.Language)
+	assert.Equal(t, "go.toml", result.Provenance["language_go_testing"])
+	assert.Equal(t, "common.md", result.Provenance["testing"])
+	assert.Empty(t, result.Config.MergeTargets, "language merge targets are applied, not passed on")
+}
+
+func TestPriorityMerger_MergeLanguagesResult_NoLanguages(t *testing.T) {
+	configs := []*config.Config{
+		{SourceFile: "a.md", Sections: map[string]config.Section{"rules": {Content: "A"}}},
+		{SourceFile: "b.md", Sections: map[string]config.Section{"rules": {Content: "B"}}},
+	}
+
+	result, err := NewPriorityMerger(false).MergeLanguagesResult(context.Background(), configs)
+	require.NoError(t, err)
+	assert.Equal(t, "B", result.Config.Sections["rules"].Content)
+}
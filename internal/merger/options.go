@@ -0,0 +1,77 @@
+package merger
+
+import "io"
+
+// Options configures a PriorityMerger. The zero value merges with the
+// default rules: later files win ties, the winning file's version is used,
+// and base templates are detected automatically.
+type Options struct {
+	// Debug prints each merge decision to DebugOutput
+	Debug bool
+
+	// KeepEmptyPlaceholders leaves placeholder blocks that have no
+	// replacement content untouched, tags included, instead of reducing them
+	// to their inline default content
+	KeepEmptyPlaceholders bool
+
+	// NoPlaceholders turns off base template detection: configs are always
+	// merged by priority and placeholder tags are left as literal content
+	NoPlaceholders bool
+
+	// MergeLists combines list fields (a section's merge points and tags)
+	// from every candidate as a deduplicated union instead of keeping only
+	// the winner's list
+	MergeLists bool
+
+	// EqualPriority decides ties between equal priorities; the zero value
+	// behaves like EqualPriorityLast
+	EqualPriority EqualPriorityPolicy
+
+	// ResolveConflict, if set, is asked to settle sections that collide with
+	// equal priority and different content, instead of applying
+	// EqualPriority
+	ResolveConflict ConflictResolver
+
+	// VersionPolicy decides how the merged version is chosen; the zero value
+	// behaves like VersionPriority
+	VersionPolicy VersionPolicy
+
+	// TraceSection names a section key whose merge decisions are printed,
+	// candidate by candidate
+	TraceSection string
+
+	// Collapse lists rules that fold noisy section keys, such as
+	// changelog_20240101, into one section after merging
+	Collapse []SectionCollapse
+
+	// CollapseStrategy combines the sections folded by Collapse; the zero
+	// value behaves like StrategyAppend
+	CollapseStrategy MergeStrategy
+
+	// PlaceholderPatterns fill placeholders whose names match a pattern,
+	// such as test-go and test-python for test-*, from the inputs' sections
+	// under the heading each name maps to. They are tried after merge
+	// targets and extractors, in order.
+	PlaceholderPatterns []PlaceholderPattern
+
+	// FoldCase matches section keys case-insensitively, so Testing and
+	// testing from different files merge as one section. The merged section
+	// is stored under the key spelling of the candidate that won it.
+	FoldCase bool
+
+	// UnionKeys names Metadata.Extra keys whose values are collected from
+	// every config instead of taken from the winning one; see mergeExtra
+	UnionKeys []string
+
+	// WithTags, if not empty, keeps only the merged sections that carry at
+	// least one of these tags, along with untagged sections
+	WithTags []string
+
+	// WithoutTags drops every merged section that carries any of these tags;
+	// untagged sections are always kept
+	WithoutTags []string
+
+	// DebugOutput receives debug and trace messages; nil means os.Stderr, so
+	// diagnostics never mix with a document written to stdout
+	DebugOutput io.Writer
+}
@@ -0,0 +1,31 @@
+package merger
+
+import (
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPriorityMergerWithOptions(t *testing.T) {
+	opts := Options{
+		EqualPriority: EqualPriorityFirst,
+		FoldCase:      true,
+	}
+	m := NewPriorityMergerWithOptions(opts)
+	assert.Equal(t, opts, m.Options)
+
+	configs := []*config.Config{
+		{SourceFile: "a.toml", Sections: map[string]config.Section{"Intro": {Content: "First"}}},
+		{SourceFile: "b.toml", Sections: map[string]config.Section{"intro": {Content: "Second"}}},
+	}
+	result, err := m.MergeAll(configs)
+	require.NoError(t, err)
+	assert.Equal(t, config.Sections{"Intro": {Content: "First"}}, result.Sections)
+}
+
+func TestNewPriorityMerger_DelegatesToOptions(t *testing.T) {
+	assert.Equal(t, Options{Debug: true}, NewPriorityMerger(true).Options)
+	assert.Equal(t, Options{}, NewPriorityMerger(false).Options)
+}
@@ -0,0 +1,216 @@
+package merger
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// patternRefRegex matches a {N} reference to a captured part of a
+// placeholder name in a PlaceholderPattern heading
+var patternRefRegex = regexp.MustCompile(`\{(\d+)\}`)
+
+// patternNameRegex matches the placeholder names a pattern may be written
+// as: a placeholder name with at least one *
+var patternNameRegex = regexp.MustCompile(`^[A-Za-z0-9_*-]*\*[A-Za-z0-9_*-]*$`)
+
+// PlaceholderPattern fills every <language-specific-NAME> placeholder whose
+// name matches Pattern from the section of another input headed by Heading.
+// Each * of the pattern as written captures part of the name, and {1}, {2},
+// and so on in Heading stand for the first, second, and later captures, so
+// test-* with the heading "Testing ({1})" fills <language-specific-test-go>
+// from a "### Testing (go)" heading.
+type PlaceholderPattern struct {
+	Pattern *regexp.Regexp
+	Heading string
+}
+
+// ParsePlaceholderPattern parses a "name=heading" rule, where name is a
+// placeholder name containing at least one * and heading refers to the
+// captures as {1}, {2}, and so on. Each * matches one or more letters,
+// digits, underscores, or dashes, as few as possible, so with several the
+// earlier ones capture the shorter parts.
+func ParsePlaceholderPattern(spec string) (PlaceholderPattern, error) {
+	name, heading, found := strings.Cut(spec, "=")
+	name = strings.TrimSpace(name)
+	heading = strings.TrimSpace(heading)
+	if !found || name == "" || heading == "" {
+		return PlaceholderPattern{}, fmt.Errorf("placeholder pattern %q must be written as name=heading", spec)
+	}
+	if !patternNameRegex.MatchString(name) {
+		return PlaceholderPattern{}, fmt.Errorf("placeholder pattern %q: name must be letters, digits, _, and -, with at least one *", spec)
+	}
+
+	captures := strings.Count(name, "*")
+	for _, match := range patternRefRegex.FindAllStringSubmatch(heading, -1) {
+		n, _ := strconv.Atoi(match[1])
+		if n < 1 || n > captures {
+			return PlaceholderPattern{}, fmt.Errorf("placeholder pattern %q: heading refers to {%d}, but the name has only %d *", spec, n, captures)
+		}
+	}
+
+	parts := strings.Split(name, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	re := regexp.MustCompile("^" + strings.Join(parts, "([A-Za-z0-9_-]+?)") + "$")
+	return PlaceholderPattern{Pattern: re, Heading: heading}, nil
+}
+
+// heading returns the source heading for the placeholder name and true, or
+// false if the pattern doesn't match the name
+func (p PlaceholderPattern) heading(name string) (string, bool) {
+	captures := p.Pattern.FindStringSubmatch(name)
+	if captures == nil {
+		return "", false
+	}
+	return patternRefRegex.ReplaceAllStringFunc(p.Heading, func(ref string) string {
+		n, _ := strconv.Atoi(ref[1 : len(ref)-1])
+		if n < len(captures) {
+			return captures[n]
+		}
+		return ref
+	}), true
+}
+
+// patternHeading returns the source heading the first of patterns that
+// matches the placeholder name looks up, and true, or false if none matches
+func patternHeading(patterns []PlaceholderPattern, name string) (string, bool) {
+	for _, pattern := range patterns {
+		if heading, ok := pattern.heading(name); ok {
+			return heading, true
+		}
+	}
+	return "", false
+}
+
+// headingContent returns the trimmed content below the heading of content
+// whose text is heading, compared case-insensitively and ignoring runs of
+// spaces, up to the next heading of the same or a higher level, and true. It
+// returns false if there is no such heading. Lines in fenced code blocks are
+// never headings.
+func headingContent(content, heading string) (string, bool) {
+	want := strings.Join(strings.Fields(heading), " ")
+	lines := strings.Split(content, "\n")
+	fence := ""
+	level := 0
+	var body []string
+	for _, line := range lines {
+		if fence != "" {
+			if config.IsClosingFence(line, fence) {
+				fence = ""
+			}
+		} else if _, f, _, ok := config.ParseFence(line); ok {
+			fence = f
+		} else if match := config.HeadingRegex.FindStringSubmatch(line); match != nil {
+			if level > 0 && len(match[1]) <= level {
+				break
+			}
+			if level == 0 {
+				if strings.EqualFold(strings.Join(strings.Fields(match[2]), " "), want) {
+					level = len(match[1])
+				}
+				continue
+			}
+		}
+		if level > 0 {
+			body = append(body, line)
+		}
+	}
+	if level == 0 {
+		return "", false
+	}
+	return strings.TrimSpace(strings.Join(body, "\n")), true
+}
+
+// patternPlaceholders returns the sorted names of the placeholders in
+// sections that match one of m.PlaceholderPatterns and aren't already in
+// names
+func (m *PriorityMerger) patternPlaceholders(sections map[string]config.Section, names []string) []string {
+	if len(m.PlaceholderPatterns) == 0 {
+		return nil
+	}
+	known := make(map[string]bool, len(names))
+	for _, name := range names {
+		known[name] = true
+	}
+
+	var matched []string
+	for _, section := range sections {
+		for _, match := range placeholderTagRegex.FindAllStringSubmatch(section.Content, -1) {
+			name := match[1]
+			if known[name] {
+				continue
+			}
+			if _, ok := patternHeading(m.PlaceholderPatterns, name); ok {
+				known[name] = true
+				matched = append(matched, name)
+			}
+		}
+	}
+	sort.Strings(matched)
+	return matched
+}
+
+// patternReplacement looks up the content under the heading that one of
+// m.PlaceholderPatterns maps the placeholder name to, across configs. The
+// config with the highest metadata priority wins, with ties following the
+// equal-priority policy; within a config, the last section in section order
+// with the heading wins. It returns "" when no config has the heading.
+func (m *PriorityMerger) patternReplacement(name string, configs []*config.Config) string {
+	heading, ok := patternHeading(m.PlaceholderPatterns, name)
+	if !ok {
+		return ""
+	}
+
+	var replacement, source string
+	var priority config.Priority
+	found := false
+	for _, cfg := range configs {
+		content, ok := configHeadingContent(cfg, heading)
+		if !ok || (found && !m.wins(cfg.Metadata.Priority, priority)) {
+			continue
+		}
+		replacement, source, priority, found = content, cfg.SourceFile, cfg.Metadata.Priority, true
+	}
+	if found && m.Debug {
+		m.debugf("Filling placeholder %s from heading %q in %s\n", name, heading, source)
+	}
+	return replacement
+}
+
+// configHeadingContent returns the content under heading in the last of
+// cfg's sections, in section order, that has it. A section whose own
+// Heading is heading, with the heading left out of its content, offers all
+// of its content.
+func configHeadingContent(cfg *config.Config, heading string) (string, bool) {
+	var content string
+	found := false
+	for _, key := range config.SortedSectionKeys(cfg.Sections) {
+		section := cfg.Sections[key]
+		if text, ok := headingContent(section.Content, heading); ok {
+			content, found = text, true
+		} else if _, ok := headingContent(section.Heading, heading); ok {
+			content, found = strings.TrimSpace(section.Content), true
+		}
+	}
+	return content, found
+}
+
+// hasHeadingSource reports whether any input other than the base template
+// has a section with heading
+func hasHeadingSource(heading string, base *config.Config, configs []*config.Config) bool {
+	for _, cfg := range configs {
+		if cfg == base {
+			continue
+		}
+		if _, ok := configHeadingContent(cfg, heading); ok {
+			return true
+		}
+	}
+	return false
+}
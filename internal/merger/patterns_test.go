@@ -0,0 +1,204 @@
+package merger
+
+import (
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mustPattern parses a placeholder pattern rule or fails the test
+func mustPattern(t *testing.T, spec string) PlaceholderPattern {
+	t.Helper()
+	pattern, err := ParsePlaceholderPattern(spec)
+	require.NoError(t, err)
+	return pattern
+}
+
+func TestParsePlaceholderPattern(t *testing.T) {
+	pattern := mustPattern(t, " test-* = Testing ({1}) ")
+	assert.Equal(t, "Testing ({1})", pattern.Heading)
+	assert.True(t, pattern.Pattern.MatchString("test-go"))
+	assert.False(t, pattern.Pattern.MatchString("test-"))
+	assert.False(t, pattern.Pattern.MatchString("unit-test-go"))
+
+	for _, spec := range []string{"", "test-*", "=Testing", "test-*=", "test-go=Testing", "test.*=Testing", "test-*=Testing ({2})", "test-*=Testing ({0})"} {
+		_, err := ParsePlaceholderPattern(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestPlaceholderPattern_Heading(t *testing.T) {
+	tests := []struct {
+		spec    string
+		name    string
+		want    string
+		matches bool
+	}{
+		{spec: "test-*=Testing ({1})", name: "test-go", want: "Testing (go)", matches: true},
+		{spec: "test-*=Testing ({1})", name: "test-python", want: "Testing (python)", matches: true},
+		{spec: "test-*=Testing ({1})", name: "test-objective-c", want: "Testing (objective-c)", matches: true},
+		{spec: "test-*=Testing ({1})", name: "lint-go", matches: false},
+		{spec: "example-*-*=Examples for {2} in {1}", name: "example-go-http", want: "Examples for http in go", matches: true},
+		{spec: "example-*-*=Examples for {2} in {1}", name: "example-go-net-http", want: "Examples for net-http in go", matches: true},
+		{spec: "*-guide=Guide", name: "style-guide", want: "Guide", matches: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec+" "+tt.name, func(t *testing.T) {
+			heading, ok := mustPattern(t, tt.spec).heading(tt.name)
+			assert.Equal(t, tt.matches, ok)
+			assert.Equal(t, tt.want, heading)
+		})
+	}
+}
+
+func TestHeadingContent(t *testing.T) {
+	content := "# Go\n\n## Testing (go)\n\nRun `go test ./...`\n\n### Flags\n-race\n```sh\n# not a heading\n```\n## Linting\nRun golangci-lint"
+
+	tests := []struct {
+		name    string
+		heading string
+		want    string
+		found   bool
+	}{
+		{name: "stops at the same level", heading: "Testing (go)", want: "Run `go test ./...`\n\n### Flags\n-race\n```sh\n# not a heading\n```", found: true},
+		{name: "case and spacing", heading: "testing  (GO)", want: "Run `go test ./...`\n\n### Flags\n-race\n```sh\n# not a heading\n```", found: true},
+		{name: "stops at a higher level", heading: "Flags", want: "-race\n```sh\n# not a heading\n```", found: true},
+		{name: "last heading", heading: "Linting", want: "Run golangci-lint", found: true},
+		{name: "fenced lines are not headings", heading: "not a heading", found: false},
+		{name: "missing", heading: "Testing (python)", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := headingContent(content, tt.heading)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPriorityMerger_PlaceholderPatterns(t *testing.T) {
+	base := &config.Config{
+		SourceFile: "common.md",
+		Sections: map[string]config.Section{
+			"content": {Content: "# Guide\n" +
+				"<language-specific-test-go>\n</language-specific-test-go>\n" +
+				"<language-specific-test-python>\n</language-specific-test-python>\n" +
+				"<language-specific-test-rust>\nRun cargo test\n</language-specific-test-rust>\n" +
+				"<language-specific-test-java>\n</language-specific-test-java>\n" +
+				"<language-specific-example-go-http>\n</language-specific-example-go-http>"},
+		},
+	}
+	golang := &config.Config{
+		SourceFile: "go.md",
+		Sections: map[string]config.Section{
+			"content": {Content: "# Go\n## Testing (go)\nRun go test ./...\n## HTTP examples for Go\nUse net/http"},
+		},
+	}
+	python := &config.Config{
+		SourceFile: "python.toml",
+		Sections: map[string]config.Section{
+			"testing": {Content: "Run pytest", Heading: "### Testing (Python)"},
+		},
+	}
+	java := &config.Config{
+		SourceFile: "java.toml",
+		Sections: map[string]config.Section{
+			"testing": {Content: "### Testing (java)\nRun mvn test"},
+		},
+		MergeTargets: map[string]config.MergeTarget{
+			"test-java": {Strategy: "append", Content: "Or gradle test"},
+		},
+	}
+
+	m := NewPriorityMergerWithOptions(Options{PlaceholderPatterns: []PlaceholderPattern{
+		mustPattern(t, "test-*=Testing ({1})"),
+		mustPattern(t, "example-*-*=HTTP examples for {1}"),
+	}})
+	result, err := m.MergeAll([]*config.Config{base, golang, python, java})
+	require.NoError(t, err)
+
+	assert.Equal(t, "# Guide\n"+
+		"Run go test ./...\n"+
+		"Run pytest\n"+
+		"Run cargo test\n"+
+		"Run mvn test\nOr gradle test\n"+
+		"Use net/http", result.Sections["content"].Content)
+}
+
+func TestPriorityMerger_PlaceholderPatternsPriority(t *testing.T) {
+	base := &config.Config{
+		SourceFile: "common.md",
+		Sections: map[string]config.Section{
+			"content": {Content: "<language-specific-test-go>\n</language-specific-test-go>"},
+		},
+	}
+	source := func(file, content string, priority config.Priority) *config.Config {
+		return &config.Config{
+			SourceFile: file,
+			Metadata:   config.Metadata{Priority: priority},
+			Sections:   map[string]config.Section{"content": {Content: "## Testing (go)\n" + content}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		configs []*config.Config
+		want    string
+	}{
+		{
+			name: "higher priority wins",
+			configs: []*config.Config{
+				source("team.md", "team", config.NewRelativePriority(9)),
+				source("go.md", "go", config.NewRelativePriority(1)),
+			},
+			want: "team",
+		},
+		{
+			name: "later file wins ties",
+			configs: []*config.Config{
+				source("a.md", "a", config.Priority{}),
+				source("b.md", "b", config.Priority{}),
+			},
+			want: "b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewPriorityMergerWithOptions(Options{PlaceholderPatterns: []PlaceholderPattern{mustPattern(t, "test-*=Testing ({1})")}})
+			result, err := m.MergeAll(append([]*config.Config{base}, tt.configs...))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.Sections["content"].Content)
+		})
+	}
+}
+
+func TestUnfillablePlaceholders_Patterns(t *testing.T) {
+	base := &config.Config{
+		SourceFile: "common.md",
+		Sections: map[string]config.Section{
+			"content": {Content: "<language-specific-test-go>\n</language-specific-test-go>\n" +
+				"<language-specific-test-python>\n</language-specific-test-python>\n" +
+				"<language-specific-test-rust>\ncargo test\n</language-specific-test-rust>"},
+		},
+	}
+	golang := &config.Config{
+		SourceFile: "go.md",
+		Sections:   map[string]config.Section{"content": {Content: "## Testing (go)\ngo test"}},
+	}
+	configs := []*config.Config{base, golang}
+
+	assert.Equal(t, []string{
+		"placeholder <language-specific-test-go> in section content of common.md has no extractor or merge target",
+		"placeholder <language-specific-test-python> in section content of common.md has no extractor or merge target",
+		"placeholder <language-specific-test-rust> in section content of common.md has no extractor or merge target",
+	}, UnfillablePlaceholders(configs))
+
+	assert.Equal(t, []string{
+		`placeholder <language-specific-test-python> in section content of common.md has no section headed "Testing (python)" among the inputs`,
+	}, UnfillablePlaceholders(configs, mustPattern(t, "test-*=Testing ({1})")))
+}
@@ -0,0 +1,334 @@
+package merger
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/arustydev/claude-merge/internal/config"
+)
+
+// placeholderExtractor knows how to find replacement content for one
+// <language-specific-NAME> placeholder in a source config's sections
+type placeholderExtractor struct {
+	name     string
+	contains func(content string) bool
+	extract  func(content string) string
+}
+
+// placeholderExtractors lists every placeholder the merger can fill
+var placeholderExtractors = []placeholderExtractor{
+	{name: "test-commands-here", contains: containsTestCommands, extract: extractTestCommands},
+	{name: "documentation-standards", contains: containsDocumentationStandards, extract: extractDocumentationStandards},
+}
+
+// placeholderTagRegex matches an opening placeholder tag and captures its name
+var placeholderTagRegex = regexp.MustCompile(`<language-specific-([A-Za-z0-9_-]+)>`)
+
+// placeholderTags returns the opening and closing tags for a placeholder name
+func placeholderTags(name string) (string, string) {
+	return "<language-specific-" + name + ">", "</language-specific-" + name + ">"
+}
+
+// placeholderDefault returns the trimmed text between a placeholder's opening
+// and closing tags in content, used as fallback content when no source fills
+// the placeholder
+func placeholderDefault(content, name string) string {
+	openTag, closeTag := placeholderTags(name)
+	startIdx := strings.Index(content, openTag)
+	if startIdx == -1 {
+		return ""
+	}
+	start := startIdx + len(openTag)
+	endIdx := strings.Index(content[start:], closeTag)
+	if endIdx == -1 {
+		return ""
+	}
+	return strings.TrimSpace(content[start : start+endIdx])
+}
+
+// findExtractor returns the extractor registered for a placeholder name
+func findExtractor(name string) (placeholderExtractor, bool) {
+	for _, extractor := range placeholderExtractors {
+		if extractor.name == name {
+			return extractor, true
+		}
+	}
+	return placeholderExtractor{}, false
+}
+
+// UnfillablePlaceholders cross-checks every placeholder in the base template
+// against the registered extractors, the patterns, and the other inputs,
+// describing each placeholder that can never be filled. A placeholder with
+// inline default content always has something to show. It returns nil when
+// there is no base template or every placeholder has a source.
+func UnfillablePlaceholders(configs []*config.Config, patterns ...PlaceholderPattern) []string {
+	m := &PriorityMerger{}
+	base := m.findBaseTemplate(configs)
+	if base == nil {
+		return nil
+	}
+
+	var problems []string
+	for sectionName, section := range base.Sections {
+		for _, match := range placeholderTagRegex.FindAllStringSubmatch(section.Content, -1) {
+			name := match[1]
+			location := fmt.Sprintf("placeholder %s in section %s of %s", match[0], sectionName, base.SourceFile)
+
+			if hasPlaceholderTarget(name, configs) {
+				continue
+			}
+			hasDefault := placeholderDefault(section.Content, name) != ""
+			extractor, ok := findExtractor(name)
+			if ok {
+				if !hasDefault && !hasPlaceholderSource(extractor, base, configs) {
+					problems = append(problems, location+" has no source content among the inputs")
+				}
+				continue
+			}
+			heading, ok := patternHeading(patterns, name)
+			if !ok {
+				problems = append(problems, location+" has no extractor or merge target")
+				continue
+			}
+			if !hasDefault && !hasHeadingSource(heading, base, configs) {
+				problems = append(problems, fmt.Sprintf("%s has no section headed %q among the inputs", location, heading))
+			}
+		}
+	}
+
+	sort.Strings(problems)
+	return problems
+}
+
+// PlaceholderUse is one place a placeholder appears in the inputs
+type PlaceholderUse struct {
+	// Name is the placeholder name: NAME for a <language-specific-NAME>
+	// tag, or the merge point key for a merge point's placeholder
+	Name    string
+	Section string
+	File    string
+}
+
+// ListPlaceholders finds every placeholder in the sections of configs: the
+// <language-specific-NAME> tags and the placeholder text of every merge point
+// any input defines. Each placeholder is listed once per section it appears
+// in, sorted by name, file, and section.
+func ListPlaceholders(configs []*config.Config) []PlaceholderUse {
+	points := make(map[string]string)
+	for _, cfg := range configs {
+		for name, point := range cfg.MergePoints {
+			if point.Placeholder != "" {
+				points[name] = point.Placeholder
+			}
+		}
+	}
+
+	seen := make(map[PlaceholderUse]bool)
+	var uses []PlaceholderUse
+	add := func(use PlaceholderUse) {
+		if !seen[use] {
+			seen[use] = true
+			uses = append(uses, use)
+		}
+	}
+	for _, cfg := range configs {
+		for sectionName, section := range cfg.Sections {
+			for _, match := range placeholderTagRegex.FindAllStringSubmatch(section.Content, -1) {
+				add(PlaceholderUse{Name: match[1], Section: sectionName, File: cfg.SourceFile})
+			}
+			for name, placeholder := range points {
+				if strings.Contains(section.Content, placeholder) {
+					add(PlaceholderUse{Name: name, Section: sectionName, File: cfg.SourceFile})
+				}
+			}
+		}
+	}
+
+	sort.Slice(uses, func(i, j int) bool {
+		a, b := uses[i], uses[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Section < b.Section
+	})
+	return uses
+}
+
+// hasPlaceholderSource reports whether any input other than the base template
+// has content the extractor can use
+func hasPlaceholderSource(extractor placeholderExtractor, base *config.Config, configs []*config.Config) bool {
+	for _, cfg := range configs {
+		if cfg == base {
+			continue
+		}
+		for _, section := range cfg.Sections {
+			if extractor.contains(section.Content) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasPlaceholderTarget reports whether any input has a merge target keyed by
+// the placeholder name
+func hasPlaceholderTarget(name string, configs []*config.Config) bool {
+	for _, cfg := range configs {
+		if _, ok := cfg.MergeTargets[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// placeholderTargets picks, for each merge target name across configs, the
+// target with the highest priority. Ties follow the equal-priority policy,
+// as for merge targets in a normal merge.
+func (m *PriorityMerger) placeholderTargets(configs []*config.Config) map[string]config.MergeTarget {
+	targets := make(map[string]config.MergeTarget)
+	for _, cfg := range configs {
+		for name, target := range cfg.MergeTargets {
+			existing, exists := targets[name]
+			if exists && !m.wins(target.Priority, existing.Priority) {
+				continue
+			}
+			if target.Source == "" {
+				target.Source = cfg.SourceFile
+			}
+			targets[name] = target
+		}
+	}
+	return targets
+}
+
+// placeholderNames returns the names of every placeholder that can be
+// filled: the registered extractors in order, then the remaining merge
+// target names sorted
+func placeholderNames(targets map[string]config.MergeTarget) []string {
+	names := make([]string, 0, len(placeholderExtractors)+len(targets))
+	for _, extractor := range placeholderExtractors {
+		names = append(names, extractor.name)
+	}
+	var extra []string
+	for name := range targets {
+		if _, ok := findExtractor(name); !ok {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	return append(names, extra...)
+}
+
+// placeholderCandidates holds the replacement content one config offers for
+// each placeholder name
+type placeholderCandidates map[string]string
+
+// extractCandidates runs every extractor over cfg's sections in section
+// order. Where several sections offer content for the same placeholder, the
+// last one wins.
+func extractCandidates(cfg *config.Config) placeholderCandidates {
+	keys := config.SortedSectionKeys(cfg.Sections)
+
+	candidates := make(placeholderCandidates)
+	for _, key := range keys {
+		content := cfg.Sections[key].Content
+		for _, extractor := range placeholderExtractors {
+			if extractor.contains(content) {
+				candidates[extractor.name] = extractor.extract(content)
+			}
+		}
+	}
+	return candidates
+}
+
+// collectReplacements extracts placeholder content from every config using
+// up to workers goroutines, then picks one replacement per placeholder. The
+// config with the highest metadata priority wins; ties go to the later
+// config, or the earlier one under EqualPriorityFirst. The result doesn't
+// depend on workers.
+func (m *PriorityMerger) collectReplacements(ctx context.Context, configs []*config.Config, workers int) (map[string]string, error) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	// Each worker writes only its own configs' slots, so no locking is needed
+	extracted := make([]placeholderCandidates, len(configs))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(configs); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if ctx.Err() == nil {
+					extracted[i] = extractCandidates(configs[i])
+				}
+			}
+		}()
+	}
+	for i := range configs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	replacements := make(map[string]string)
+	priorities := make(map[string]config.Priority)
+	for i, cfg := range configs {
+		if m.Debug {
+			m.debugf("Processing config: %s, Language: %s\n", cfg.SourceFile, cfg.Metadata.Language)
+		}
+		for _, extractor := range placeholderExtractors {
+			replacement, ok := extracted[i][extractor.name]
+			if !ok {
+				continue
+			}
+			existing, exists := priorities[extractor.name]
+			if exists && !m.wins(cfg.Metadata.Priority, existing) {
+				continue
+			}
+			if m.Debug {
+				m.debugf("Found content for placeholder %s: %d chars\n", extractor.name, len(replacement))
+			}
+			replacements[extractor.name] = replacement
+			priorities[extractor.name] = cfg.Metadata.Priority
+		}
+	}
+	return replacements, nil
+}
+
+// leftoverTagRegex matches anything that looks like a placeholder tag,
+// opening or closing, in any case, terminated or not
+var leftoverTagRegex = regexp.MustCompile(`(?i)</?language-specific-[^\s<>]*>?`)
+
+// FindLeftoverTags returns every placeholder tag in text, opening or
+// closing, in any case, terminated or not, in the order they appear
+func FindLeftoverTags(text string) []string {
+	return leftoverTagRegex.FindAllString(text, -1)
+}
+
+// LeftoverPlaceholders describes every placeholder tag still present in
+// sections, such as a misspelled or unterminated tag the merger couldn't
+// fill. The result is sorted, and nil when no tags remain.
+func LeftoverPlaceholders(sections map[string]config.Section) []string {
+	var leftovers []string
+	for name, section := range sections {
+		for _, tag := range FindLeftoverTags(section.Content) {
+			leftovers = append(leftovers, fmt.Sprintf("section %s: %s", name, tag))
+		}
+	}
+	sort.Strings(leftovers)
+	return leftovers
+}
@@ -0,0 +1,299 @@
+package merger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnfillablePlaceholders(t *testing.T) {
+	base := &config.Config{
+		SourceFile: "common.md",
+		Sections: map[string]config.Section{
+			"content": {Content: "# Base\n" +
+				"<language-specific-test-commands-here>\n</language-specific-test-commands-here>\n" +
+				"<language-specific-documentation-standards>\n</language-specific-documentation-standards>\n" +
+				"<language-specific-lint-rules>\n</language-specific-lint-rules>"},
+		},
+	}
+	lang := &config.Config{
+		SourceFile: "go.md",
+		Sections: map[string]config.Section{
+			"content": {Content: "### Testing commands\n- go test ./..."},
+		},
+	}
+
+	problems := UnfillablePlaceholders([]*config.Config{base, lang})
+
+	assert.Equal(t, []string{
+		"placeholder <language-specific-documentation-standards> in section content of common.md has no source content among the inputs",
+		"placeholder <language-specific-lint-rules> in section content of common.md has no extractor or merge target",
+	}, problems)
+}
+
+func TestUnfillablePlaceholders_NoTemplate(t *testing.T) {
+	plain := &config.Config{
+		Sections: map[string]config.Section{"content": {Content: "# Plain"}},
+	}
+
+	assert.Nil(t, UnfillablePlaceholders([]*config.Config{plain}))
+}
+
+func TestUnfillablePlaceholders_InlineDefault(t *testing.T) {
+	base := &config.Config{
+		SourceFile: "common.md",
+		Sections: map[string]config.Section{
+			"content": {Content: "<language-specific-documentation-standards>\nDocument exported names.\n</language-specific-documentation-standards>"},
+		},
+	}
+
+	assert.Nil(t, UnfillablePlaceholders([]*config.Config{base}))
+}
+
+func TestPlaceholderDefault(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "inner text", content: "<language-specific-x>\n  Default text\n</language-specific-x>", want: "Default text"},
+		{name: "empty block", content: "<language-specific-x>\n</language-specific-x>", want: ""},
+		{name: "missing close tag", content: "<language-specific-x>\nDefault", want: ""},
+		{name: "no block", content: "plain", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, placeholderDefault(tt.content, "x"))
+		})
+	}
+}
+
+func TestLeftoverPlaceholders(t *testing.T) {
+	sections := map[string]config.Section{
+		"intro":   {Content: "# Intro\n<Language-Specific-test-commands-here>\n- go test\n</language-specific-test-commands-here>"},
+		"docs":    {Content: "<language-specific-documentation-standards\nDocument exported names."},
+		"testing": {Content: "No tags here."},
+	}
+
+	assert.Equal(t, []string{
+		"section docs: <language-specific-documentation-standards",
+		"section intro: </language-specific-test-commands-here>",
+		"section intro: <Language-Specific-test-commands-here>",
+	}, LeftoverPlaceholders(sections))
+
+	assert.Nil(t, LeftoverPlaceholders(map[string]config.Section{"testing": sections["testing"]}))
+}
+
+func TestFindLeftoverTags(t *testing.T) {
+	assert.Equal(t, []string{"<Language-Specific-lint>", "</language-specific-lint"},
+		FindLeftoverTags("<Language-Specific-lint> text </language-specific-lint"))
+	assert.Nil(t, FindLeftoverTags("<language>not a placeholder</language>"))
+}
+
+func TestListPlaceholders(t *testing.T) {
+	base := &config.Config{
+		SourceFile: "base.md",
+		Sections: map[string]config.Section{
+			"content": {Content: "<language-specific-test-commands-here>\n- go test\n</language-specific-test-commands-here>\n" +
+				"<language-specific-test-commands-here></language-specific-test-commands-here>\n<!-- LINT -->"},
+			"docs": {Content: "<language-specific-documentation-standards>"},
+		},
+	}
+	points := &config.Config{
+		SourceFile: "points.toml",
+		Sections:   map[string]config.Section{"extra": {Content: "No placeholders."}},
+		MergePoints: map[string]config.MergePoint{
+			"lint":   {Placeholder: "<!-- LINT -->"},
+			"unused": {Placeholder: "<!-- UNUSED -->"},
+		},
+	}
+
+	assert.Equal(t, []PlaceholderUse{
+		{Name: "documentation-standards", Section: "docs", File: "base.md"},
+		{Name: "lint", Section: "content", File: "base.md"},
+		{Name: "test-commands-here", Section: "content", File: "base.md"},
+	}, ListPlaceholders([]*config.Config{points, base}))
+
+	assert.Nil(t, ListPlaceholders([]*config.Config{points}))
+}
+
+func TestCollectReplacements_Priority(t *testing.T) {
+	low := &config.Config{
+		Metadata: config.Metadata{Priority: config.NewExplicitPriority(1)},
+		Sections: map[string]config.Section{"content": {Content: "### Testing commands\n- low"}},
+	}
+	high := &config.Config{
+		Metadata: config.Metadata{Priority: config.NewExplicitPriority(5)},
+		Sections: map[string]config.Section{"content": {Content: "### Testing commands\n- high"}},
+	}
+	tie := &config.Config{
+		Metadata: config.Metadata{Priority: config.NewExplicitPriority(5)},
+		Sections: map[string]config.Section{"content": {Content: "### Testing commands\n- tie"}},
+	}
+
+	m := NewPriorityMerger(false)
+	replacements, err := m.collectReplacements(context.Background(), []*config.Config{high, low}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "- high", replacements["test-commands-here"])
+
+	replacements, err = m.collectReplacements(context.Background(), []*config.Config{high, tie}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "- tie", replacements["test-commands-here"])
+
+	m.EqualPriority = EqualPriorityFirst
+	replacements, err = m.collectReplacements(context.Background(), []*config.Config{high, tie}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "- high", replacements["test-commands-here"])
+}
+
+func TestCollectReplacements_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewPriorityMerger(false).collectReplacements(ctx, largeConfigs(3, 10), 2)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCollectReplacements_ParallelMatchesSerial(t *testing.T) {
+	configs := largeConfigs(12, 50)
+	m := NewPriorityMerger(false)
+
+	serial, err := m.collectReplacements(context.Background(), configs, 1)
+	require.NoError(t, err)
+	require.Len(t, serial, 2)
+
+	for _, workers := range []int{2, 4, 16} {
+		parallel, err := m.collectReplacements(context.Background(), configs, workers)
+		require.NoError(t, err)
+		assert.Equal(t, serial, parallel, "workers=%d", workers)
+	}
+}
+
+func BenchmarkCollectReplacements(b *testing.B) {
+	configs := largeConfigs(12, 400)
+	m := NewPriorityMerger(false)
+
+	for _, workers := range []int{1, 4, 12} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, err := m.collectReplacements(context.Background(), configs, workers)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// largeConfigs builds count language configs of sections sections each, with
+// testing commands and documentation standards spread across them
+func largeConfigs(count, sections int) []*config.Config {
+	filler := strings.Repeat("Keep functions small and names descriptive.\n", 40)
+	configs := make([]*config.Config, count)
+	for i := range configs {
+		cfg := &config.Config{
+			SourceFile: fmt.Sprintf("lang%d.md", i),
+			Metadata:   config.Metadata{Priority: config.NewExplicitPriority(i % 3)},
+			Sections:   make(map[string]config.Section, sections),
+		}
+		for j := 0; j < sections; j++ {
+			content := filler
+			switch j % 10 {
+			case 3:
+				content += fmt.Sprintf("### Testing commands\n- test %d-%d\n", i, j)
+			case 7:
+				content += fmt.Sprintf("### Documentation Standards\nDocument %d-%d\n", i, j)
+			}
+			cfg.Sections[fmt.Sprintf("section%03d", j)] = config.Section{Order: j, Content: content}
+		}
+		configs[i] = cfg
+	}
+	return configs
+}
+
+func TestUnfillablePlaceholders_MergeTarget(t *testing.T) {
+	base := &config.Config{
+		SourceFile: "common.md",
+		Sections: map[string]config.Section{
+			"content": {Content: "<language-specific-lint-rules>\n</language-specific-lint-rules>"},
+		},
+	}
+	lang := &config.Config{
+		SourceFile:   "go.toml",
+		MergeTargets: map[string]config.MergeTarget{"lint-rules": {Content: "- golangci-lint run"}},
+	}
+
+	assert.Nil(t, UnfillablePlaceholders([]*config.Config{base, lang}))
+}
+
+func TestPriorityMerger_PlaceholderMergeTargets(t *testing.T) {
+	template := "# Base\n" +
+		"<language-specific-test-commands-here>\nRun the tests.\n</language-specific-test-commands-here>\n" +
+		"<language-specific-lint-rules>\n</language-specific-lint-rules>"
+	scraped := "### Testing commands\n- go test ./..."
+
+	tests := []struct {
+		name    string
+		targets map[string]config.MergeTarget
+		want    string
+	}{
+		{
+			name: "extractor without target",
+			want: "# Base\n- go test ./...\n<language-specific-lint-rules>\n</language-specific-lint-rules>",
+		},
+		{
+			name:    "target replaces extractor",
+			targets: map[string]config.MergeTarget{"test-commands-here": {Content: "- make test"}},
+			want:    "# Base\n- make test\n<language-specific-lint-rules>\n</language-specific-lint-rules>",
+		},
+		{
+			name:    "target appends to extractor",
+			targets: map[string]config.MergeTarget{"test-commands-here": {Strategy: "append", Content: "- make test"}},
+			want:    "# Base\n- go test ./...\n- make test\n<language-specific-lint-rules>\n</language-specific-lint-rules>",
+		},
+		{
+			name:    "target fills placeholder without extractor",
+			targets: map[string]config.MergeTarget{"lint-rules": {Content: "- golangci-lint run"}},
+			want:    "# Base\n- go test ./...\n- golangci-lint run",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configs := []*config.Config{
+				{SourceFile: "common.md", Sections: map[string]config.Section{"content": {Content: template}}},
+				{SourceFile: "go.md", Sections: map[string]config.Section{"content": {Content: scraped}}, MergeTargets: tt.targets},
+			}
+
+			result, err := NewPriorityMerger(false).MergeAll(configs)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.Sections["content"].Content)
+		})
+	}
+}
+
+func TestPriorityMerger_PlaceholderMergeTargetPriority(t *testing.T) {
+	configs := []*config.Config{
+		{
+			SourceFile: "common.md",
+			Sections:   map[string]config.Section{"content": {Content: "<language-specific-lint-rules>\n</language-specific-lint-rules>"}},
+			MergeTargets: map[string]config.MergeTarget{
+				"lint-rules": {Content: "- base rules", Priority: config.NewExplicitPriority(10)},
+			},
+		},
+		{
+			SourceFile:   "go.toml",
+			MergeTargets: map[string]config.MergeTarget{"lint-rules": {Content: "- go rules"}},
+		},
+	}
+
+	result, err := NewPriorityMerger(false).MergeAll(configs)
+	require.NoError(t, err)
+	assert.Equal(t, "- base rules", result.Sections["content"].Content)
+}
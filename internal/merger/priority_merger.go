@@ -1,27 +1,135 @@
 package merger
 
 import (
+	"context"
 	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/arustydev/claude-merge/internal/config"
 )
 
-// PriorityMerger handles priority-based merging of multiple configurations
+// EqualPriorityPolicy decides which candidate wins when two candidates have
+// equal priority
+type EqualPriorityPolicy string
+
+const (
+	// EqualPriorityLast lets the later file win ties (the default)
+	EqualPriorityLast EqualPriorityPolicy = "last"
+
+	// EqualPriorityFirst keeps the earlier file's content on ties
+	EqualPriorityFirst EqualPriorityPolicy = "first"
+)
+
+// IsValid checks if a policy string is valid
+func (p EqualPriorityPolicy) IsValid() bool {
+	return p == EqualPriorityLast || p == EqualPriorityFirst
+}
+
+// VersionPolicy decides how the merged metadata version is chosen
+type VersionPolicy string
+
+const (
+	// VersionPriority takes the version like any other metadata field, from
+	// the winning file (the default)
+	VersionPriority VersionPolicy = "priority"
+
+	// VersionHighestSemver takes the highest semantic version among all
+	// inputs, ignoring versions that are not semantic versions
+	VersionHighestSemver VersionPolicy = "highest-semver"
+)
+
+// IsValid checks if a policy string is valid
+func (p VersionPolicy) IsValid() bool {
+	return p == VersionPriority || p == VersionHighestSemver
+}
+
+// PriorityMerger handles priority-based merging of multiple configurations.
+// Its behavior is set by the embedded Options, whose fields can also be set
+// directly on the merger.
 type PriorityMerger struct {
-	debug bool
+	Options
+
+	// tracedSource is the file currently holding the traced section
+	tracedSource string
+
+	// stats collects the MergeResult of the merge in progress
+	stats *MergeResult
+
+	// chunks holds, for each section combined across files, the content
+	// each file contributed
+	chunks map[string][]sectionChunk
 }
 
-// NewPriorityMerger creates a new priority merger
+// NewPriorityMerger creates a new priority merger with default options,
+// printing debug messages if debug is set
 func NewPriorityMerger(debug bool) *PriorityMerger {
-	return &PriorityMerger{debug: debug}
+	return NewPriorityMergerWithOptions(Options{Debug: debug})
+}
+
+// NewPriorityMergerWithOptions creates a new priority merger configured by
+// opts
+func NewPriorityMergerWithOptions(opts Options) *PriorityMerger {
+	return &PriorityMerger{Options: opts}
+}
+
+// debugf writes a debug or trace message to DebugOutput
+func (m *PriorityMerger) debugf(format string, args ...interface{}) {
+	out := m.DebugOutput
+	if out == nil {
+		out = os.Stderr
+	}
+	fmt.Fprintf(out, format, args...)
 }
 
 // MergeAll merges multiple configurations using priority rules
 func (m *PriorityMerger) MergeAll(configs []*config.Config) (*config.Config, error) {
+	return m.MergeAllContext(context.Background(), configs)
+}
+
+// MergeFiles loads the given files in order and merges them. Cancelling ctx
+// aborts loading or merging with ctx.Err().
+func (m *PriorityMerger) MergeFiles(ctx context.Context, filenames []string) (*config.Config, error) {
+	configs, err := config.LoadConfigs(ctx, filenames)
+	if err != nil {
+		return nil, err
+	}
+	return m.MergeAllContext(ctx, configs)
+}
+
+// MergeFS loads the named files from fsys, such as an embed.FS, in order
+// and merges them like MergeFiles
+func (m *PriorityMerger) MergeFS(ctx context.Context, fsys fs.FS, names []string) (*config.Config, error) {
+	configs, err := config.LoadConfigsFS(ctx, fsys, names)
+	if err != nil {
+		return nil, err
+	}
+	return m.MergeAllContext(ctx, configs)
+}
+
+// MergeAllContext merges multiple configurations using priority rules,
+// checking ctx between configs and sections so a cancelled merge stops early
+func (m *PriorityMerger) MergeAllContext(ctx context.Context, configs []*config.Config) (*config.Config, error) {
+	mergeResult, err := m.MergeAllResult(ctx, configs)
+	if err != nil {
+		return nil, err
+	}
+	return mergeResult.Config, nil
+}
+
+// MergeAllResult merges like MergeAllContext and also reports where each
+// section came from, how many overrides and placeholder fills happened, and
+// any non-fatal warnings
+func (m *PriorityMerger) MergeAllResult(ctx context.Context, configs []*config.Config) (*MergeResult, error) {
 	if len(configs) == 0 {
 		return nil, fmt.Errorf("no configurations to merge")
 	}
+	m.tracedSource = ""
+	m.stats = &MergeResult{Provenance: make(map[string]string)}
+	m.chunks = make(map[string][]sectionChunk)
 
 	result := &config.Config{
 		Sections:     make(map[string]config.Section),
@@ -30,27 +138,178 @@ func (m *PriorityMerger) MergeAll(configs []*config.Config) (*config.Config, err
 	}
 
 	// Check if we have a base template with placeholders
-	baseConfig := m.findBaseTemplate(configs)
+	var baseConfig *config.Config
+	if !m.NoPlaceholders {
+		baseConfig = m.findBaseTemplate(configs)
+	}
 	if baseConfig != nil {
 		// Use template-based merging
-		m.mergeWithTemplate(result, baseConfig, configs)
+		err := m.mergeWithTemplate(ctx, result, baseConfig, configs)
+		if err != nil {
+			return nil, err
+		}
 	} else {
 		// Use standard priority-based merging
-		for _, cfg := range configs {
-			m.mergeMetadata(result, cfg)
-			m.mergeSections(result, cfg)
-			m.mergeMergePoints(result, cfg)
-			m.mergeMergeTargets(result, cfg)
+		err := m.mergeByPriority(ctx, result, configs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	m.applyVersionPolicy(&result.Metadata, configs)
+	result.Metadata.Extra = m.mergeExtra(configs)
+
+	// Drop sections whose condition doesn't hold for the merged metadata
+	err := m.applyConditions(result)
+	if err != nil {
+		return nil, err
+	}
+	m.applyTagFilters(result)
+	m.applyCollapses(result)
+
+	sort.Strings(m.stats.Warnings)
+	m.stats.Config = result
+	return m.stats, nil
+}
+
+// mergeByPriority merges the metadata, sections, merge points, and merge
+// targets of configs into result, in order, by priority alone
+func (m *PriorityMerger) mergeByPriority(ctx context.Context, result *config.Config, configs []*config.Config) error {
+	for _, cfg := range configs {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		m.mergeMetadata(result, cfg)
+		err = m.mergeSections(ctx, result, cfg)
+		if err != nil {
+			return err
+		}
+		m.mergeMergePoints(result, cfg)
+		m.mergeMergeTargets(result, cfg)
+	}
+	return nil
+}
+
+// applyConditions removes sections whose condition evaluates to false
+func (m *PriorityMerger) applyConditions(result *config.Config) error {
+	for name, section := range result.Sections {
+		keep, err := config.EvaluateCondition(section.Condition, result.Metadata)
+		if err != nil {
+			return fmt.Errorf("section %s: %w", name, err)
+		}
+		if !keep {
+			if m.Debug {
+				m.debugf("Dropping section %s (condition %q not met)\n", name, section.Condition)
+			}
+			delete(result.Sections, name)
+			delete(m.stats.Provenance, name)
+		}
+	}
+	return nil
+}
+
+// MergeMetadata merges only the metadata of configs, using the same priority
+// rules as MergeAll without a base template. Sections, merge points, and
+// placeholders are not touched.
+func (m *PriorityMerger) MergeMetadata(configs []*config.Config) (config.Metadata, error) {
+	if len(configs) == 0 {
+		return config.Metadata{}, fmt.Errorf("no configurations to merge")
+	}
+
+	result := &config.Config{}
+	for _, cfg := range configs {
+		m.mergeMetadata(result, cfg)
+	}
+	m.applyVersionPolicy(&result.Metadata, configs)
+	result.Metadata.Extra = m.mergeExtra(configs)
+	return result.Metadata, nil
+}
+
+// applyVersionPolicy replaces the priority-chosen version in metadata when
+// VersionPolicy asks for the highest semantic version. If no input has a
+// valid semantic version, the priority-chosen version stays.
+func (m *PriorityMerger) applyVersionPolicy(metadata *config.Metadata, configs []*config.Config) {
+	if m.VersionPolicy != VersionHighestSemver {
+		return
+	}
+
+	var highest semver
+	found := false
+	for _, cfg := range configs {
+		version, ok := parseSemver(cfg.Metadata.Version)
+		if !ok {
+			continue
+		}
+		if !found || version.compare(highest) > 0 {
+			highest = version
+			metadata.Version = cfg.Metadata.Version
+			found = true
 		}
 	}
+}
 
-	return result, nil
+// ApplyDefaults fills in any metadata field, section, merge point, or merge
+// target that is missing from result using defaults. Defaults have the lowest
+// precedence of all: they never replace content supplied by a merged config,
+// regardless of priorities or file order. Default sections are subject to
+// WithTags and WithoutTags.
+func (m *PriorityMerger) ApplyDefaults(result *config.Config, defaults *config.Config) {
+	if result.Metadata.Title == "" {
+		result.Metadata.Title = defaults.Metadata.Title
+	}
+	if result.Metadata.Description == "" {
+		result.Metadata.Description = defaults.Metadata.Description
+	}
+	if result.Metadata.Version == "" {
+		result.Metadata.Version = defaults.Metadata.Version
+	}
+	if result.Metadata.Language == "" {
+		result.Metadata.Language = defaults.Metadata.Language
+	}
+	for key, value := range defaults.Metadata.Extra {
+		if _, exists := result.Metadata.Extra[key]; !exists {
+			if result.Metadata.Extra == nil {
+				result.Metadata.Extra = make(map[string]interface{})
+			}
+			result.Metadata.Extra[key] = value
+		}
+	}
+
+	for name, section := range defaults.Sections {
+		if _, exists := result.Sections[name]; !exists && m.keepsTags(section.Tags) {
+			if m.Debug {
+				m.debugf("Using default section %s from %s\n", name, defaults.SourceFile)
+			}
+			result.Sections[name] = section
+		}
+	}
+	for name, point := range defaults.MergePoints {
+		if _, exists := result.MergePoints[name]; !exists {
+			result.MergePoints[name] = point
+		}
+	}
+	for name, target := range defaults.MergeTargets {
+		if _, exists := result.MergeTargets[name]; !exists {
+			result.MergeTargets[name] = target
+		}
+	}
+}
+
+// wins reports whether an incoming priority replaces an existing one under
+// the merger's equal-priority policy
+func (m *PriorityMerger) wins(incoming, existing config.Priority) bool {
+	if m.EqualPriority == EqualPriorityFirst {
+		return incoming.TakesPrecedenceOver(existing)
+	}
+	return incoming.TakesPrecedenceOverOrEqual(existing)
 }
 
 // mergeMetadata merges metadata using priority rules
 func (m *PriorityMerger) mergeMetadata(result *config.Config, incoming *config.Config) {
 	// Merge title
-	if result.Metadata.Title == "" || incoming.Metadata.Priority.TakesPrecedenceOverOrEqual(result.Metadata.Priority) {
+	if result.Metadata.Title == "" || m.wins(incoming.Metadata.Priority, result.Metadata.Priority) {
 		if incoming.Metadata.Title != "" {
 			result.Metadata.Title = incoming.Metadata.Title
 			result.Metadata.Priority = incoming.Metadata.Priority
@@ -58,28 +317,28 @@ func (m *PriorityMerger) mergeMetadata(result *config.Config, incoming *config.C
 	}
 
 	// Merge description (only if higher priority or empty)
-	if result.Metadata.Description == "" || incoming.Metadata.Priority.TakesPrecedenceOverOrEqual(result.Metadata.Priority) {
+	if result.Metadata.Description == "" || m.wins(incoming.Metadata.Priority, result.Metadata.Priority) {
 		if incoming.Metadata.Description != "" {
 			result.Metadata.Description = incoming.Metadata.Description
 		}
 	}
 
 	// Merge version (only if higher priority or empty)
-	if result.Metadata.Version == "" || incoming.Metadata.Priority.TakesPrecedenceOverOrEqual(result.Metadata.Priority) {
+	if result.Metadata.Version == "" || m.wins(incoming.Metadata.Priority, result.Metadata.Priority) {
 		if incoming.Metadata.Version != "" {
 			result.Metadata.Version = incoming.Metadata.Version
 		}
 	}
 
 	// Merge language (only if higher priority or empty)
-	if result.Metadata.Language == "" || incoming.Metadata.Priority.TakesPrecedenceOverOrEqual(result.Metadata.Priority) {
+	if result.Metadata.Language == "" || m.wins(incoming.Metadata.Priority, result.Metadata.Priority) {
 		if incoming.Metadata.Language != "" {
 			result.Metadata.Language = incoming.Metadata.Language
 		}
 	}
 
 	// Merge extends (only if higher priority or empty)
-	if result.Metadata.Extends == "" || incoming.Metadata.Priority.TakesPrecedenceOverOrEqual(result.Metadata.Priority) {
+	if result.Metadata.Extends == "" || m.wins(incoming.Metadata.Priority, result.Metadata.Priority) {
 		if incoming.Metadata.Extends != "" {
 			result.Metadata.Extends = incoming.Metadata.Extends
 		}
@@ -87,32 +346,342 @@ func (m *PriorityMerger) mergeMetadata(result *config.Config, incoming *config.C
 }
 
 // mergeSections merges sections using priority rules
-func (m *PriorityMerger) mergeSections(result *config.Config, incoming *config.Config) {
-	for name, section := range incoming.Sections {
+func (m *PriorityMerger) mergeSections(ctx context.Context, result *config.Config, incoming *config.Config) error {
+	keys, err := resolveAliases(incoming.Sections)
+	if err != nil {
+		return fmt.Errorf("%s: %w", incoming.SourceFile, err)
+	}
+	if m.FoldCase {
+		err = checkFoldedKeys(keys)
+		if err != nil {
+			return fmt.Errorf("%s: %w", incoming.SourceFile, err)
+		}
+	}
+
+	// spellings maps the keys merged under an earlier spelling to this
+	// config's own, and won holds the keys whose candidate from this config
+	// was kept
+	spellings := make(map[string]string)
+	won := make(map[string]bool)
+	for key, section := range incoming.Sections {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		// Aliased sections merge under the key they alias
+		name := keys[key]
+		if m.Debug && name != key {
+			m.debugf("Treating section %s as %s (alias)\n", key, name)
+		}
+
+		// Merge under the spelling already in result; the spelling of the
+		// winner is restored once the file is merged
+		if m.FoldCase {
+			folded := foldedKey(result.Sections, name)
+			if folded != name {
+				spellings[folded] = name
+				name = folded
+			}
+		}
+
 		existing, exists := result.Sections[name]
 
-		if !exists || section.Priority.TakesPrecedenceOverOrEqual(existing.Priority) {
-			if m.debug {
-				fmt.Printf("Merging section %s from %s\n", name, incoming.SourceFile)
+		// A final section is locked against every later candidate, whatever
+		// its priority
+		if exists && existing.Final {
+			if section.Final {
+				m.stats.Warnings = append(m.stats.Warnings, fmt.Sprintf("section %s is final in both %s and %s; keeping %s", name, m.stats.Provenance[name], incoming.SourceFile, m.stats.Provenance[name]))
+			}
+			if m.Debug {
+				m.debugf("Skipping section %s (final in %s)\n", name, m.stats.Provenance[name])
 			}
+			if m.TraceSection != "" && m.sameKey(name, m.TraceSection) {
+				m.debugf("trace %s: %s priority=%s: skipped because the section from %s is final\n", name, incoming.SourceFile, section.Priority, m.tracedSource)
+			}
+			continue
+		}
+
+		// A section with a combining strategy adds to the existing one
+		if exists && combines(existing, section) {
+			if m.Debug {
+				m.debugf("Combining section %s from %s (%s)\n", name, incoming.SourceFile, section.Strategy)
+			}
+			if m.TraceSection != "" && m.sameKey(name, m.TraceSection) {
+				m.debugf("trace %s: %s priority=%s: combined with the section from %s (strategy %s)\n", name, incoming.SourceFile, section.Priority, m.tracedSource, section.Strategy)
+				m.tracedSource = incoming.SourceFile
+			}
+			m.combineSection(result, name, section, incoming.SourceFile)
+			continue
+		}
+
+		// Let the resolver settle genuine equal-priority collisions
+		if exists && !section.Final && m.ResolveConflict != nil && isConflict(existing, section) {
+			resolution, err := m.resolveConflict(result, name, existing, section, incoming.SourceFile)
+			if err != nil {
+				return err
+			}
+			if resolution != ResolveAuto {
+				won[name] = resolution != KeepExisting
+				continue
+			}
+		}
+
+		if !exists || section.Final || m.wins(section.Priority, existing.Priority) {
+			if m.Debug {
+				m.debugf("Merging section %s from %s\n", name, incoming.SourceFile)
+			}
+			if exists && section.Final && !m.wins(section.Priority, existing.Priority) {
+				if m.TraceSection != "" && m.sameKey(name, m.TraceSection) {
+					m.debugf("trace %s: %s priority=%s: kept, overrides %s from %s because the section is final\n", name, incoming.SourceFile, section.Priority, existing.Priority, m.tracedSource)
+					m.tracedSource = incoming.SourceFile
+				}
+			} else {
+				m.traceSection(name, incoming.SourceFile, section.Priority, existing.Priority, exists, true)
+			}
+			if exists && m.MergeLists {
+				section.MergePoints = unionStrings(existing.MergePoints, section.MergePoints)
+				section.Tags = unionStrings(existing.Tags, section.Tags)
+			}
+			// Content without a heading of its own keeps the one it replaces
+			if exists && section.Heading == "" {
+				section.Heading = existing.Heading
+			}
+			// Replaced content keeps the place of what it replaces among
+			// sections of the same order
+			if exists && existing.Seq != 0 {
+				section.Seq = existing.Seq
+			}
+			if exists {
+				m.stats.Overrides++
+			}
+			m.stats.Provenance[name] = incoming.SourceFile
+			won[name] = true
+			delete(m.chunks, name)
 			result.Sections[name] = section
-		} else if m.debug {
-			fmt.Printf("Skipping section %s (lower priority)\n", name)
+		} else {
+			if m.Debug {
+				m.debugf("Skipping section %s (lower priority)\n", name)
+			}
+			m.traceSection(name, incoming.SourceFile, section.Priority, existing.Priority, exists, false)
+			if m.MergeLists {
+				existing.MergePoints = unionStrings(existing.MergePoints, section.MergePoints)
+				existing.Tags = unionStrings(existing.Tags, section.Tags)
+				result.Sections[name] = existing
+			}
 		}
 	}
+
+	for name, spelling := range spellings {
+		if won[name] {
+			m.respellSection(result, name, spelling)
+		}
+	}
+	return nil
+}
+
+// foldedKey returns the key in sections that matches name ignoring case, or
+// name itself if there is none
+func foldedKey(sections map[string]config.Section, name string) string {
+	if _, exists := sections[name]; exists {
+		return name
+	}
+	for key := range sections {
+		if strings.EqualFold(key, name) {
+			return key
+		}
+	}
+	return name
+}
+
+// checkFoldedKeys rejects a file whose section keys differ only in case,
+// since there is no priority to choose between them
+func checkFoldedKeys(keys map[string]string) error {
+	seen := make(map[string]string, len(keys))
+	for _, name := range keys {
+		folded := strings.ToLower(name)
+		if other, exists := seen[folded]; exists && other != name {
+			first, second := other, name
+			if second < first {
+				first, second = second, first
+			}
+			return fmt.Errorf("sections %s and %s differ only in case", first, second)
+		}
+		seen[folded] = name
+	}
+	return nil
+}
+
+// respellSection moves a merged section, and its provenance, to the key
+// spelling of the candidate that won it
+func (m *PriorityMerger) respellSection(result *config.Config, name, spelling string) {
+	if m.Debug {
+		m.debugf("Renaming section %s to %s (fold case)\n", name, spelling)
+	}
+	result.Sections[spelling] = result.Sections[name]
+	delete(result.Sections, name)
+	m.stats.Provenance[spelling] = m.stats.Provenance[name]
+	delete(m.stats.Provenance, name)
+	if chunks, ok := m.chunks[name]; ok {
+		m.chunks[spelling] = chunks
+		delete(m.chunks, name)
+	}
+}
+
+// isConflict reports whether two candidates for a section have equal
+// priority but different content
+func isConflict(existing, incoming config.Section) bool {
+	equal := !existing.Priority.TakesPrecedenceOver(incoming.Priority) &&
+		!incoming.Priority.TakesPrecedenceOver(existing.Priority)
+	return equal && existing.Content != incoming.Content
+}
+
+// resolveConflict asks ResolveConflict to settle a collision, applying its
+// choice to result. It returns the choice, which is ResolveAuto when the
+// resolver defers to the automatic rules.
+func (m *PriorityMerger) resolveConflict(result *config.Config, name string, existing, incoming config.Section, source string) (Resolution, error) {
+	existingSource := m.stats.Provenance[name]
+	resolution, err := m.ResolveConflict(Conflict{
+		Section:        name,
+		Existing:       existing,
+		ExistingSource: existingSource,
+		Incoming:       incoming,
+		IncomingSource: source,
+	})
+	if err != nil {
+		return ResolveAuto, fmt.Errorf("resolving section %s: %w", name, err)
+	}
+
+	switch resolution {
+	case KeepExisting:
+		if m.Debug {
+			m.debugf("Keeping section %s from %s (resolved)\n", name, existingSource)
+		}
+	case KeepIncoming:
+		if m.Debug {
+			m.debugf("Merging section %s from %s (resolved)\n", name, source)
+		}
+		m.stats.Overrides++
+		m.stats.Provenance[name] = source
+		result.Sections[name] = incoming
+	case AppendBoth:
+		if m.Debug {
+			m.debugf("Appending section %s from %s to %s (resolved)\n", name, source, existingSource)
+		}
+		incoming.Content = existing.Content + "\n\n" + incoming.Content
+		m.stats.Provenance[name] = source
+		result.Sections[name] = incoming
+	default:
+		return ResolveAuto, nil
+	}
+	return resolution, nil
+}
+
+// unionStrings returns the values of a followed by those of b, without
+// duplicates, in first-seen order
+func unionStrings(a, b []string) []string {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+
+	result := make([]string, 0, len(a)+len(b))
+	seen := make(map[string]bool, len(a)+len(b))
+	for _, values := range [][]string{a, b} {
+		for _, value := range values {
+			if !seen[value] {
+				seen[value] = true
+				result = append(result, value)
+			}
+		}
+	}
+	return result
+}
+
+// resolveAliases maps each section key to the key it merges under, following
+// alias chains. Cycles and two sections resolving to the same key are errors.
+func resolveAliases(sections map[string]config.Section) (map[string]string, error) {
+	keys := make(map[string]string, len(sections))
+	claimed := make(map[string]string, len(sections))
+
+	for key := range sections {
+		target := key
+		visited := map[string]bool{key: true}
+		for {
+			alias := sections[target].Alias
+			if alias == "" {
+				break
+			}
+			if visited[alias] {
+				return nil, fmt.Errorf("section %s has a circular alias chain through %s", key, alias)
+			}
+			visited[alias] = true
+			target = alias
+		}
+
+		if other, exists := claimed[target]; exists {
+			first, second := other, key
+			if second < first {
+				first, second = second, first
+			}
+			return nil, fmt.Errorf("sections %s and %s both resolve to %s", first, second, target)
+		}
+		claimed[target] = key
+		keys[key] = target
+	}
+
+	return keys, nil
+}
+
+// traceSection prints a single merge decision for the traced section key,
+// explaining how the incoming priority compares with the existing one
+func (m *PriorityMerger) traceSection(name, source string, incoming, existing config.Priority, exists, kept bool) {
+	if m.TraceSection == "" || !m.sameKey(name, m.TraceSection) {
+		return
+	}
+
+	prefix := fmt.Sprintf("trace %s: %s priority=%s:", name, source, incoming)
+	explanation := config.ExplainPrecedence(incoming, existing)
+	if !incoming.TakesPrecedenceOver(existing) && !existing.TakesPrecedenceOver(incoming) {
+		if m.EqualPriority == EqualPriorityFirst {
+			explanation += "; the earlier file wins ties"
+		} else {
+			explanation += "; the later file wins ties"
+		}
+	}
+	switch {
+	case !exists:
+		m.debugf("%s kept (first candidate)\n", prefix)
+	case kept:
+		m.debugf("%s kept, overrides %s from %s (%s)\n", prefix, existing, m.tracedSource, explanation)
+	default:
+		m.debugf("%s skipped in favor of %s from %s (%s)\n", prefix, existing, m.tracedSource, explanation)
+	}
+
+	if kept {
+		m.tracedSource = source
+	}
+}
+
+// sameKey reports whether two section keys name the same section, ignoring
+// case under FoldCase
+func (m *PriorityMerger) sameKey(a, b string) bool {
+	if m.FoldCase {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
 }
 
 // mergeMergePoints merges merge points using priority rules
 func (m *PriorityMerger) mergeMergePoints(result *config.Config, incoming *config.Config) {
 	for name, point := range incoming.MergePoints {
 		existing, exists := result.MergePoints[name]
-		if !exists || point.Priority.TakesPrecedenceOverOrEqual(existing.Priority) {
-			if m.debug {
-				fmt.Printf("Merging merge point %s from %s\n", name, incoming.SourceFile)
+		if !exists || m.wins(point.Priority, existing.Priority) {
+			if m.Debug {
+				m.debugf("Merging merge point %s from %s\n", name, incoming.SourceFile)
 			}
 			result.MergePoints[name] = point
-		} else if m.debug {
-			fmt.Printf("Skipping merge point %s (lower priority)\n", name)
+		} else if m.Debug {
+			m.debugf("Skipping merge point %s (lower priority)\n", name)
 		}
 	}
 }
@@ -121,47 +690,40 @@ func (m *PriorityMerger) mergeMergePoints(result *config.Config, incoming *confi
 func (m *PriorityMerger) mergeMergeTargets(result *config.Config, incoming *config.Config) {
 	for name, target := range incoming.MergeTargets {
 		existing, exists := result.MergeTargets[name]
-		if !exists || target.Priority.TakesPrecedenceOverOrEqual(existing.Priority) {
-			if m.debug {
-				fmt.Printf("Merging merge target %s from %s\n", name, incoming.SourceFile)
+		if !exists || m.wins(target.Priority, existing.Priority) {
+			if m.Debug {
+				m.debugf("Merging merge target %s from %s\n", name, incoming.SourceFile)
+			}
+			if target.Source == "" {
+				target.Source = incoming.SourceFile
 			}
 			result.MergeTargets[name] = target
-		} else if m.debug {
-			fmt.Printf("Skipping merge target %s (lower priority)\n", name)
+		} else if m.Debug {
+			m.debugf("Skipping merge target %s (lower priority)\n", name)
 		}
 	}
 }
 
-// applyPlaceholderReplacements handles special placeholder replacements for markdown
-func (m *PriorityMerger) applyPlaceholderReplacements(result *config.Config, configs []*config.Config) {
+// applyPlaceholderReplacements handles special placeholder replacements for
+// markdown. A placeholder is filled from, in order of precedence: a merge
+// target keyed by its name, content scraped by its extractor, the section
+// under the heading its name maps to by PlaceholderPatterns, the default
+// text inside the block, and otherwise nothing. A merge target's strategy
+// combines its content with what would have filled the placeholder without
+// it, so "append" adds to the extracted content and "replace" discards it.
+func (m *PriorityMerger) applyPlaceholderReplacements(ctx context.Context, result *config.Config, configs []*config.Config) error {
 	// Collect content for placeholders from all configs
-	replacements := make(map[string]string)
-
-	// Process each config to find content for placeholders
-	for _, cfg := range configs {
-		if m.debug {
-			fmt.Printf("Processing config: %s, Language: %s\n", cfg.SourceFile, cfg.Metadata.Language)
-		}
-		// Look for specific sections that might contain replacement content
-		for _, section := range cfg.Sections {
-			content := section.Content
-
-			// Extract test commands
-			if containsTestCommands(content) {
-				testCommands := extractTestCommands(content)
-				if m.debug {
-					fmt.Printf("Found test commands: %s\n", testCommands)
-				}
-				replacements["test-commands"] = testCommands
-			}
-
-			// Extract documentation standards
-			if containsDocumentationStandards(content) {
-				docStandards := extractDocumentationStandards(content)
-				if m.debug {
-					fmt.Printf("Found documentation standards: %d chars\n", len(docStandards))
-				}
-				replacements["documentation-standards"] = docStandards
+	replacements, err := m.collectReplacements(ctx, configs, runtime.GOMAXPROCS(0))
+	if err != nil {
+		return err
+	}
+	targets := m.placeholderTargets(configs)
+	names := placeholderNames(targets)
+	names = append(names, m.patternPlaceholders(result.Sections, names)...)
+	for _, name := range names {
+		if replacements[name] == "" {
+			if fill := m.patternReplacement(name, configs); fill != "" {
+				replacements[name] = fill
 			}
 		}
 	}
@@ -170,34 +732,42 @@ func (m *PriorityMerger) applyPlaceholderReplacements(result *config.Config, con
 	for name, section := range result.Sections {
 		content := section.Content
 
-		// Replace placeholder blocks (including content between tags)
-		if replacements["test-commands"] != "" {
-			content = replacePlaceholderBlock(content, 
-				"<language-specific-test-commands-here>", 
-				"</language-specific-test-commands-here>", 
-				replacements["test-commands"])
-		} else {
-			content = replacePlaceholderBlock(content, 
-				"<language-specific-test-commands-here>", 
-				"</language-specific-test-commands-here>", 
-				"")
-		}
+		// Replace placeholder blocks (including content between tags). Without
+		// a fill, the block's own inner text is kept as the default.
+		for _, placeholder := range names {
+			openTag, closeTag := placeholderTags(placeholder)
+			if !strings.Contains(content, openTag) {
+				continue
+			}
 
-		if replacements["documentation-standards"] != "" {
-			content = replacePlaceholderBlock(content, 
-				"<language-specific-documentation-standards>", 
-				"</language-specific-documentation-standards>", 
-				replacements["documentation-standards"])
-		} else {
-			content = replacePlaceholderBlock(content, 
-				"<language-specific-documentation-standards>", 
-				"</language-specific-documentation-standards>", 
-				"")
+			fill := replacements[placeholder]
+			target, hasTarget := targets[placeholder]
+			if hasTarget {
+				base := fill
+				if base == "" {
+					base = placeholderDefault(content, placeholder)
+				}
+				if m.Debug {
+					m.debugf("Filling placeholder %s from merge target in %s\n", placeholder, target.Source)
+				}
+				fill = ApplyStrategyFrom(MergeStrategy(target.Strategy), base, target.Content, target.Source)
+			}
+
+			if fill != "" {
+				m.stats.PlaceholdersFilled++
+				content = replacePlaceholderBlock(content, openTag, closeTag, fill)
+				continue
+			}
+			m.stats.Warnings = append(m.stats.Warnings, fmt.Sprintf("placeholder %s in section %s has no source content", openTag, name))
+			if !m.KeepEmptyPlaceholders {
+				content = replacePlaceholderBlock(content, openTag, closeTag, placeholderDefault(content, placeholder))
+			}
 		}
 
 		section.Content = content
 		result.Sections[name] = section
 	}
+	return nil
 }
 
 // containsTestCommands checks if content has test commands section
@@ -299,10 +869,14 @@ func containsPlaceholders(content string) bool {
 }
 
 // mergeWithTemplate handles template-based merging where base has placeholders
-func (m *PriorityMerger) mergeWithTemplate(result *config.Config, baseConfig *config.Config, configs []*config.Config) {
+func (m *PriorityMerger) mergeWithTemplate(ctx context.Context, result *config.Config, baseConfig *config.Config, configs []*config.Config) error {
 	// Start with base config
 	result.Metadata = baseConfig.Metadata
 	for name, section := range baseConfig.Sections {
+		if m.TraceSection != "" && m.sameKey(name, m.TraceSection) {
+			m.debugf("trace %s: %s priority=%s: kept (base template)\n", name, baseConfig.SourceFile, section.Priority)
+		}
+		m.stats.Provenance[name] = baseConfig.SourceFile
 		result.Sections[name] = section
 	}
 	for name, mp := range baseConfig.MergePoints {
@@ -313,7 +887,10 @@ func (m *PriorityMerger) mergeWithTemplate(result *config.Config, baseConfig *co
 	}
 
 	// Apply placeholder replacements
-	m.applyPlaceholderReplacements(result, configs)
+	err := m.applyPlaceholderReplacements(ctx, result, configs)
+	if err != nil {
+		return err
+	}
 
 	// Merge metadata from other configs if they have higher priority
 	for _, cfg := range configs {
@@ -329,6 +906,7 @@ func (m *PriorityMerger) mergeWithTemplate(result *config.Config, baseConfig *co
 			}
 		}
 	}
+	return nil
 }
 
 // replacePlaceholderBlock replaces content between opening and closing tags
@@ -338,10 +916,12 @@ func replacePlaceholderBlock(content, openTag, closeTag, replacement string) str
 		return content
 	}
 
-	endIdx := strings.Index(content, closeTag)
+	// Only a closing tag after the opening tag ends the block
+	endIdx := strings.Index(content[startIdx+len(openTag):], closeTag)
 	if endIdx == -1 {
 		return content
 	}
+	endIdx += startIdx + len(openTag)
 
 	// Replace everything from openTag to closeTag (inclusive) with replacement
 	before := content[:startIdx]
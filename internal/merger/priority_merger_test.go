@@ -1,7 +1,16 @@
 package merger
 
 import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/arustydev/claude-merge/internal/config"
 	"github.com/stretchr/testify/assert"
@@ -192,6 +201,52 @@ func TestPriorityMerger_MergeAll_AdditiveSections(t *testing.T) {
 	assert.Equal(t, "Content 2", result.Sections["section2"].Content)
 }
 
+func TestPriorityMerger_MergeAll_KeepsHeading(t *testing.T) {
+	base := &config.Config{
+		Sections: map[string]config.Section{
+			"header_2_rules": {Content: "## RULES!!\nOld", Heading: "## RULES!!"},
+			"header_2_setup": {Content: "## Setup\nOld", Heading: "## Setup"},
+		},
+	}
+	override := &config.Config{
+		Sections: map[string]config.Section{
+			"header_2_rules": {Content: "New"},
+			"header_2_setup": {Content: "## Set up\nNew", Heading: "## Set up"},
+		},
+	}
+
+	merger := NewPriorityMerger(false)
+	result, err := merger.MergeAll([]*config.Config{base, override})
+	require.NoError(t, err)
+
+	assert.Equal(t, "New", result.Sections["header_2_rules"].Content)
+	assert.Equal(t, "## RULES!!", result.Sections["header_2_rules"].Heading, "content without a heading keeps the replaced one")
+	assert.Equal(t, "## Set up", result.Sections["header_2_setup"].Heading)
+}
+
+func TestPriorityMerger_MergeAll_KeepsSeq(t *testing.T) {
+	base := &config.Config{
+		Sections: map[string]config.Section{
+			"rules": {Content: "Old", Seq: 1},
+			"setup": {Content: "Setup", Seq: 2},
+		},
+	}
+	override := &config.Config{
+		Sections: map[string]config.Section{
+			"rules": {Content: "New", Seq: 5},
+			"extra": {Content: "Extra", Seq: 6},
+		},
+	}
+
+	merger := NewPriorityMerger(false)
+	result, err := merger.MergeAll([]*config.Config{base, override})
+	require.NoError(t, err)
+
+	assert.Equal(t, "New", result.Sections["rules"].Content)
+	assert.Equal(t, int64(1), result.Sections["rules"].Seq, "replaced content keeps its place")
+	assert.Equal(t, int64(6), result.Sections["extra"].Seq)
+}
+
 func TestPriorityMerger_MergeAll_ExplicitBeatsRelative(t *testing.T) {
 	config1 := &config.Config{
 		Sections: map[string]config.Section{
@@ -250,9 +305,755 @@ func TestPriorityMerger_MergeAll_MetadataFields(t *testing.T) {
 func TestNewPriorityMerger(t *testing.T) {
 	merger := NewPriorityMerger(true)
 	assert.NotNil(t, merger)
-	assert.True(t, merger.debug)
+	assert.True(t, merger.Debug)
 
 	merger2 := NewPriorityMerger(false)
 	assert.NotNil(t, merger2)
-	assert.False(t, merger2.debug)
-}
\ No newline at end of file
+	assert.False(t, merger2.Debug)
+}
+func TestPriorityMerger_MergeAll_KeepEmptyPlaceholders(t *testing.T) {
+	base := &config.Config{
+		Sections: map[string]config.Section{
+			"content": {
+				Content: "# Base\n<language-specific-test-commands-here>\nTODO\n</language-specific-test-commands-here>",
+			},
+		},
+	}
+
+	merger := NewPriorityMerger(false)
+	result, err := merger.MergeAll([]*config.Config{base})
+	require.NoError(t, err)
+	assert.NotContains(t, result.Sections["content"].Content, "<language-specific-test-commands-here>")
+	assert.Equal(t, "# Base\nTODO", result.Sections["content"].Content, "inline default is kept without its tags")
+
+	merger = NewPriorityMerger(false)
+	merger.KeepEmptyPlaceholders = true
+	result, err = merger.MergeAll([]*config.Config{base})
+	require.NoError(t, err)
+	assert.Equal(t, base.Sections["content"].Content, result.Sections["content"].Content)
+}
+
+func TestPriorityMerger_ApplyDefaults(t *testing.T) {
+	config1 := &config.Config{
+		Metadata: config.Metadata{Title: "Merged Title"},
+		Sections: map[string]config.Section{
+			"license": {Content: "Real license"},
+		},
+	}
+
+	defaults := &config.Config{
+		Metadata: config.Metadata{
+			Title:   "Default Title",
+			Version: "0.0.1",
+		},
+		Sections: map[string]config.Section{
+			"license": {
+				Content:  "Default license",
+				Priority: config.NewExplicitPriority(100),
+			},
+			"security": {Content: "Default security"},
+		},
+		MergeTargets: map[string]config.MergeTarget{
+			"test-commands": {Content: "go test ./..."},
+		},
+	}
+
+	merger := NewPriorityMerger(false)
+	result, err := merger.MergeAll([]*config.Config{config1})
+	require.NoError(t, err)
+	merger.ApplyDefaults(result, defaults)
+
+	// Defaults never override merged content, even with a higher priority
+	assert.Equal(t, "Merged Title", result.Metadata.Title)
+	assert.Equal(t, "Real license", result.Sections["license"].Content)
+
+	// Missing keys are filled from the defaults
+	assert.Equal(t, "0.0.1", result.Metadata.Version)
+	assert.Equal(t, "Default security", result.Sections["security"].Content)
+	assert.Equal(t, "go test ./...", result.MergeTargets["test-commands"].Content)
+}
+
+func TestPriorityMerger_MergeAll_Conditions(t *testing.T) {
+	config1 := &config.Config{
+		Metadata: config.Metadata{Language: "go"},
+		Sections: map[string]config.Section{
+			"common": {Content: "Always here"},
+			"go":     {Content: "Go only", Condition: "language == go"},
+			"python": {Content: "Python only", Condition: "language == python"},
+			"not-go": {Content: "Not Go", Condition: "language != go"},
+		},
+	}
+
+	merger := NewPriorityMerger(false)
+	result, err := merger.MergeAll([]*config.Config{config1})
+	require.NoError(t, err)
+
+	assert.Contains(t, result.Sections, "common")
+	assert.Contains(t, result.Sections, "go")
+	assert.NotContains(t, result.Sections, "python")
+	assert.NotContains(t, result.Sections, "not-go")
+}
+
+func TestPriorityMerger_MergeAll_InvalidCondition(t *testing.T) {
+	config1 := &config.Config{
+		Sections: map[string]config.Section{
+			"broken": {Content: "Broken", Condition: "language ~ go"},
+		},
+	}
+
+	merger := NewPriorityMerger(false)
+	_, err := merger.MergeAll([]*config.Config{config1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "section broken")
+}
+
+func TestPriorityMerger_MergeAllContext_Cancelled(t *testing.T) {
+	configs := []*config.Config{
+		{Sections: map[string]config.Section{"section1": {Content: "Content"}}},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	merger := NewPriorityMerger(false)
+	_, err := merger.MergeAllContext(ctx, configs)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// Template mode checks the context too
+	template := []*config.Config{
+		{Sections: map[string]config.Section{
+			"content": {Content: "<language-specific-test-commands-here>\n</language-specific-test-commands-here>"},
+		}},
+	}
+	_, err = merger.MergeAllContext(ctx, template)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestPriorityMerger_MergeFiles(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.toml")
+	override := filepath.Join(dir, "override.toml")
+	require.NoError(t, os.WriteFile(base, []byte("[sections.a]\ncontent = \"base\"\n"), 0644))
+	require.NoError(t, os.WriteFile(override, []byte("[sections.a]\ncontent = \"override\"\n"), 0644))
+
+	merger := NewPriorityMerger(false)
+	result, err := merger.MergeFiles(context.Background(), []string{base, override})
+	require.NoError(t, err)
+	assert.Equal(t, "override", result.Sections["a"].Content)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = merger.MergeFiles(ctx, []string{base, override})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestPriorityMerger_MergeFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"bases/base.toml":     {Data: []byte("[sections.a]\ncontent = \"base\"\n[sections.b]\ncontent_file = \"b.md\"\n")},
+		"bases/b.md":          {Data: []byte("from b.md\n")},
+		"bases/override.yaml": {Data: []byte("sections:\n  a:\n    content: override\n")},
+	}
+
+	merger := NewPriorityMerger(false)
+	result, err := merger.MergeFS(context.Background(), fsys, []string{"bases/base.toml", "bases/override.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, "override", result.Sections["a"].Content)
+	assert.Equal(t, "from b.md", result.Sections["b"].Content)
+
+	_, err = merger.MergeFS(context.Background(), fsys, []string{"bases/missing.toml"})
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestPriorityMerger_TraceSection(t *testing.T) {
+	configs := []*config.Config{
+		{
+			SourceFile: "a.toml",
+			Sections: map[string]config.Section{
+				"testing": {Content: "A", Priority: config.NewExplicitPriority(5)},
+				"other":   {Content: "Other"},
+			},
+		},
+		{
+			SourceFile: "b.toml",
+			Sections: map[string]config.Section{
+				"testing": {Content: "B"},
+			},
+		},
+		{
+			SourceFile: "c.toml",
+			Sections: map[string]config.Section{
+				"testing": {Content: "C", Priority: config.NewExplicitPriority(10)},
+			},
+		},
+		{
+			SourceFile: "d.toml",
+			Sections: map[string]config.Section{
+				"testing": {Content: "D", Priority: config.NewExplicitPriority(10)},
+			},
+		},
+	}
+
+	var output bytes.Buffer
+	merger := NewPriorityMerger(false)
+	merger.TraceSection = "testing"
+	merger.DebugOutput = &output
+
+	_, err := merger.MergeAll(configs)
+	require.NoError(t, err)
+
+	assert.Equal(t, "trace testing: a.toml priority=explicit(5): kept (first candidate)\n"+
+		"trace testing: b.toml priority=none: skipped in favor of explicit(5) from a.toml (none loses to explicit(5) because explicit always wins)\n"+
+		"trace testing: c.toml priority=explicit(10): kept, overrides explicit(5) from a.toml (explicit(10) beats explicit(5) because the higher value wins)\n"+
+		"trace testing: d.toml priority=explicit(10): kept, overrides explicit(10) from c.toml (explicit(10) ties with explicit(10) because the values are equal; the later file wins ties)\n", output.String())
+}
+
+func TestPriorityMerger_TraceSection_TemplateFoldCase(t *testing.T) {
+	configs := []*config.Config{
+		{
+			SourceFile: "common.md",
+			Sections: map[string]config.Section{
+				"testing": {Content: "<language-specific-test-commands-here>\n</language-specific-test-commands-here>"},
+			},
+		},
+	}
+
+	var output bytes.Buffer
+	merger := NewPriorityMergerWithOptions(Options{FoldCase: true, TraceSection: "Testing"})
+	merger.DebugOutput = &output
+
+	_, err := merger.MergeAll(configs)
+	require.NoError(t, err)
+
+	assert.Contains(t, output.String(), "trace testing: common.md priority=none: kept (base template)\n")
+}
+
+func TestPriorityMerger_DebugOutputDefaultsToStderr(t *testing.T) {
+	configs := []*config.Config{
+		{SourceFile: "a.toml", Sections: map[string]config.Section{"testing": {Content: "A"}}},
+	}
+
+	stdout := captureFile(t, &os.Stdout, func() {
+		stderr := captureFile(t, &os.Stderr, func() {
+			_, err := NewPriorityMerger(true).MergeAll(configs)
+			require.NoError(t, err)
+		})
+		assert.Contains(t, stderr, "Merging section testing from a.toml")
+	})
+	assert.Empty(t, stdout)
+}
+
+// captureFile returns everything fn writes to *file, such as os.Stdout
+func captureFile(t *testing.T, file **os.File, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	original := *file
+	*file = w
+	defer func() { *file = original }()
+
+	fn()
+	require.NoError(t, w.Close())
+
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestPriorityMerger_MergeAll_Alias(t *testing.T) {
+	config1 := &config.Config{
+		Sections: map[string]config.Section{
+			"setup": {Content: "Team A setup"},
+		},
+	}
+
+	config2 := &config.Config{
+		Sections: map[string]config.Section{
+			"getting_started": {Content: "Team B setup", Alias: "setup"},
+		},
+	}
+
+	merger := NewPriorityMerger(false)
+	result, err := merger.MergeAll([]*config.Config{config1, config2})
+	require.NoError(t, err)
+
+	assert.Len(t, result.Sections, 1)
+	assert.Equal(t, "Team B setup", result.Sections["setup"].Content)
+}
+
+func TestResolveAliases(t *testing.T) {
+	tests := []struct {
+		name     string
+		sections map[string]config.Section
+		want     map[string]string
+		errMsg   string
+	}{
+		{
+			name: "no aliases",
+			sections: map[string]config.Section{
+				"a": {}, "b": {},
+			},
+			want: map[string]string{"a": "a", "b": "b"},
+		},
+		{
+			name: "chained aliases collide",
+			sections: map[string]config.Section{
+				"a": {Alias: "b"},
+				"b": {Alias: "c"},
+			},
+			errMsg: "sections a and b both resolve to c",
+		},
+		{
+			name: "alias chain through missing key",
+			sections: map[string]config.Section{
+				"a": {Alias: "b"},
+				"x": {Alias: "y"},
+			},
+			want: map[string]string{"a": "b", "x": "y"},
+		},
+		{
+			name: "alias cycle",
+			sections: map[string]config.Section{
+				"a": {Alias: "b"},
+				"b": {Alias: "a"},
+			},
+			errMsg: "circular alias chain",
+		},
+		{
+			name: "two sections alias the same key",
+			sections: map[string]config.Section{
+				"setup":           {},
+				"getting_started": {Alias: "setup"},
+			},
+			errMsg: "sections getting_started and setup both resolve to setup",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveAliases(tt.sections)
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPriorityMerger_MergeAll_MergeLists(t *testing.T) {
+	configs := func() []*config.Config {
+		return []*config.Config{
+			{Sections: map[string]config.Section{
+				"section1": {Content: "First", MergePoints: []string{"a", "b"}, Tags: []string{"go"}},
+			}},
+			{Sections: map[string]config.Section{
+				"section1": {Content: "Second", MergePoints: []string{"b", "c"}, Tags: []string{"ci", "go"}, Priority: config.NewExplicitPriority(5)},
+			}},
+			{Sections: map[string]config.Section{
+				"section1": {Content: "Low", MergePoints: []string{"d"}, Tags: []string{"lint"}, Priority: config.NewExplicitPriority(1)},
+			}},
+		}
+	}
+
+	// Replace is the default
+	merger := NewPriorityMerger(false)
+	result, err := merger.MergeAll(configs()[:2])
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, result.Sections["section1"].MergePoints)
+	assert.Equal(t, []string{"ci", "go"}, result.Sections["section1"].Tags)
+
+	merger = NewPriorityMerger(false)
+	merger.MergeLists = true
+	result, err = merger.MergeAll(configs())
+	require.NoError(t, err)
+
+	// Lists are unioned even from candidates that lose on priority
+	assert.Equal(t, "Second", result.Sections["section1"].Content)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, result.Sections["section1"].MergePoints)
+	assert.Equal(t, []string{"go", "ci", "lint"}, result.Sections["section1"].Tags)
+}
+
+func TestPriorityMerger_MergeAll_EqualPriority(t *testing.T) {
+	newConfigs := func() []*config.Config {
+		return []*config.Config{
+			{
+				Metadata: config.Metadata{Title: "First"},
+				Sections: map[string]config.Section{
+					"intro": {Order: 1, Content: "First intro"},
+				},
+				MergePoints: map[string]config.MergePoint{
+					"point": {Placeholder: "<!-- P -->", Default: "first"},
+				},
+				MergeTargets: map[string]config.MergeTarget{
+					"target": {Strategy: "replace", Content: "first"},
+				},
+			},
+			{
+				Metadata: config.Metadata{Title: "Second"},
+				Sections: map[string]config.Section{
+					"intro": {Order: 1, Content: "Second intro"},
+				},
+				MergePoints: map[string]config.MergePoint{
+					"point": {Placeholder: "<!-- P -->", Default: "second"},
+				},
+				MergeTargets: map[string]config.MergeTarget{
+					"target": {Strategy: "replace", Content: "second"},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		policy EqualPriorityPolicy
+		want   string
+	}{
+		{name: "default is last", policy: "", want: "Second"},
+		{name: "last", policy: EqualPriorityLast, want: "Second"},
+		{name: "first", policy: EqualPriorityFirst, want: "First"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewPriorityMerger(false)
+			m.EqualPriority = tt.policy
+
+			result, err := m.MergeAll(newConfigs())
+			require.NoError(t, err)
+
+			word := strings.ToLower(tt.want)
+			assert.Equal(t, tt.want, result.Metadata.Title)
+			assert.Equal(t, tt.want+" intro", result.Sections["intro"].Content)
+			assert.Equal(t, word, result.MergePoints["point"].Default)
+			assert.Equal(t, word, result.MergeTargets["target"].Content)
+		})
+	}
+}
+
+func TestPriorityMerger_EqualPriorityFirst_HigherStillWins(t *testing.T) {
+	m := NewPriorityMerger(false)
+	m.EqualPriority = EqualPriorityFirst
+
+	configs := []*config.Config{
+		{Sections: map[string]config.Section{"intro": {Content: "First"}}},
+		{Sections: map[string]config.Section{"intro": {Content: "Second", Priority: config.Priority{Type: config.PriorityRelative, Value: 1}}}},
+	}
+
+	result, err := m.MergeAll(configs)
+	require.NoError(t, err)
+	assert.Equal(t, "Second", result.Sections["intro"].Content)
+}
+
+func TestEqualPriorityPolicy_IsValid(t *testing.T) {
+	assert.True(t, EqualPriorityLast.IsValid())
+	assert.True(t, EqualPriorityFirst.IsValid())
+	assert.False(t, EqualPriorityPolicy("middle").IsValid())
+	assert.False(t, EqualPriorityPolicy("").IsValid())
+}
+
+func TestPriorityMerger_MergeAll_PlaceholderInlineDefault(t *testing.T) {
+	base := &config.Config{
+		Sections: map[string]config.Section{
+			"content": {
+				Content: "# Base\n<language-specific-test-commands-here>\nRun the tests.\n</language-specific-test-commands-here>\nEnd",
+			},
+		},
+	}
+	lang := &config.Config{
+		Sections: map[string]config.Section{
+			"testing": {Content: "### Testing commands\n- go test ./..."},
+		},
+	}
+
+	m := NewPriorityMerger(false)
+	result, err := m.MergeAll([]*config.Config{base})
+	require.NoError(t, err)
+	assert.Equal(t, "# Base\nRun the tests.\nEnd", result.Sections["content"].Content)
+
+	result, err = m.MergeAll([]*config.Config{base, lang})
+	require.NoError(t, err)
+	assert.Contains(t, result.Sections["content"].Content, "go test ./...")
+	assert.NotContains(t, result.Sections["content"].Content, "Run the tests.")
+}
+
+func TestReplacePlaceholderBlock(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "block", content: "a<x>old</x>b", want: "aNEWb"},
+		{name: "no open tag", content: "a</x>b", want: "a</x>b"},
+		{name: "no close tag", content: "a<x>b", want: "a<x>b"},
+		{name: "close before open", content: "</x>a<x>b", want: "</x>a<x>b"},
+		{name: "stray close before block", content: "</x>a<x>old</x>b", want: "</x>aNEWb"},
+		{name: "nested tags", content: "<x><x>old</x></x>", want: "NEW</x>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, replacePlaceholderBlock(tt.content, "<x>", "</x>", "NEW"))
+		})
+	}
+}
+
+func FuzzReplacePlaceholderBlock(f *testing.F) {
+	f.Add("a<x>old</x>b", "<x>", "</x>", "new")
+	f.Add("</x><x>", "<x>", "</x>", "new")
+	f.Add("<x></x>", "<x>", "<x>", "")
+	f.Add("<language-specific-a><language-specific-a></language-specific-a>", "<language-specific-a>", "</language-specific-a>", "fill")
+
+	f.Fuzz(func(t *testing.T, content, openTag, closeTag, replacement string) {
+		result := replacePlaceholderBlock(content, openTag, closeTag, replacement)
+		if !strings.Contains(content, openTag) {
+			assert.Equal(t, content, result)
+		}
+		assert.LessOrEqual(t, len(result), len(content)+len(replacement))
+	})
+}
+
+func TestPriorityMerger_MergeMetadata(t *testing.T) {
+	configs := []*config.Config{
+		{
+			Metadata: config.Metadata{Title: "Base", Description: "Base description", Version: "1.0", Priority: config.NewExplicitPriority(5)},
+			Sections: map[string]config.Section{"intro": {Content: "<!-- GO -->"}},
+		},
+		{
+			Metadata: config.Metadata{Title: "Low", Version: "0.1", Language: "go", Priority: config.NewExplicitPriority(1)},
+		},
+		{
+			Metadata: config.Metadata{Title: "High", Priority: config.NewExplicitPriority(10)},
+		},
+	}
+
+	m := NewPriorityMerger(false)
+	metadata, err := m.MergeMetadata(configs)
+	require.NoError(t, err)
+	assert.Equal(t, config.Metadata{
+		Title:       "High",
+		Description: "Base description",
+		Version:     "1.0",
+		Language:    "go",
+		Priority:    config.NewExplicitPriority(10),
+	}, metadata)
+
+	_, err = m.MergeMetadata(nil)
+	assert.Error(t, err)
+}
+
+func TestPriorityMerger_VersionPolicy(t *testing.T) {
+	newConfigs := func(versions ...string) []*config.Config {
+		configs := []*config.Config{
+			{Metadata: config.Metadata{Title: "Base", Version: versions[0], Priority: config.NewExplicitPriority(10)}},
+		}
+		for _, version := range versions[1:] {
+			configs = append(configs, &config.Config{Metadata: config.Metadata{Version: version}})
+		}
+		return configs
+	}
+
+	tests := []struct {
+		name     string
+		policy   VersionPolicy
+		versions []string
+		want     string
+	}{
+		{name: "priority by default", versions: []string{"1.0.0", "1.4.0"}, want: "1.0.0"},
+		{name: "priority", policy: VersionPriority, versions: []string{"1.0.0", "1.4.0"}, want: "1.0.0"},
+		{name: "highest semver", policy: VersionHighestSemver, versions: []string{"1.0.0", "v1.10.0", "1.9.2"}, want: "v1.10.0"},
+		{name: "release beats pre-release", policy: VersionHighestSemver, versions: []string{"2.0.0-rc.1", "2.0.0"}, want: "2.0.0"},
+		{name: "invalid versions are ignored", policy: VersionHighestSemver, versions: []string{"latest", "1.2.0", "9"}, want: "1.2.0"},
+		{name: "no semver falls back to priority", policy: VersionHighestSemver, versions: []string{"latest", "next"}, want: "latest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewPriorityMerger(false)
+			m.VersionPolicy = tt.policy
+
+			merged, err := m.MergeAll(newConfigs(tt.versions...))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, merged.Metadata.Version)
+
+			metadata, err := m.MergeMetadata(newConfigs(tt.versions...))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, metadata.Version)
+		})
+	}
+}
+
+func TestVersionPolicy_IsValid(t *testing.T) {
+	assert.True(t, VersionPriority.IsValid())
+	assert.True(t, VersionHighestSemver.IsValid())
+	assert.False(t, VersionPolicy("").IsValid())
+	assert.False(t, VersionPolicy("newest").IsValid())
+}
+
+func TestPriorityMerger_FoldCase(t *testing.T) {
+	relative := func(value int) config.Priority {
+		return config.Priority{Type: config.PriorityRelative, Value: value}
+	}
+	newConfigs := func(lowerPriority config.Priority) []*config.Config {
+		return []*config.Config{
+			{SourceFile: "a.toml", Sections: map[string]config.Section{"Testing": {Content: "## Testing", Priority: relative(5)}}},
+			{SourceFile: "b.toml", Sections: map[string]config.Section{"testing": {Content: "## testing", Priority: lowerPriority}}},
+			{SourceFile: "c.toml", Sections: map[string]config.Section{"TESTING": {Content: "## TESTING"}}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		fold     bool
+		priority config.Priority
+		wantKeys []string
+		wantKey  string
+		wantFrom string
+	}{
+		{name: "exact keys stay apart", priority: relative(1), wantKeys: []string{"TESTING", "Testing", "testing"}},
+		{name: "earlier winner keeps its spelling", fold: true, priority: relative(1), wantKeys: []string{"Testing"}, wantKey: "Testing", wantFrom: "a.toml"},
+		{name: "later winner brings its spelling", fold: true, priority: relative(9), wantKeys: []string{"testing"}, wantKey: "testing", wantFrom: "b.toml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewPriorityMerger(false)
+			m.FoldCase = tt.fold
+
+			merged, err := m.MergeAllResult(context.Background(), newConfigs(tt.priority))
+			require.NoError(t, err)
+
+			var keys []string
+			for key := range merged.Config.Sections {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			assert.Equal(t, tt.wantKeys, keys)
+
+			if tt.wantKey != "" {
+				assert.Equal(t, "## "+tt.wantKey, merged.Config.Sections[tt.wantKey].Content)
+				assert.Equal(t, map[string]string{tt.wantKey: tt.wantFrom}, merged.Provenance)
+			}
+		})
+	}
+}
+
+func TestPriorityMerger_FoldCase_SameFileConfigs(t *testing.T) {
+	// Configs from one JSON array share a source file, so the winner's
+	// spelling can't be told by file name
+	configs := []*config.Config{
+		{SourceFile: "all.json", Sections: map[string]config.Section{"Testing": {Content: "## Testing", Priority: config.NewExplicitPriority(5)}}},
+		{SourceFile: "all.json", Sections: map[string]config.Section{"testing": {Content: "## testing"}}},
+	}
+
+	m := NewPriorityMerger(false)
+	m.FoldCase = true
+	merged, err := m.MergeAll(configs)
+	require.NoError(t, err)
+
+	require.Len(t, merged.Sections, 1)
+	assert.Equal(t, "## Testing", merged.Sections["Testing"].Content)
+}
+
+func TestPriorityMerger_FoldCase_SameFileCollision(t *testing.T) {
+	m := NewPriorityMerger(false)
+	m.FoldCase = true
+
+	configs := []*config.Config{
+		{SourceFile: "a.toml", Sections: map[string]config.Section{"Testing": {Content: "A"}, "testing": {Content: "B"}}},
+	}
+
+	_, err := m.MergeAll(configs)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "a.toml: sections Testing and testing differ only in case")
+}
+
+func TestPriorityMerger_NoPlaceholders(t *testing.T) {
+	docs := "Wrap text in <language-specific-test-commands-here>\nexample\n</language-specific-test-commands-here> tags."
+	configs := []*config.Config{
+		{SourceFile: "docs.md", Metadata: config.Metadata{Title: "Docs"}, Sections: map[string]config.Section{"usage": {Order: 1, Content: docs}}},
+		{SourceFile: "go.md", Metadata: config.Metadata{Title: "Go"}, Sections: map[string]config.Section{"testing": {Order: 2, Content: "### Testing commands\n- go test ./..."}}},
+	}
+
+	m := NewPriorityMerger(false)
+	m.NoPlaceholders = true
+
+	result, err := m.MergeAll(configs)
+	require.NoError(t, err)
+	assert.Equal(t, "Go", result.Metadata.Title, "standard merging lets the later title win")
+	assert.Equal(t, docs, result.Sections["usage"].Content)
+	assert.Contains(t, result.Sections, "testing")
+
+	m = NewPriorityMerger(false)
+	result, err = m.MergeAll(configs)
+	require.NoError(t, err)
+	assert.Equal(t, "Docs", result.Metadata.Title, "template mode keeps the base title")
+	assert.NotContains(t, result.Sections, "testing")
+}
+
+func TestPriorityMerger_FinalSection(t *testing.T) {
+	compliance := func(source, content string, priority config.Priority, final bool) *config.Config {
+		return &config.Config{
+			SourceFile: source,
+			Sections: map[string]config.Section{
+				"compliance": {Order: 1, Content: content, Priority: priority, Final: final},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		configs  []*config.Config
+		content  string
+		source   string
+		warnings []string
+	}{
+		{
+			name: "final beats a later higher priority",
+			configs: []*config.Config{
+				compliance("base.toml", "Locked", config.NewExplicitPriority(0), true),
+				compliance("team.toml", "Override", config.NewExplicitPriority(100), false),
+			},
+			content: "Locked",
+			source:  "base.toml",
+		},
+		{
+			name: "final replaces an earlier higher priority",
+			configs: []*config.Config{
+				compliance("team.toml", "Team", config.NewExplicitPriority(100), false),
+				compliance("base.toml", "Locked", config.NewExplicitPriority(0), true),
+			},
+			content: "Locked",
+			source:  "base.toml",
+		},
+		{
+			name: "first final wins",
+			configs: []*config.Config{
+				compliance("a.toml", "A", config.NewExplicitPriority(0), true),
+				compliance("b.toml", "B", config.NewExplicitPriority(10), true),
+			},
+			content:  "A",
+			source:   "a.toml",
+			warnings: []string{"section compliance is final in both a.toml and b.toml; keeping a.toml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewPriorityMerger(false)
+			m.ResolveConflict = func(Conflict) (Resolution, error) {
+				t.Fatal("final sections never reach the resolver")
+				return ResolveAuto, nil
+			}
+
+			result, err := m.MergeAllResult(context.Background(), tt.configs)
+			require.NoError(t, err)
+			assert.Equal(t, tt.content, result.Config.Sections["compliance"].Content)
+			assert.Equal(t, tt.source, result.Provenance["compliance"])
+			assert.Equal(t, tt.warnings, result.Warnings)
+		})
+	}
+}
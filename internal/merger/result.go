@@ -0,0 +1,23 @@
+package merger
+
+import "github.com/arustydev/claude-merge/internal/config"
+
+// MergeResult describes the outcome of a merge
+type MergeResult struct {
+	// Config is the merged configuration
+	Config *config.Config
+
+	// Provenance maps each merged section key to the source file whose
+	// content won
+	Provenance map[string]string
+
+	// Overrides counts the sections that replaced an earlier candidate
+	Overrides int
+
+	// PlaceholdersFilled counts the placeholder blocks replaced with content
+	// from another input
+	PlaceholdersFilled int
+
+	// Warnings lists non-fatal problems found while merging
+	Warnings []string
+}
@@ -0,0 +1,71 @@
+package merger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityMerger_MergeAllResult(t *testing.T) {
+	configs := []*config.Config{
+		{
+			SourceFile: "base.toml",
+			Sections: map[string]config.Section{
+				"intro":   {Content: "Base intro"},
+				"testing": {Content: "Base testing", Priority: config.Priority{Type: config.PriorityExplicit, Value: 10}},
+			},
+		},
+		{
+			SourceFile: "team.toml",
+			Sections: map[string]config.Section{
+				"intro":   {Content: "Team intro"},
+				"testing": {Content: "Team testing"},
+				"release": {Content: "Team release", Condition: "language == rust"},
+			},
+		},
+	}
+
+	m := NewPriorityMerger(false)
+	result, err := m.MergeAllResult(context.Background(), configs)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Team intro", result.Config.Sections["intro"].Content)
+	assert.Equal(t, map[string]string{
+		"intro":   "team.toml",
+		"testing": "base.toml",
+	}, result.Provenance, "dropped sections have no provenance")
+	assert.Equal(t, 1, result.Overrides)
+	assert.Zero(t, result.PlaceholdersFilled)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestPriorityMerger_MergeAllResult_Placeholders(t *testing.T) {
+	configs := []*config.Config{
+		{
+			SourceFile: "common.md",
+			Sections: map[string]config.Section{
+				"content": {Content: "<language-specific-test-commands-here>\n</language-specific-test-commands-here>\n" +
+					"<language-specific-documentation-standards>\n</language-specific-documentation-standards>"},
+			},
+		},
+		{
+			SourceFile: "go.md",
+			Sections: map[string]config.Section{
+				"content": {Content: "### Testing commands\n- go test ./..."},
+			},
+		},
+	}
+
+	m := NewPriorityMerger(false)
+	result, err := m.MergeAllResult(context.Background(), configs)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"content": "common.md"}, result.Provenance)
+	assert.Equal(t, 1, result.PlaceholdersFilled)
+	assert.Equal(t, []string{
+		"placeholder <language-specific-documentation-standards> in section content has no source content",
+	}, result.Warnings)
+}
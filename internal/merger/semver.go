@@ -0,0 +1,125 @@
+package merger
+
+import (
+	"strconv"
+	"strings"
+)
+
+// semver is a parsed semantic version; build metadata is dropped because it
+// does not affect precedence
+type semver struct {
+	major, minor, patch int
+	prerelease          []string
+}
+
+// parseSemver parses a semantic version such as "1.2.3", "v1.2.3", or
+// "1.2.3-rc.1+build.5"
+func parseSemver(version string) (semver, bool) {
+	v := strings.TrimPrefix(version, "v")
+	v, _, _ = strings.Cut(v, "+")
+	v, pre, hasPre := strings.Cut(v, "-")
+
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return semver{}, false
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		n, ok := parseNumericIdentifier(part)
+		if !ok {
+			return semver{}, false
+		}
+		numbers[i] = n
+	}
+
+	parsed := semver{major: numbers[0], minor: numbers[1], patch: numbers[2]}
+	if hasPre {
+		parsed.prerelease = strings.Split(pre, ".")
+		for _, id := range parsed.prerelease {
+			if id == "" || strings.Trim(id, "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ-") != "" {
+				return semver{}, false
+			}
+			if isNumeric(id) && len(id) > 1 && id[0] == '0' {
+				return semver{}, false
+			}
+		}
+	}
+	return parsed, true
+}
+
+// IsSemver reports whether version is a valid semantic version, optionally
+// prefixed with "v"
+func IsSemver(version string) bool {
+	_, ok := parseSemver(version)
+	return ok
+}
+
+// compare returns -1, 0, or 1 as s has lower, equal, or higher precedence
+// than other
+func (s semver) compare(other semver) int {
+	for _, pair := range [][2]int{{s.major, other.major}, {s.minor, other.minor}, {s.patch, other.patch}} {
+		if pair[0] != pair[1] {
+			return compareInts(pair[0], pair[1])
+		}
+	}
+
+	// A pre-release has lower precedence than the release itself
+	switch {
+	case len(s.prerelease) == 0 && len(other.prerelease) == 0:
+		return 0
+	case len(s.prerelease) == 0:
+		return 1
+	case len(other.prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(s.prerelease) && i < len(other.prerelease); i++ {
+		a, b := s.prerelease[i], other.prerelease[i]
+		if a == b {
+			continue
+		}
+		aNum, bNum := isNumeric(a), isNumeric(b)
+		switch {
+		case aNum && bNum:
+			x, _ := strconv.Atoi(a)
+			y, _ := strconv.Atoi(b)
+			return compareInts(x, y)
+		case aNum:
+			return -1
+		case bNum:
+			return 1
+		case a < b:
+			return -1
+		default:
+			return 1
+		}
+	}
+	return compareInts(len(s.prerelease), len(other.prerelease))
+}
+
+// parseNumericIdentifier parses a version number without leading zeros
+func parseNumericIdentifier(s string) (int, bool) {
+	if !isNumeric(s) || (len(s) > 1 && s[0] == '0') {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
+
+// isNumeric reports whether s is a non-empty run of digits
+func isNumeric(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+// compareInts returns -1, 0, or 1 as a is less than, equal to, or greater
+// than b
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,66 @@
+package merger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSemver(t *testing.T) {
+	tests := []struct {
+		version string
+		valid   bool
+	}{
+		{"1.2.3", true},
+		{"v1.2.3", true},
+		{"0.0.0", true},
+		{"1.2.3-rc.1", true},
+		{"1.2.3-alpha-1+build.5", true},
+		{"1.2.3+20250102", true},
+		{"1.2", false},
+		{"1.2.3.4", false},
+		{"01.2.3", false},
+		{"1.2.3-01", false},
+		{"1.2.3-", false},
+		{"1.2.3-rc..1", false},
+		{"1.2.x", false},
+		{"latest", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.valid, IsSemver(tt.version))
+		})
+	}
+}
+
+func TestSemver_Compare(t *testing.T) {
+	// Each version has lower precedence than the next, per the semver spec
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.9.2",
+		"v1.10.0",
+		"2.0.0",
+	}
+
+	for i := range ordered {
+		for j := range ordered {
+			a, okA := parseSemver(ordered[i])
+			b, okB := parseSemver(ordered[j])
+			assert.True(t, okA && okB)
+			assert.Equal(t, compareInts(i, j), a.compare(b), "%s vs %s", ordered[i], ordered[j])
+		}
+	}
+
+	a, _ := parseSemver("1.0.0+build.1")
+	b, _ := parseSemver("1.0.0+build.2")
+	assert.Zero(t, a.compare(b), "build metadata does not affect precedence")
+}
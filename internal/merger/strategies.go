@@ -1,5 +1,7 @@
 package merger
 
+import "github.com/arustydev/claude-merge/internal/config"
+
 // MergeStrategy defines how content should be combined
 type MergeStrategy string
 
@@ -12,34 +14,61 @@ const (
 
 	// StrategyPrepend means the new content is added before the old
 	StrategyPrepend MergeStrategy = "prepend"
+
+	// StrategyCollapse means the new content is added after the old inside a
+	// collapsible <details> block labeled with its source file
+	StrategyCollapse MergeStrategy = "collapse"
+
+	// StrategyKeyValue means old and new are read as "Key: value" lines and
+	// merged by key: a key in new replaces that line in old, keys only in new
+	// are added at the end, and other lines pass through
+	StrategyKeyValue MergeStrategy = "keyvalue"
 )
 
-// IsValid checks if a strategy string is valid
+// Strategies returns all built-in merge strategies
+func Strategies() []MergeStrategy {
+	return []MergeStrategy{StrategyReplace, StrategyAppend, StrategyPrepend, StrategyCollapse, StrategyKeyValue}
+}
+
+// IsValid checks if a strategy string is valid: a built-in strategy or one
+// added with RegisterStrategy
 func (s MergeStrategy) IsValid() bool {
 	switch s {
-	case StrategyReplace, StrategyAppend, StrategyPrepend:
+	case StrategyReplace, StrategyAppend, StrategyPrepend, StrategyCollapse, StrategyKeyValue:
 		return true
 	default:
-		return false
+		_, ok := config.RegisteredStrategy(string(s))
+		return ok
 	}
 }
 
+// RegisterStrategy adds a merge strategy called name that combines old and
+// new content with fn, for domain-specific merging such as combining JSON
+// blocks. The name can then be used wherever a built-in strategy can: in a
+// section's or merge target's strategy field and in -collapse-strategy. A
+// section with a registered strategy is combined with the one it meets, like
+// append. Registered strategies are consulted before the built-in ones, so
+// registering a built-in name overrides it, and registering a name again
+// replaces the earlier function.
+//
+// RegisterStrategy is safe for concurrent use, including while merges run,
+// but a merge may then see the strategy either before or after the change;
+// register strategies before merging, typically from an init function. fn
+// may be called from several goroutines at once. It panics if name is empty
+// or fn is nil.
+func RegisterStrategy(name string, fn func(old, new string) string) {
+	config.RegisterStrategy(name, fn)
+}
+
 // ApplyStrategy applies a merge strategy to combine old and new content
 func ApplyStrategy(strategy MergeStrategy, old, new string) string {
-	switch strategy {
-	case StrategyReplace:
-		return new
-	case StrategyAppend:
-		if old == "" {
-			return new
-		}
-		return old + "\n" + new
-	case StrategyPrepend:
-		if old == "" {
-			return new
-		}
-		return new + "\n" + old
-	default:
-		return new // Default to replace
-	}
+	return ApplyStrategyFrom(strategy, old, new, "")
+}
+
+// ApplyStrategyFrom applies a merge strategy like ApplyStrategy, where new
+// content came from the file source. Only StrategyCollapse uses the source,
+// as the label of the block it wraps new content in; registered strategies
+// don't see it.
+func ApplyStrategyFrom(strategy MergeStrategy, old, new, source string) string {
+	return config.CombineContent(string(strategy), old, new, source)
 }
\ No newline at end of file
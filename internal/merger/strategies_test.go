@@ -1,9 +1,13 @@
 package merger
 
 import (
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/arustydev/claude-merge/internal/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMergeStrategy_IsValid(t *testing.T) {
@@ -14,6 +18,8 @@ func TestMergeStrategy_IsValid(t *testing.T) {
 		{StrategyReplace, true},
 		{StrategyAppend, true},
 		{StrategyPrepend, true},
+		{StrategyCollapse, true},
+		{StrategyKeyValue, true},
 		{"invalid", false},
 		{"", false},
 	}
@@ -64,6 +70,8 @@ func TestMergeStrategy_String(t *testing.T) {
 		{StrategyReplace, "replace"},
 		{StrategyAppend, "append"},
 		{StrategyPrepend, "prepend"},
+		{StrategyCollapse, "collapse"},
+		{StrategyKeyValue, "keyvalue"},
 	}
 
 	for _, tt := range tests {
@@ -71,4 +79,232 @@ func TestMergeStrategy_String(t *testing.T) {
 			assert.Equal(t, tt.expected, string(tt.strategy))
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestStrategies_AcceptedByConfigValidation(t *testing.T) {
+	// config duplicates the strategy names to avoid an import cycle, so make
+	// sure every built-in strategy is accepted there too
+	for _, strategy := range Strategies() {
+		t.Run(string(strategy), func(t *testing.T) {
+			assert.True(t, strategy.IsValid())
+
+			cfg := &config.Config{
+				Metadata: config.Metadata{Title: "Test"},
+				Sections: map[string]config.Section{"test": {Content: "content"}},
+				MergeTargets: map[string]config.MergeTarget{
+					"target": {Strategy: string(strategy)},
+				},
+			}
+			assert.NoError(t, config.ValidateConfig(cfg))
+		})
+	}
+}
+
+func TestApplyStrategyFrom_Collapse(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		source   string
+		expected string
+	}{
+		{
+			name:     "base content stays unwrapped",
+			old:      "Base notes",
+			new:      "Extra notes",
+			source:   "go.toml",
+			expected: "Base notes\n<details>\n<summary>go.toml</summary>\n\nExtra notes\n\n</details>",
+		},
+		{
+			name:     "no base content",
+			new:      "Extra notes",
+			source:   "go.toml",
+			expected: "<details>\n<summary>go.toml</summary>\n\nExtra notes\n\n</details>",
+		},
+		{
+			name:     "source is escaped",
+			new:      "Extra",
+			source:   "a<b>.md",
+			expected: "<details>\n<summary>a&lt;b&gt;.md</summary>\n\nExtra\n\n</details>",
+		},
+		{
+			name:     "unknown source",
+			new:      "Extra",
+			expected: "<details>\n<summary>More</summary>\n\nExtra\n\n</details>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ApplyStrategyFrom(StrategyCollapse, tt.old, tt.new, tt.source))
+		})
+	}
+
+	// Other strategies ignore the source
+	assert.Equal(t, "old\nnew", ApplyStrategyFrom(StrategyAppend, "old", "new", "go.toml"))
+}
+
+func TestPriorityMerger_MergeTargetSource(t *testing.T) {
+	configs := []*config.Config{
+		{
+			SourceFile: "common.md",
+			MergeTargets: map[string]config.MergeTarget{
+				"notes": {Strategy: "collapse", Content: "common"},
+			},
+		},
+		{
+			SourceFile: "go.toml",
+			MergeTargets: map[string]config.MergeTarget{
+				"notes": {Strategy: "collapse", Content: "go"},
+			},
+		},
+	}
+
+	merged, err := NewPriorityMerger(false).MergeAll(configs)
+	require.NoError(t, err)
+	assert.Equal(t, "go.toml", merged.MergeTargets["notes"].Source)
+}
+
+func TestApplyStrategy_KeyValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		expected string
+	}{
+		{
+			name:     "update keeps order",
+			old:      "Timeout: 10s\nRetries: 3\nHost: db",
+			new:      "Retries: 5",
+			expected: "Timeout: 10s\nRetries: 5\nHost: db",
+		},
+		{
+			name:     "new keys appended in order",
+			old:      "Timeout: 10s",
+			new:      "Zone: b\nTimeout: 30s\nRegion: eu",
+			expected: "Timeout: 30s\nZone: b\nRegion: eu",
+		},
+		{
+			name:     "last value wins within new",
+			old:      "Timeout: 10s",
+			new:      "Timeout: 20s\nTimeout: 30s",
+			expected: "Timeout: 30s",
+		},
+		{
+			name:     "other lines pass through",
+			old:      "## Settings\n\nTimeout: 10s\nSee https://example.com for details",
+			new:      "## Settings\n\nTimeout: 30s\nAsk in #ops before changing these",
+			expected: "## Settings\n\nTimeout: 30s\nSee https://example.com for details\nAsk in #ops before changing these",
+		},
+		{
+			name:     "list items and empty values",
+			old:      "- Owner: team-a\n- Notes:",
+			new:      "- Notes: keep it short",
+			expected: "- Owner: team-a\n- Notes: keep it short",
+		},
+		{
+			name:     "empty old",
+			old:      "",
+			new:      "Timeout: 30s",
+			expected: "Timeout: 30s",
+		},
+		{
+			name:     "empty new",
+			old:      "Timeout: 10s",
+			new:      "",
+			expected: "Timeout: 10s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ApplyStrategy(StrategyKeyValue, tt.old, tt.new))
+		})
+	}
+}
+
+// unregisterStrategy removes the test strategy name from the global
+// registry when the test finishes, so it can't leak into other tests
+func unregisterStrategy(t *testing.T, name string) {
+	t.Cleanup(func() { config.UnregisterStrategy(name) })
+}
+
+func TestRegisterStrategy(t *testing.T) {
+	unregisterStrategy(t, "test_union")
+	// Joins old and new, dropping lines already present
+	RegisterStrategy("test_union", func(old, new string) string {
+		lines := strings.Split(old, "\n")
+		for _, line := range strings.Split(new, "\n") {
+			if !containsString(lines, line) {
+				lines = append(lines, line)
+			}
+		}
+		return strings.Join(lines, "\n")
+	})
+
+	strategy := MergeStrategy("test_union")
+	assert.True(t, strategy.IsValid())
+	assert.False(t, MergeStrategy("test_unregistered").IsValid())
+	assert.Equal(t, "- a\n- b\n- c", ApplyStrategy(strategy, "- a\n- b", "- b\n- c"))
+
+	cfg := &config.Config{
+		Metadata:     config.Metadata{Title: "Test"},
+		Sections:     map[string]config.Section{"test": {Content: "content"}},
+		MergeTargets: map[string]config.MergeTarget{"target": {Strategy: "test_union"}},
+	}
+	assert.NoError(t, config.ValidateConfig(cfg), "registered strategies pass config validation")
+
+	configs := []*config.Config{
+		fragment("base.md", "- a\n- b", "", config.Priority{}),
+		fragment("extra.toml", "- b\n- c", "test_union", config.Priority{}),
+	}
+	result, err := NewPriorityMerger(false).MergeAll(configs)
+	require.NoError(t, err)
+	assert.Equal(t, "- a\n- b\n- c", result.Sections["rules"].Content, "a registered strategy combines sections")
+}
+
+func TestRegisterStrategy_Overrides(t *testing.T) {
+	unregisterStrategy(t, "test_override")
+	RegisterStrategy("test_override", func(old, new string) string { return "first" })
+	RegisterStrategy("test_override", func(old, new string) string { return old + "|" + new })
+
+	assert.Equal(t, "a|b", ApplyStrategy("test_override", "a", "b"), "registering again replaces the function")
+}
+
+func TestRegisterStrategy_Invalid(t *testing.T) {
+	assert.Panics(t, func() { RegisterStrategy("", func(old, new string) string { return new }) })
+	assert.Panics(t, func() { RegisterStrategy("test_nil", nil) })
+	assert.False(t, MergeStrategy("test_nil").IsValid())
+}
+
+func TestRegisterStrategy_Concurrent(t *testing.T) {
+	unregisterStrategy(t, "test_concurrent")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			RegisterStrategy("test_concurrent", func(old, new string) string { return new + old })
+		}()
+		go func() {
+			defer wg.Done()
+			MergeStrategy("test_concurrent").IsValid()
+			ApplyStrategy("test_concurrent", "a", "b")
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, "ba", ApplyStrategy("test_concurrent", "a", "b"))
+}
+
+func TestUnregisterStrategy(t *testing.T) {
+	RegisterStrategy("test_removed", func(old, new string) string { return new })
+	config.UnregisterStrategy("test_removed")
+	assert.False(t, MergeStrategy("test_removed").IsValid())
+
+	unregisterStrategy(t, string(StrategyAppend))
+	builtIn := ApplyStrategy(StrategyAppend, "a", "b")
+	RegisterStrategy(string(StrategyAppend), func(old, new string) string { return "custom" })
+	config.UnregisterStrategy(string(StrategyAppend))
+	assert.Equal(t, builtIn, ApplyStrategy(StrategyAppend, "a", "b"), "the built-in strategy is back")
+}
@@ -0,0 +1,40 @@
+package merger
+
+import "github.com/arustydev/claude-merge/internal/config"
+
+// keepsTags reports whether a section carrying tags passes WithTags and
+// WithoutTags. An untagged section always passes.
+func (m *PriorityMerger) keepsTags(tags []string) bool {
+	if len(tags) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		if containsString(m.WithoutTags, tag) {
+			return false
+		}
+	}
+	if len(m.WithTags) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		if containsString(m.WithTags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyTagFilters removes the sections that don't pass WithTags and
+// WithoutTags
+func (m *PriorityMerger) applyTagFilters(result *config.Config) {
+	for name, section := range result.Sections {
+		if m.keepsTags(section.Tags) {
+			continue
+		}
+		if m.Debug {
+			m.debugf("Dropping section %s (tags %v filtered out)\n", name, section.Tags)
+		}
+		delete(result.Sections, name)
+		delete(m.stats.Provenance, name)
+	}
+}
@@ -0,0 +1,81 @@
+package merger
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityMerger_KeepsTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		with    []string
+		without []string
+		tags    []string
+		want    bool
+	}{
+		{name: "no filters", tags: []string{"internal"}, want: true},
+		{name: "untagged with filter", with: []string{"public"}, want: true},
+		{name: "untagged without filter", without: []string{"internal"}, want: true},
+		{name: "with matches", with: []string{"public"}, tags: []string{"beta", "public"}, want: true},
+		{name: "with misses", with: []string{"public"}, tags: []string{"internal"}, want: false},
+		{name: "without matches", without: []string{"internal"}, tags: []string{"public", "internal"}, want: false},
+		{name: "without misses", without: []string{"internal"}, tags: []string{"public"}, want: true},
+		{name: "without wins", with: []string{"public"}, without: []string{"beta"}, tags: []string{"public", "beta"}, want: false},
+		{name: "case sensitive", with: []string{"public"}, tags: []string{"Public"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewPriorityMergerWithOptions(Options{WithTags: tt.with, WithoutTags: tt.without})
+			assert.Equal(t, tt.want, m.keepsTags(tt.tags))
+		})
+	}
+}
+
+func TestPriorityMerger_TagFilters(t *testing.T) {
+	configs := []*config.Config{
+		{
+			SourceFile: "base.toml",
+			Sections: map[string]config.Section{
+				"intro":  {Content: "Intro"},
+				"keys":   {Content: "Deploy keys", Tags: []string{"internal"}},
+				"public": {Content: "Old", Tags: []string{"internal"}},
+			},
+		},
+		{
+			SourceFile: "override.toml",
+			Sections: map[string]config.Section{
+				"public": {Content: "New", Tags: []string{"public"}},
+			},
+		},
+	}
+
+	m := NewPriorityMergerWithOptions(Options{WithoutTags: []string{"internal"}})
+	result, err := m.MergeAllResult(context.Background(), configs)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"intro", "public"}, sectionKeys(result.Config))
+	assert.Equal(t, "New", result.Config.Sections["public"].Content, "the winner's tags are checked")
+	assert.NotContains(t, result.Provenance, "keys")
+
+	m.ApplyDefaults(result.Config, &config.Config{Sections: map[string]config.Section{
+		"secret":  {Content: "Secret", Tags: []string{"internal"}},
+		"license": {Content: "MIT"},
+	}})
+	assert.Equal(t, []string{"intro", "license", "public"}, sectionKeys(result.Config))
+}
+
+// sectionKeys returns the sorted section keys of cfg
+func sectionKeys(cfg *config.Config) []string {
+	keys := make([]string, 0, len(cfg.Sections))
+	for key := range cfg.Sections {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,94 @@
+// Package report renders human-readable tables describing configurations
+// and merge results.
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/arustydev/claude-merge/internal/gitinfo"
+	"github.com/arustydev/claude-merge/internal/merger"
+)
+
+// RenderTable writes rows as aligned, space-padded columns. The first row is
+// treated like any other, so callers include their own header row.
+func RenderTable(w io.Writer, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		_, err := fmt.Fprintln(tw, strings.Join(row, "\t"))
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// SectionRows describes every section of the given configs, one row per
+// section, preceded by a header row. Rows follow the config order, then
+// section order, read order, and key.
+func SectionRows(configs []*config.Config) [][]string {
+	rows := [][]string{{"FILE", "SECTION", "ORDER", "PRIORITY", "LENGTH"}}
+
+	for _, cfg := range configs {
+		for _, key := range config.SortedSectionKeys(cfg.Sections) {
+			section := cfg.Sections[key]
+			rows = append(rows, []string{
+				cfg.SourceFile,
+				key,
+				strconv.Itoa(section.Order),
+				section.Priority.String(),
+				strconv.Itoa(len(section.Content)),
+			})
+		}
+	}
+
+	return rows
+}
+
+// ProvenanceRows describes which source file each merged section came from,
+// one row per section sorted by key, preceded by a header row
+func ProvenanceRows(provenance map[string]string) [][]string {
+	keys := make([]string, 0, len(provenance))
+	for key := range provenance {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	rows := [][]string{{"SECTION", "SOURCE"}}
+	for _, key := range keys {
+		rows = append(rows, []string{key, provenance[key]})
+	}
+	return rows
+}
+
+// GitProvenanceRows is ProvenanceRows with the git path and last commit of
+// each source file, and the commit checked out in its repository. Sources
+// without git information show "-".
+func GitProvenanceRows(provenance map[string]string, infos map[string]gitinfo.Info) [][]string {
+	rows := ProvenanceRows(provenance)
+	rows[0] = append(rows[0], "GIT", "HEAD")
+	for i := 1; i < len(rows); i++ {
+		info, ok := infos[rows[i][1]]
+		if ok {
+			rows[i] = append(rows[i], info.String(), gitinfo.Short(info.Head))
+		} else {
+			rows[i] = append(rows[i], "-", "-")
+		}
+	}
+	return rows
+}
+
+// PlaceholderRows describes where each placeholder appears, one row per use
+// in the given order, preceded by a header row
+func PlaceholderRows(uses []merger.PlaceholderUse) [][]string {
+	rows := [][]string{{"PLACEHOLDER", "SECTION", "FILE"}}
+	for _, use := range uses {
+		rows = append(rows, []string{use.Name, use.Section, use.File})
+	}
+	return rows
+}
@@ -0,0 +1,96 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/arustydev/claude-merge/internal/config"
+	"github.com/arustydev/claude-merge/internal/gitinfo"
+	"github.com/arustydev/claude-merge/internal/merger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTable(t *testing.T) {
+	var buf bytes.Buffer
+	err := RenderTable(&buf, [][]string{
+		{"FILE", "SECTION"},
+		{"common.md", "content"},
+		{"a.toml", "testing"},
+	})
+	require.NoError(t, err)
+
+	expected := "FILE       SECTION\n" +
+		"common.md  content\n" +
+		"a.toml     testing\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestSectionRows(t *testing.T) {
+	configs := []*config.Config{
+		{
+			SourceFile: "base.toml",
+			Sections: map[string]config.Section{
+				"second": {Order: 2, Content: "two"},
+				"first":  {Order: 1, Content: "first", Priority: config.NewExplicitPriority(10)},
+			},
+		},
+		{
+			SourceFile: "lang.yaml",
+			Sections: map[string]config.Section{
+				"testing": {Order: 1, Content: "go test", Priority: config.NewRelativePriority(5)},
+			},
+		},
+	}
+
+	rows := SectionRows(configs)
+
+	assert.Equal(t, [][]string{
+		{"FILE", "SECTION", "ORDER", "PRIORITY", "LENGTH"},
+		{"base.toml", "first", "1", "explicit(10)", "5"},
+		{"base.toml", "second", "2", "none", "3"},
+		{"lang.yaml", "testing", "1", "relative(5)", "7"},
+	}, rows)
+}
+
+func TestProvenanceRows(t *testing.T) {
+	rows := ProvenanceRows(map[string]string{
+		"testing": "team.toml",
+		"intro":   "base.toml",
+	})
+
+	assert.Equal(t, [][]string{
+		{"SECTION", "SOURCE"},
+		{"intro", "base.toml"},
+		{"testing", "team.toml"},
+	}, rows)
+}
+
+func TestGitProvenanceRows(t *testing.T) {
+	head := "0123456789abcdef0123456789abcdef01234567"
+	rows := GitProvenanceRows(map[string]string{
+		"testing": "team.toml",
+		"intro":   "base.toml",
+	}, map[string]gitinfo.Info{
+		"base.toml": {Head: head, Commit: "fedcba9876543210fedcba9876543210fedcba98", Path: "config/base.toml"},
+	})
+
+	assert.Equal(t, [][]string{
+		{"SECTION", "SOURCE", "GIT", "HEAD"},
+		{"intro", "base.toml", "config/base.toml@fedcba987654", "0123456789ab"},
+		{"testing", "team.toml", "-", "-"},
+	}, rows)
+}
+
+func TestPlaceholderRows(t *testing.T) {
+	rows := PlaceholderRows([]merger.PlaceholderUse{
+		{Name: "commands", Section: "testing", File: "base.md"},
+		{Name: "test-commands-here", Section: "content", File: "base.md"},
+	})
+
+	assert.Equal(t, [][]string{
+		{"PLACEHOLDER", "SECTION", "FILE"},
+		{"commands", "testing", "base.md"},
+		{"test-commands-here", "content", "base.md"},
+	}, rows)
+}